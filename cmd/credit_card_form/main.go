@@ -2,9 +2,7 @@ package credit_card_form //nolint:revive,stylecheck
 
 import (
 	"context"
-	"fmt"
 	"strconv"
-	"strings"
 
 	"github.com/rprtr258/tea"
 	"github.com/rprtr258/tea/components/textinput"
@@ -17,6 +15,8 @@ const (
 	_cvv
 )
 
+var cvvMask = textinput.NewMask("999")
+
 var (
 	inputStyle    = styles.Style{}.Foreground(styles.FgColor("#FF06B7")) // hot pink
 	continueStyle = styles.Style{}.Foreground(styles.FgColor("#767676")) // dark gray
@@ -27,78 +27,39 @@ type model struct {
 	focused int
 }
 
-// Validator functions to ensure valid input
-func ccnValidator(s string) error {
-	// Credit Card Number should a string less than 20 digits
-	// It should include 16 integers and 3 spaces
-	if len(s) > 16+3 {
-		return fmt.Errorf("CCN is too long")
-	}
-
-	if s == "" || len(s)%5 != 0 && (s[len(s)-1] < '0' || s[len(s)-1] > '9') {
-		return fmt.Errorf("CCN is invalid")
-	}
-
-	// The last digit should be a number unless it is a multiple of 4 in which
-	// case it should be a space
-	if len(s)%5 == 0 && s[len(s)-1] != ' ' {
-		return fmt.Errorf("CCN must separate groups with spaces")
+// expCheck checks that expiration date is complete and month is valid
+func expCheck(s string) error {
+	if err := textinput.MaskExpiry.Validate(s); err != nil {
+		return err
 	}
 
-	// The remaining digits should be integers
-	c := strings.ReplaceAll(s, " ", "")
-	_, err := strconv.ParseInt(c, 10, 64)
-
-	return err
-}
-
-func expValidator(s string) error {
-	// The 3 character should be a slash (/)
-	// The rest should be numbers
-	e := strings.ReplaceAll(s, "/", "")
-
-	if _, err := strconv.ParseInt(e, 10, 64); err != nil {
-		return fmt.Errorf("EXP is invalid")
-	}
-
-	// There should be only one slash and it should be in the 2nd index (3rd character)
-	if len(s) >= 3 && (strings.Index(s, "/") != 2 || strings.LastIndex(s, "/") != 2) {
-		return fmt.Errorf("EXP is invalid")
-	}
-
-	return nil
-}
-
-func cvvValidator(s string) error {
-	// The CVV should be a number of 3 digits
-	// Since the input will already ensure that the CVV is a string of length 3,
-	// All we need to do is check that it is a number
-	_, err := strconv.ParseInt(s, 10, 64)
-	return err
+	month, _ := strconv.Atoi(s[:2])
+	return textinput.InRange(1, 12)(month)
 }
 
 func initialModel() *model {
 	ccnInput := textinput.New()
 	ccnInput.Placeholder = "4505 **** **** 1234"
 	ccnInput.Focus()
-	ccnInput.CharLimit = 20
 	ccnInput.Width = 30
 	ccnInput.Prompt = ""
-	ccnInput.Validate = ccnValidator
+	ccnInput.Mask = textinput.MaskCreditCard
+	ccnInput.Check = textinput.MaskCreditCard.Validate
+	ccnInput.ShowError = true
 
 	expInput := textinput.New()
 	expInput.Placeholder = "MM/YY "
-	expInput.CharLimit = 5
 	expInput.Width = 5
 	expInput.Prompt = ""
-	expInput.Validate = expValidator
+	expInput.Mask = textinput.MaskExpiry
+	expInput.Check = expCheck
 
 	cvvInput := textinput.New()
 	cvvInput.Placeholder = "XXX"
-	cvvInput.CharLimit = 3
 	cvvInput.Width = 5
 	cvvInput.Prompt = ""
-	cvvInput.Validate = cvvValidator
+	cvvInput.Mask = cvvMask
+	cvvInput.Check = cvvMask.Validate
 
 	return &model{
 		inputs: []textinput.Model{
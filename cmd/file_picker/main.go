@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/rprtr258/tea"
@@ -33,6 +34,10 @@ func (m *model) Init(yield func(...tea.Cmd)) {
 func (m *model) Update(msg tea.Msg, yield func(...tea.Cmd)) {
 	switch msg := msg.(type) {
 	case tea.MsgKey:
+		if m.filepicker.Finding() {
+			break
+		}
+
 		switch msg.String() {
 		case "ctrl+c", "q":
 			m.quitting = true
@@ -45,10 +50,11 @@ func (m *model) Update(msg tea.Msg, yield func(...tea.Cmd)) {
 
 	yield(m.filepicker.Update(msg)...)
 
-	// Did the user select a file?
-	if didSelect, path := m.filepicker.DidSelectFile(msg); didSelect {
-		// Get the path of the selected file.
-		m.selectedFile = path
+	// Did the user select files? These are marked ones or the highlighted one.
+	if didSelect, paths := m.filepicker.DidSelectFiles(msg); didSelect {
+		// Get the paths of the selected files.
+		m.selectedFile = strings.Join(paths, ", ")
+		m.filepicker.ClearMarks()
 	}
 
 	// Did the user select a disabled file?
@@ -83,12 +89,16 @@ func Main(ctx context.Context) error {
 	fp := filepicker.New()
 	fp.AllowedTypes = []string{".mod", ".sum", ".go", ".txt", ".md"}
 	fp.CurrentDirectory, _ = os.UserHomeDir()
+	fp.MultiSelect = true
+	fp.Preview = true
+	fp.Watch = true
 
 	m := &model{
 		filepicker: fp,
 	}
 
 	mm, err := tea.NewProgram(ctx, m).WithOutput(os.Stderr).Run()
+	m.filepicker.Close()
 	if err != nil {
 		return err
 	}
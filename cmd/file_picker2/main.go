@@ -5,105 +5,94 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/rprtr258/fun"
-	"github.com/rprtr258/fun/iter"
 	"github.com/rprtr258/tea"
 	"github.com/rprtr258/tea/components/headless/hierachy"
-	"github.com/rprtr258/tea/styles"
+	"github.com/rprtr258/tea/components/tree"
 )
 
 type dirEntry struct {
 	fullpath string
 	name     string
+	size     int64
 	perms    fs.FileMode
 }
 
-func readDir(path string) hierachy.Node[dirEntry] {
-	// info, _ := os.Stat(path)
-	subdirs, _ := os.ReadDir(path)
-	return hierachy.Node[dirEntry]{
-		Value: dirEntry{
-			fullpath: path,
-			name:     filepath.Base(path),
-			// perms: info.Mode(),
-		},
-		Children: fun.FilterMap[hierachy.Node[dirEntry]](func(e fs.DirEntry) (hierachy.Node[dirEntry], bool) {
-			if strings.HasPrefix(e.Name(), ".git") {
-				return hierachy.Node[dirEntry]{}, false
-			}
-			return readDir(filepath.Join(path, e.Name())), true
-		}, subdirs...),
+// readDir reads entries of directory, subdirectories are read when expanded.
+func readDir(entry dirEntry) ([]hierachy.Node[dirEntry], error) {
+	entries, err := os.ReadDir(entry.fullpath)
+	if err != nil {
+		return nil, err
 	}
+
+	return fun.FilterMap[hierachy.Node[dirEntry]](func(e fs.DirEntry) (hierachy.Node[dirEntry], bool) {
+		if strings.HasPrefix(e.Name(), ".git") {
+			return hierachy.Node[dirEntry]{}, false
+		}
+
+		info, err := e.Info()
+		if err != nil {
+			return hierachy.Node[dirEntry]{}, false
+		}
+
+		return hierachy.Node[dirEntry]{
+			Value: dirEntry{
+				fullpath: filepath.Join(entry.fullpath, e.Name()),
+				name:     e.Name(),
+				size:     info.Size(),
+				perms:    info.Mode(),
+			},
+			Lazy: e.IsDir(),
+		}, true
+	}, entries...), nil
 }
 
 type model struct {
-	tree *hierachy.Hierachy[dirEntry]
+	tree tree.Model[dirEntry]
 }
 
 func (m *model) Init(yield func(...tea.Cmd)) {}
 
 func (m *model) Update(msg tea.Msg, yield func(...tea.Cmd)) {
-	switch msg := msg.(type) {
-	case tea.MsgKey:
+	if msg, ok := msg.(tea.MsgKey); ok && !m.tree.Filtering() {
 		switch msg.String() {
 		case "ctrl+c", "q":
 			yield(tea.Quit)
-		case "j":
-			m.tree.GoNextOrUp()
-		case "k":
-			m.tree.GoPrevOrUp()
-		case "h":
-			if m.tree.IsCollapsed() {
-				m.tree.GoUp()
-			} else {
-				m.tree.ToggleCollapsed()
-			}
-		case "l":
-			if m.tree.IsCollapsed() {
-				m.tree.ToggleCollapsed()
-			} else {
-				m.tree.GoDown()
-			}
+			return
 		}
 	}
+	m.tree.Update(msg, yield)
 }
 
 func (m *model) View(vb tea.Viewbox) {
-	selected := 0
-	m.tree.Iter(func(i hierachy.IterItem[dirEntry]) bool {
-		selected++
-		return !i.IsSelected
-	})
-
-	height := vb.Height
-	iter.Skip(m.tree.Iter, max(0, selected-height/2))(func(i hierachy.IterItem[dirEntry]) bool {
-		vbItem := vb.PaddingLeft(i.Depth * 2)
-		if i.IsSelected {
-			vbItem = vbItem.Styled(styles.Style{}.Foreground(styles.FgColor("170")))
-		}
-		if i.HasChildren {
-			vbArrow := vbItem.Styled(styles.Style{}.Foreground(styles.FgColor("169")))
-			if i.IsCollapsed {
-				vbArrow.WriteLineX(">")
-			} else {
-				vbArrow.WriteLineX("∨")
-			}
-			vbItem = vbItem.PaddingLeft(1)
-		}
-		vbItem.WriteLine(i.Value.name)
-
-		vb = vb.PaddingTop(1)
-		height--
-		return height != 0
-	})
+	m.tree.View(vb)
 }
 
 func Main(ctx context.Context) error {
-	m := &model{
-		tree: hierachy.New(readDir(".")),
+	root := dirEntry{fullpath: ".", name: "."}
+	children, err := readDir(root)
+	if err != nil {
+		return err
 	}
-	_, err := tea.NewProgram(ctx, m).WithOutput(os.Stderr).Run()
+
+	t := tree.New(hierachy.Node[dirEntry]{Value: root, Children: children}, func(e dirEntry) string {
+		return e.name
+	})
+	t.Load = readDir
+	t.Header = "Name"
+	t.Columns = []tree.Column[dirEntry]{
+		{Title: "Mode", Get: func(e dirEntry) string { return e.perms.String() }},
+		{Title: "Size", Get: func(e dirEntry) string {
+			if e.perms.IsDir() {
+				return ""
+			}
+			return strconv.FormatInt(e.size, 10)
+		}},
+	}
+
+	_, err = tea.NewProgram(ctx, &model{t}).WithOutput(os.Stderr).WithMouseCellMotion().Run()
 	return err
 }
@@ -0,0 +1,125 @@
+package form
+
+// An example of a multi-page form filling a struct, with dropdowns,
+// checkboxes, typed inputs and cross-field validation.
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/rprtr258/tea"
+	"github.com/rprtr258/tea/components/form"
+	"github.com/rprtr258/tea/components/headless/selector"
+	"github.com/rprtr258/tea/components/headless/selector_multiple"
+	"github.com/rprtr258/tea/components/textinput"
+)
+
+type order struct {
+	Name     string
+	Email    string
+	Tea      string
+	Cups     int
+	Toppings []string
+	Notes    string
+	Delivery bool
+	Address  string
+}
+
+type model struct {
+	form form.Model[order]
+}
+
+func initialModel(o *order) *model {
+	name := form.Input("Name", &o.Name)
+	name.Check = func(s string) error {
+		if s == "" {
+			return form.ErrRequired
+		}
+		return nil
+	}
+
+	cups := form.Parsed("Cups", &o.Cups, textinput.Int().Where(textinput.InRange(1, 10)), "1")
+
+	address := form.Input("Address", &o.Address)
+	address.Input.Placeholder = "only for delivery"
+
+	teas := form.Select("Tea", &o.Tea,
+		selector.Item[string]{Label: "Taro", Value: "taro"},
+		selector.Item[string]{Label: "Coffee", Value: "coffee"},
+		selector.Item[string]{Label: "Lychee", Value: "lychee"},
+	)
+	teas.Required = true
+
+	toppings := form.MultiSelect("Toppings", &o.Toppings,
+		selector_multiple.Item[string]{Label: "Boba", Value: "boba"},
+		selector_multiple.Item[string]{Label: "Jelly", Value: "jelly"},
+		selector_multiple.Item[string]{Label: "Pudding", Value: "pudding"},
+	)
+	toppings.Max = 2
+
+	f := form.New(o,
+		form.Group{
+			Title:  "Contacts",
+			Fields: []form.Field{name, form.Input("Email", &o.Email)},
+		},
+		form.Group{
+			Title: "Order",
+			Fields: []form.Field{
+				teas,
+				cups,
+				toppings,
+				form.Text("Notes", &o.Notes),
+				form.Confirm("Delivery", &o.Delivery),
+				address,
+				form.Submit("Order"),
+				form.Cancel("Cancel"),
+			},
+		},
+	)
+	f.Validate = func(o order) error {
+		if o.Delivery && o.Address == "" {
+			return &form.FieldError{Field: address, Err: errors.New("address is required for delivery")}
+		}
+		return nil
+	}
+
+	return &model{form: f}
+}
+
+func (m *model) Init(f func(...tea.Cmd)) {
+	f(m.form.Focus()...)
+}
+
+func (m *model) Update(msg tea.Msg, f func(...tea.Cmd)) {
+	switch msg := msg.(type) {
+	case tea.MsgKey:
+		if msg.Type == tea.KeyCtrlC {
+			f(tea.Quit)
+			return
+		}
+	case form.MsgSubmitted[order], form.MsgAborted:
+		f(tea.Quit)
+		return
+	}
+
+	m.form.Update(msg, f)
+}
+
+func (m *model) View(vb tea.Viewbox) {
+	m.form.View(vb.PaddingLeft(1))
+}
+
+func Main(ctx context.Context) error {
+	var o order
+	m, err := tea.NewProgram(ctx, initialModel(&o)).Run()
+	if err != nil {
+		return err
+	}
+
+	if m.form.State() == form.StateSubmitted {
+		fmt.Printf("\n---\n%d cup(s) of %s with %v for %s\n", o.Cups, o.Tea, o.Toppings, o.Name)
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package log_viewer //nolint:revive,stylecheck
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/rprtr258/tea"
+	"github.com/rprtr258/tea/components/logview"
+)
+
+// generate sends log lines in various formats at irregular interval.
+func generate(ctx context.Context, lines chan<- string) {
+	defer close(lines)
+
+	levels := []string{"debug", "info", "info", "info", "warn", "error"}
+	for i := 0; ; i++ {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Duration(rand.Int63n(300)+50) * time.Millisecond): //nolint:gosec
+		}
+
+		level := levels[rand.Intn(len(levels))] //nolint:gosec
+		now := time.Now().Format(time.RFC3339)
+		switch i % 3 {
+		case 0:
+			lines <- fmt.Sprintf(`{"time":%q,"level":%q,"msg":"request handled","id":%d}`, now, level, i)
+		case 1:
+			lines <- fmt.Sprintf(`time=%s level=%s msg="job done" id=%d`, now, level, i)
+		default:
+			lines <- fmt.Sprintf("%s \x1b[36m%s\x1b[0m cache \x1b[1mhit\x1b[0m id=%d", now, strings.ToUpper(level), i)
+		}
+	}
+}
+
+type model struct {
+	lines <-chan string
+	log   logview.Model
+}
+
+func (m *model) Init(yield func(...tea.Cmd)) {
+	yield(m.log.Listen(m.lines))
+}
+
+func (m *model) Update(msg tea.Msg, yield func(...tea.Cmd)) {
+	switch msg := msg.(type) {
+	case tea.MsgWindowSize:
+		m.log.SetSize(msg.Height, msg.Width)
+	case tea.MsgKey:
+		if m.log.Filtering() {
+			break
+		}
+
+		switch msg.String() {
+		case "ctrl+c", "q":
+			yield(tea.Quit)
+			return
+		}
+	}
+	m.log.Update(msg, yield)
+}
+
+func (m *model) View(vb tea.Viewbox) {
+	m.log.View(vb)
+}
+
+func Main(ctx context.Context) error {
+	lines := make(chan string)
+	go generate(ctx, lines)
+
+	_, err := tea.NewProgram(ctx, &model{
+		lines: lines,
+		log:   logview.New(1000, 20, 80),
+	}).WithOutput(os.Stderr).WithAltScreen().WithMouseCellMotion().Run()
+	return err
+}
@@ -21,12 +21,14 @@ import (
 	"github.com/rprtr258/tea/cmd/exec"
 	"github.com/rprtr258/tea/cmd/file_picker"
 	"github.com/rprtr258/tea/cmd/file_picker2"
+	"github.com/rprtr258/tea/cmd/form"
 	"github.com/rprtr258/tea/cmd/fullscreen"
 	"github.com/rprtr258/tea/cmd/help"
 	"github.com/rprtr258/tea/cmd/http"
 	"github.com/rprtr258/tea/cmd/list_default"
 	"github.com/rprtr258/tea/cmd/list_fancy"
 	"github.com/rprtr258/tea/cmd/list_simple"
+	"github.com/rprtr258/tea/cmd/log_viewer"
 	"github.com/rprtr258/tea/cmd/markdown/custom_renderer"
 	"github.com/rprtr258/tea/cmd/markdown/helloworld"
 	"github.com/rprtr258/tea/cmd/markdown/menu"
@@ -81,12 +83,14 @@ var (
 		"exec":              exec.Main,
 		"file-picker":       file_picker.Main,
 		"file-picker2":      file_picker2.Main,
+		"form":              form.Main,
 		"fullscreen":        fullscreen.Main,
 		"help":              help.Main,
 		"http":              http.Main,
 		"list-default":      list_default.Main,
 		"list-fancy":        list_fancy.Main,
 		"list-simple":       list_simple.Main,
+		"log-viewer":        log_viewer.Main,
 		"mouse":             mouse.Main,
 		"package-manager":   package_manager.Main,
 		"pager":             pager.Main,
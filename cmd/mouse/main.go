@@ -1,18 +1,23 @@
 package mouse
 
 // A simple program that opens the alternate screen buffer and displays mouse
-// coordinates and events.
+// coordinates and events, as well as events over tagged zones.
 
 import (
 	"context"
 	"fmt"
 
 	"github.com/rprtr258/tea"
+	"github.com/rprtr258/tea/styles"
 )
 
+var _styleHovered = styles.Style{}.Background(styles.BgColor("62"))
+
 type model struct {
 	init       bool
 	mouseEvent tea.MouseEvent
+	zoneEvent  tea.MsgMouseZone
+	hovered    string
 }
 
 func (m *model) Init(func(...tea.Cmd)) {}
@@ -26,6 +31,14 @@ func (m *model) Update(msg tea.Msg, f func(...tea.Cmd)) {
 	case tea.MsgMouse:
 		m.init = true
 		m.mouseEvent = tea.MouseEvent(msg)
+	case tea.MsgMouseZone:
+		m.zoneEvent = msg
+		switch msg.Event.Type {
+		case tea.MouseZoneEnter:
+			m.hovered = msg.ID
+		case tea.MouseZoneLeave:
+			m.hovered = ""
+		}
 	}
 }
 
@@ -36,6 +49,23 @@ func (m *model) View(vb tea.Viewbox) {
 		e := m.mouseEvent
 		vb.PaddingTop(2).WriteLine(fmt.Sprintf("(X: %d, Y: %d) %s", e.X, e.Y, e))
 	}
+
+	if z := m.zoneEvent; z.ID != "" {
+		vb.PaddingTop(3).WriteLine(fmt.Sprintf("zone %q (X: %d, Y: %d) %s", z.ID, z.X, z.Y, z.Event))
+	}
+
+	vbZones := vb.PaddingTop(5).MaxHeight(3)
+	for i, vbZone := range vbZones.SplitX(tea.Fixed(12), tea.Fixed(2), tea.Fixed(12), tea.Fixed(2), tea.Fixed(12)) {
+		if i%2 == 1 {
+			continue
+		}
+
+		id := fmt.Sprintf("zone %d", i/2+1)
+		if m.hovered == id {
+			vbZone = vbZone.Styled(_styleHovered)
+		}
+		vbZone.Zone(id).PaddingTop(1).PaddingLeft(3).WriteLine(id)
+	}
 }
 
 func Main(ctx context.Context) error {
@@ -6,6 +6,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"slices"
 	"strings"
 
 	"github.com/rprtr258/tea"
@@ -23,6 +24,11 @@ func (m *model) Init(func(...tea.Cmd)) {}
 func (m *model) Update(msg tea.Msg, f func(...tea.Cmd)) {
 	switch msg := msg.(type) {
 	case tea.MsgKey:
+		if m.viewport.Search.Active() {
+			// keys are typed into search bar
+			break
+		}
+
 		switch msg.String() {
 		case "ctrl+c", "q", "esc":
 			f(tea.Quit)
@@ -31,10 +37,11 @@ func (m *model) Update(msg tea.Msg, f func(...tea.Cmd)) {
 	case tea.MsgWindowSize:
 		const headerHeight = 3
 		const footerHeight = 3
-		m.viewport = viewport.New(msg.Width, msg.Height-headerHeight-footerHeight)
+		m.viewport.Width = msg.Width
+		m.viewport.Height = msg.Height - headerHeight - footerHeight
 	}
 
-	m.viewport.Update(msg)
+	m.viewport.UpdateSearch(msg, f)
 	if m.viewport.YOffset > len(m.lines)-m.viewport.Height {
 		m.viewport.YOffset = max(0, len(m.lines)-m.viewport.Height)
 	}
@@ -103,8 +110,13 @@ func Main(ctx context.Context) error {
 		return fmt.Errorf("load file: %w", err)
 	}
 
+	lines := strings.Split(string(content), "\n")
+	vp := viewport.New(0, 0)
+	vp.SearchSource = slices.All(lines) // press / to search
+
 	_, err = tea.NewProgram(ctx, &model{
-		lines: strings.Split(string(content), "\n"),
+		viewport: vp,
+		lines:    lines,
 	}).
 		WithAltScreen().       // use the full size of the terminal in its "alternate screen buffer"
 		WithMouseCellMotion(). // turn on mouse support so we can track the mouse wheel
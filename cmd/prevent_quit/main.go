@@ -4,9 +4,9 @@ package prevent_quit //nolint:revive,stylecheck
 
 import (
 	"context"
-	"strings"
 
 	"github.com/rprtr258/tea"
+	"github.com/rprtr258/tea/components/box"
 	"github.com/rprtr258/tea/components/help"
 	"github.com/rprtr258/tea/components/key"
 	"github.com/rprtr258/tea/components/textarea"
@@ -16,7 +16,6 @@ import (
 var (
 	choiceStyle   = styles.Style{}. /*.PaddingLeft(1)*/ Foreground(styles.FgColor("241"))
 	saveTextStyle = styles.Style{}.Foreground(styles.FgColor("170"))
-	quitViewStyle = styles.Style{} /*.Padding(1) Border(styles.RoundedBorder).BorderForeground(styles.FgColor("170"))*/
 )
 
 type model struct {
@@ -106,16 +105,7 @@ func (m *model) updatePromptView(msg tea.Msg, f func(...tea.Cmd)) {
 }
 
 func (m *model) View(vb tea.Viewbox) {
-	if m.quitting {
-		if m.hasChanges {
-			vb.Styled(quitViewStyle).WriteText(0, 0, styles.JoinHorizontal(
-				styles.Top,
-				[]string{"You have unsaved changes. Quit without saving?"},
-				strings.Split(choiceStyle.Render("[yn]"), "\n"),
-			))
-			return
-		}
-
+	if m.quitting && !m.hasChanges {
 		vb.WriteLine("Very important, thank you")
 		return
 	}
@@ -128,6 +118,27 @@ func (m *model) View(vb tea.Viewbox) {
 		m.keymap.save,
 		m.keymap.quit,
 	})
+
+	if m.quitting {
+		const prompt = "You have unsaved changes. Quit without saving?"
+		vb.Overlay(tea.OverlayOptions{
+			Height: 3,
+			Width:  len(prompt) + 2 + 5,
+			Shadow: true,
+			Dim:    true,
+		}, func(vb tea.Viewbox) {
+			box.Box(
+				vb,
+				func(vb tea.Viewbox) {
+					vb.WriteLineX(prompt + " ").Styled(choiceStyle).WriteLine("[yn]")
+				},
+				box.RoundedBorder,
+				box.BorderMaskAll,
+				box.Colors(nil),
+				box.Colors(styles.FgColor("170")),
+			)
+		})
+	}
 }
 
 func Main(ctx context.Context) error {
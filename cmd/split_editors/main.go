@@ -96,7 +96,8 @@ func newModel() *model {
 				Help: key.Help{"ctrl+w", "remove an editor"},
 			},
 			quit: key.Binding{
-				Keys: []string{"esc", "ctrl+c"},
+				// ctrl+c is left for copying selected text
+				Keys: []string{"esc"},
 				Help: key.Help{"esc", "quit"},
 			},
 		},
@@ -186,6 +187,6 @@ func (m *model) View(vb tea.Viewbox) {
 }
 
 func Main(ctx context.Context) error {
-	_, err := tea.NewProgram(ctx, newModel()).WithAltScreen().Run()
+	_, err := tea.NewProgram(ctx, newModel()).WithAltScreen().WithMouseCellMotion().Run()
 	return err
 }
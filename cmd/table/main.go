@@ -2,6 +2,10 @@ package table
 
 import (
 	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/rprtr258/tea"
 	"github.com/rprtr258/tea/components/table"
@@ -9,30 +13,102 @@ import (
 )
 
 type model struct {
-	table table.Model
+	table table.Model[[]string]
 }
 
 func (*model) Init(func(...tea.Cmd)) {}
 
 func (m *model) Update(msg tea.Msg, f func(...tea.Cmd)) {
-	switch msg := msg.(type) { //nolint:gocritic
+	switch msg := msg.(type) {
+	case table.MsgCellEdited[[]string]:
+		f(tea.Printf("Saved %s of %s: %s", columns[msg.Column].Title, msg.Updated[1], msg.Value))
+		return
 	case tea.MsgKey:
+		if m.table.Filtering() || m.table.Editing() {
+			break
+		}
+
 		switch msg.String() {
 		case "esc", "q", "ctrl+c":
 			f(tea.Quit)
 			return
 		case "enter":
-			f(tea.Printf("Let's go to %s!", m.table.SelectedRow()[1]))
+			if rows := m.table.MarkedRows(); len(rows) > 0 {
+				for _, row := range rows {
+					f(tea.Printf("Let's go to %s!", row[1]))
+				}
+			} else if row, ok := m.table.SelectedRow(); ok {
+				f(tea.Printf("Let's go to %s!", row[1]))
+			}
 			return
 		}
 	}
-	m.table.Update(msg)
+	m.table.Update(msg, f)
 }
 
 func (m *model) View(vb tea.Viewbox) {
 	m.table.View(vb)
 }
 
+// set returns function setting i-th value of row, validated by validate.
+func set(i int, validate func(string) error) func([]string, string) ([]string, error) {
+	return func(row []string, value string) ([]string, error) {
+		if err := validate(value); err != nil {
+			return nil, err
+		}
+
+		row = slices.Clone(row)
+		row[i] = value
+		return row, nil
+	}
+}
+
+func nonEmpty(value string) error {
+	if strings.TrimSpace(value) == "" {
+		return errors.New("must not be empty")
+	}
+	return nil
+}
+
+func population(value string) error {
+	if _, err := strconv.Atoi(strings.ReplaceAll(value, ",", "")); err != nil {
+		return errors.New("must be a number")
+	}
+	return nil
+}
+
+func digits(value string) error {
+	if strings.Trim(value, "0123456789,") != "" {
+		return errors.New("only digits are allowed")
+	}
+	return nil
+}
+
+func get(i int) func([]string) string {
+	return func(row []string) string { return row[i] }
+}
+
+var columns = []table.Column[[]string]{
+	{Title: "Rank", Get: get(0), Align: table.AlignRight},
+	{Title: "City", Get: get(1), MaxWidth: 12, Set: set(1, nonEmpty)},
+	{Title: "Country", Get: get(2), Flex: 1, MinWidth: 10},
+	{Title: "Population", Get: get(3), Align: table.AlignRight, Set: set(3, population), Validate: digits},
+	{Title: "Size", Get: get(3), Render: sizeBadge},
+}
+
+// sizeBadge renders colored badge of city size by its population.
+func sizeBadge(vb tea.Viewbox, row []string) {
+	millions, _, _ := strings.Cut(row[3], ",")
+	switch n, _ := strconv.Atoi(millions); {
+	case n >= 20:
+		vb.Styled(styles.Style{}.Foreground(styles.FgColor("9"))).WriteLine("mega")
+	case n >= 10:
+		vb.Styled(styles.Style{}.Foreground(styles.FgColor("11"))).WriteLine("huge")
+	default:
+		vb.Styled(styles.Style{}.Foreground(styles.FgColor("10"))).WriteLine("big")
+	}
+}
+
 func Main(ctx context.Context) error {
 	s := table.DefaultStyles
 	s.Header = s.Header.
@@ -45,13 +121,6 @@ func Main(ctx context.Context) error {
 		Background(styles.BgColor("57")).
 		Bold(false)
 
-	widths := []int{4, 10, 10, 10}
-	columns := []table.Column{
-		{Title: "Rank"},
-		{Title: "City"},
-		{Title: "Country"},
-		{Title: "Population"},
-	}
 	rows := [][]string{
 		{"1", "Tokyo", "Japan", "37,274,000"},
 		{"2", "Delhi", "India", "32,065,760"},
@@ -156,14 +225,14 @@ func Main(ctx context.Context) error {
 	}
 	t := table.New(
 		columns,
-		widths,
 		rows,
 		7,
 		0,
 		s,
 		table.DefaultKeyMap,
 	)
+	t.Frozen = 1 // keep rank visible while scrolling
 
-	_, err := tea.NewProgram(ctx, &model{t}).Run()
+	_, err := tea.NewProgram(ctx, &model{t}).WithMouseCellMotion().Run()
 	return err
 }
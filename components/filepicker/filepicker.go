@@ -5,17 +5,22 @@ import (
 	"fmt"
 	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
 	"slices"
 	"strings"
+	"time"
 	"unsafe"
 
+	"github.com/alecthomas/chroma/v2"
 	"github.com/dustin/go-humanize"
 	"github.com/rprtr258/fun"
 	"github.com/rprtr258/scuf"
 
 	"github.com/rprtr258/tea"
 	"github.com/rprtr258/tea/components/key"
+	"github.com/rprtr258/tea/components/markdown/ansi"
+	"github.com/rprtr258/tea/components/textinput"
 	"github.com/rprtr258/tea/styles"
 )
 
@@ -23,9 +28,12 @@ func pop(data []int) ([]int, int) {
 	return data[:len(data)-1], data[len(data)-1]
 }
 
-// TODO: accept fs.FS, test w/ S3
-// New returns a new filepicker model with default styling and key bindings.
+// New returns a new filepicker model with default styling and key bindings,
+// browsing OS file system.
 func New() Model {
+	query := textinput.New()
+	query.Prompt = "/ "
+
 	return Model{
 		CurrentDirectory: ".",
 		Cursor:           ">",
@@ -33,6 +41,8 @@ func New() Model {
 		AutoHeight:       true,
 		KeyMap:           DefaultKeyMap,
 		Styles:           DefaultStyles,
+		marked:           map[string]bool{},
+		query:            query,
 	}
 }
 
@@ -40,10 +50,44 @@ type msgError struct{ err error }
 
 type msgReadDir struct {
 	id      uintptr
-	entries []os.DirEntry
+	dir     string
+	entries []entry
+	// refresh is whether entries of the same directory are reread
+	refresh bool
+}
+
+// entry is a directory entry with its info read.
+type entry struct {
+	fs.DirEntry
+	info fs.FileInfo
+	// target is destination of symbolic link, empty if entry is not a link
+	// or file system can not read links
+	target string
+	// isDir is whether entry is a directory or a link to one
+	isDir bool
+	// path is slash separated path relative to current directory, it is
+	// entry name unless entry is found in subdirectory
+	path string
+	// matches are byte indexes of path characters matching find query
+	matches []int
 }
 
+// readLinkFS is a file system with symbolic links, e.g. os.DirFS.
+type readLinkFS interface {
+	fs.FS
+	ReadLink(name string) (string, error)
+}
+
+// osFS is the OS file system, names are OS paths.
+type osFS struct{}
+
+func (osFS) Open(name string) (fs.File, error)          { return os.Open(name) }
+func (osFS) ReadDir(name string) ([]fs.DirEntry, error) { return os.ReadDir(name) }
+func (osFS) Stat(name string) (fs.FileInfo, error)      { return os.Stat(name) }
+func (osFS) ReadLink(name string) (string, error)       { return os.Readlink(name) }
+
 const (
+	_marker        = '•'
 	_marginBottom  = 5
 	_fileSizeWidth = 8
 	_paddingLeft   = 2
@@ -60,6 +104,12 @@ type KeyMap struct {
 	Back     key.Binding
 	Open     key.Binding
 	Select   key.Binding
+	// Mark marks entry to be selected with others, if MultiSelect is set.
+	Mark key.Binding
+	// Find starts finding entries in current directory and its
+	// subdirectories by fuzzy query.
+	Find       key.Binding
+	CancelFind key.Binding
 }
 
 // DefaultKeyMap defines the default keybindings.
@@ -73,6 +123,12 @@ var DefaultKeyMap = KeyMap{
 	Back:     key.Binding{Keys: []string{"h", "backspace", "left", "esc"}, Help: key.Help{"h", "back"}},
 	Open:     key.Binding{Keys: []string{"l", "right", "enter"}, Help: key.Help{"l", "open"}},
 	Select:   key.Binding{Keys: []string{"enter"}, Help: key.Help{"enter", "select"}},
+	Mark:     key.Binding{Keys: []string{" ", "tab"}, Help: key.Help{"space/tab", "mark"}},
+	Find:     key.Binding{Keys: []string{"/"}, Help: key.Help{"/", "find"}},
+	CancelFind: key.Binding{
+		Keys: []string{"esc"},
+		Help: key.Help{"esc", "cancel find"},
+	},
 }
 
 // Styles defines the possible customizations for styles in the file picker.
@@ -88,6 +144,14 @@ type Styles struct {
 	DisabledSelected styles.Style
 	FileSize         styles.Style
 	EmptyDirectory   styles.Style
+	Marked           styles.Style
+	// Match is style of path characters matching find query.
+	Match styles.Style
+	// PreviewBorder is style of line between entries and preview.
+	PreviewBorder styles.Style
+	// Highlight is styles of code tokens in preview, tokens missing from
+	// map are styled as their subcategory or category.
+	Highlight map[chroma.TokenType]styles.Style
 }
 
 // DefaultStyles is default styling for the file picker, with a given Lip Gloss renderer.
@@ -103,6 +167,11 @@ var DefaultStyles = Styles{
 	Selected:         styles.Style{}.Foreground(scuf.FgANSI(212)).Bold(true),
 	FileSize:         styles.Style{}.Foreground(scuf.FgANSI(240)).Align(styles.Right),
 	EmptyDirectory:   styles.Style{}.Foreground(scuf.FgANSI(240)),
+	Marked:           styles.Style{}.Foreground(scuf.FgANSI(214)),
+	Match:            styles.Style{}.Underline(),
+	PreviewBorder:    styles.Style{}.Foreground(scuf.FgANSI(240)),
+	// the same as code blocks in markdown
+	Highlight: ansi.HighlightStyles(ansi.ChromaStyle("filepicker", ansi.DarkChroma)),
 }
 
 // Model represents a file picker.
@@ -110,7 +179,13 @@ type Model struct {
 	// Path is the path which the user has selected with the file picker.
 	Path string
 
-	// CurrentDirectory is the directory that the user is currently in.
+	// FS is the file system being browsed, e.g. embed.FS, zip.Reader or
+	// fstest.MapFS. If nil, OS file system is browsed.
+	FS fs.FS
+
+	// CurrentDirectory is the directory that the user is currently in. It
+	// is OS path if FS is nil, otherwise slash separated path in FS, "."
+	// being its root.
 	CurrentDirectory string
 
 	// AllowedTypes specifies which file types the user may select.
@@ -118,10 +193,33 @@ type Model struct {
 	AllowedTypes []string
 
 	KeyMap      KeyMap
-	files       []os.DirEntry
+	files       []entry
 	ShowHidden  bool
 	DirAllowed  bool
 	FileAllowed bool
+	// MultiSelect allows marking several entries, which are selected
+	// together, see DidSelectFiles.
+	MultiSelect bool
+	// marked are paths of marked entries
+	marked map[string]bool
+
+	// finding is whether entries are being found by query, then files are
+	// entries matching it from all found in current directory subtree
+	finding bool
+	query   textinput.Model
+	all     []entry
+
+	// Preview shows content of highlighted entry next to entries.
+	Preview bool
+	preview *preview
+
+	// Watch refreshes entries when current directory changes. OS file
+	// system is watched by OS notifications where supported, otherwise
+	// directory is polled every WatchInterval, a second by default. Close
+	// must be called to stop watching once picker is not used anymore.
+	Watch         bool
+	WatchInterval time.Duration
+	watcher       *watcher
 
 	fileSelected  string
 	selected      int
@@ -147,8 +245,8 @@ func (m *Model) pushView() {
 
 func (m *Model) popView() (selected, min, max int) {
 	m.selectedStack, selected = pop(m.selectedStack)
-	m.minStack, min = pop(m.selectedStack)
-	m.maxStack, max = pop(m.selectedStack)
+	m.minStack, min = pop(m.minStack)
+	m.maxStack, max = pop(m.maxStack)
 	return
 }
 
@@ -157,9 +255,56 @@ func isHidden(file string) bool {
 	return strings.HasPrefix(file, ".")
 }
 
-func (m *Model) cmdReadDir(path string, showHidden bool) tea.Cmd {
+// fsys returns the file system being browsed.
+func (m *Model) fsys() fs.FS {
+	if m.FS == nil {
+		return osFS{}
+	}
+	return m.FS
+}
+
+// join joins path elements, using OS separator if OS file system is
+// browsed.
+func (m *Model) join(elem ...string) string {
+	if m.FS == nil {
+		return filepath.Join(elem...)
+	}
+	return path.Join(elem...)
+}
+
+// parent returns parent directory of dir.
+func (m *Model) parent(dir string) string {
+	if m.FS == nil {
+		return filepath.Dir(dir)
+	}
+	return path.Dir(dir)
+}
+
+// readEntry reads info of entry with given name, following symbolic link.
+// Path is name relative to current directory.
+func readEntry(fsys fs.FS, name, path string, e fs.DirEntry) (entry, error) {
+	info, err := e.Info()
+	if err != nil {
+		return entry{}, err
+	}
+
+	res := entry{DirEntry: e, info: info, isDir: e.IsDir(), path: path}
+	if info.Mode()&fs.ModeSymlink != 0 {
+		if lfs, ok := fsys.(readLinkFS); ok {
+			res.target, _ = lfs.ReadLink(name)
+		}
+		if target, err := fs.Stat(fsys, name); err == nil {
+			res.isDir = target.IsDir()
+		}
+	}
+	return res, nil
+}
+
+func (m *Model) cmdReadDir(dir string, showHidden bool) tea.Cmd {
+	fsys := m.fsys()
+	join := m.join
 	return func() tea.Msg {
-		dirEntries, err := os.ReadDir(path)
+		dirEntries, err := fs.ReadDir(fsys, dir)
 		if err != nil {
 			return msgError{err}
 		}
@@ -175,16 +320,23 @@ func (m *Model) cmdReadDir(path string, showHidden bool) tea.Cmd {
 			}
 		})
 
+		entries := make([]entry, 0, len(dirEntries))
+		for _, e := range dirEntries {
+			if !showHidden && isHidden(e.Name()) {
+				continue
+			}
+
+			// entries which info can not be read, e.g. removed meanwhile,
+			// are skipped
+			if res, err := readEntry(fsys, join(dir, e.Name()), e.Name(), e); err == nil {
+				entries = append(entries, res)
+			}
+		}
+
 		return msgReadDir{
-			id: uintptr(unsafe.Pointer(m)),
-			entries: fun.
-				If(showHidden, dirEntries).
-				ElseF(func() []fs.DirEntry {
-					return fun.Filter(
-						func(dirEntry os.DirEntry) bool {
-							return !isHidden(dirEntry.Name())
-						}, dirEntries...)
-				}),
+			id:      uintptr(unsafe.Pointer(m)),
+			dir:     dir,
+			entries: entries,
 		}
 	}
 }
@@ -192,120 +344,161 @@ func (m *Model) cmdReadDir(path string, showHidden bool) tea.Cmd {
 // Init initializes the file picker model.
 func (m *Model) Init(yield func(...tea.Cmd)) {
 	yield(m.cmdReadDir(m.CurrentDirectory, m.ShowHidden))
+	if cmd := m.cmdWatchDir(); cmd != nil {
+		yield(cmd)
+	}
 }
 
 // Update handles user interactions within the file picker model.
 func (m *Model) Update(msg tea.Msg) []tea.Cmd {
+	cmds := m.update(msg)
+	if cmd := m.cmdPreview(); cmd != nil {
+		cmds = append(cmds, cmd)
+	}
+	if cmd := m.cmdWatchDir(); cmd != nil {
+		cmds = append(cmds, cmd)
+	}
+	return cmds
+}
+
+func (m *Model) update(msg tea.Msg) []tea.Cmd {
 	switch msg := msg.(type) {
 	case msgReadDir:
-		if msg.id != uintptr(unsafe.Pointer(m)) {
+		if msg.id != uintptr(unsafe.Pointer(m)) || msg.dir != m.CurrentDirectory {
+			break
+		}
+		if msg.refresh {
+			m.refreshed(msg.entries)
 			break
 		}
 		m.files = msg.entries
 		m.max = m.Height - 1
+	case msgWalk:
+		m.walked(msg)
+	case msgPreview:
+		m.previewed(msg)
+	case msgWatch:
+		return m.watched(msg)
 	case tea.MsgWindowSize:
 		if m.AutoHeight {
 			m.Height = msg.Height - _marginBottom
 		}
 		m.max = m.Height - 1
 	case tea.MsgKey:
-		switch {
-		case key.Matches(msg, m.KeyMap.GoToTop):
+		if m.finding {
+			return m.updateFind(msg)
+		}
+		return m.updateKey(msg)
+	}
+	return nil
+}
+
+// updateKey handles keys browsing entries.
+func (m *Model) updateKey(msg tea.MsgKey) []tea.Cmd {
+	switch {
+	case key.Matches(msg, m.KeyMap.Mark):
+		m.ToggleMark()
+	case key.Matches(msg, m.KeyMap.Find):
+		return m.startFind()
+	case key.Matches(msg, m.KeyMap.GoToTop):
+		m.selected = 0
+		m.min = 0
+		m.max = m.Height - 1
+	case key.Matches(msg, m.KeyMap.GoToLast):
+		m.selected = max(len(m.files)-1, 0)
+		m.min = len(m.files) - m.Height
+		m.max = len(m.files) - 1
+	case key.Matches(msg, m.KeyMap.Down):
+		m.selected = max(min(m.selected+1, len(m.files)-1), 0)
+		if m.selected > m.max {
+			m.min++
+			m.max++
+		}
+	case key.Matches(msg, m.KeyMap.Up):
+		m.selected = max(m.selected-1, 0)
+		if m.selected < m.min {
+			m.min--
+			m.max--
+		}
+	case key.Matches(msg, m.KeyMap.PageDown):
+		m.selected = max(min(m.selected+m.Height, len(m.files)-1), 0)
+		m.min += m.Height
+		m.max += m.Height
+
+		if m.max >= len(m.files) {
+			m.max = len(m.files) - 1
+			m.min = m.max - m.Height
+		}
+	case key.Matches(msg, m.KeyMap.PageUp):
+		m.selected = max(m.selected-m.Height, 0)
+		m.min -= m.Height
+		m.max -= m.Height
+
+		if m.min < 0 {
+			m.min = 0
+			m.max = m.min + m.Height
+		}
+	case key.Matches(msg, m.KeyMap.Back):
+		m.CurrentDirectory = m.parent(m.CurrentDirectory)
+		if len(m.selectedStack) > 0 {
+			m.selected, m.min, m.max = m.popView()
+		} else {
 			m.selected = 0
 			m.min = 0
 			m.max = m.Height - 1
-		case key.Matches(msg, m.KeyMap.GoToLast):
-			m.selected = len(m.files) - 1
-			m.min = len(m.files) - m.Height
-			m.max = len(m.files) - 1
-		case key.Matches(msg, m.KeyMap.Down):
-			m.selected = min(m.selected+1, len(m.files)-1)
-			if m.selected > m.max {
-				m.min++
-				m.max++
-			}
-		case key.Matches(msg, m.KeyMap.Up):
-			m.selected = max(m.selected-1, 0)
-			if m.selected < m.min {
-				m.min--
-				m.max--
-			}
-		case key.Matches(msg, m.KeyMap.PageDown):
-			m.selected = min(m.selected+m.Height, len(m.files)-1)
-			m.min += m.Height
-			m.max += m.Height
-
-			if m.max >= len(m.files) {
-				m.max = len(m.files) - 1
-				m.min = m.max - m.Height
-			}
-		case key.Matches(msg, m.KeyMap.PageUp):
-			m.selected = max(m.selected-m.Height, 0)
-			m.min -= m.Height
-			m.max -= m.Height
-
-			if m.min < 0 {
-				m.min = 0
-				m.max = m.min + m.Height
-			}
-		case key.Matches(msg, m.KeyMap.Back):
-			m.CurrentDirectory = filepath.Dir(m.CurrentDirectory)
-			if len(m.selectedStack) > 0 {
-				m.selected, m.min, m.max = m.popView()
-			} else {
-				m.selected = 0
-				m.min = 0
-				m.max = m.Height - 1
-			}
-			return []tea.Cmd{m.cmdReadDir(m.CurrentDirectory, m.ShowHidden)}
-		case key.Matches(msg, m.KeyMap.Open):
-			if len(m.files) == 0 {
-				break
-			}
-
-			f := m.files[m.selected]
-			info, err := f.Info()
-			if err != nil {
-				break
-			}
-			isSymlink := info.Mode()&os.ModeSymlink != 0
-			isDir := f.IsDir()
-
-			if isSymlink {
-				symlinkPath, _ := filepath.EvalSymlinks(filepath.Join(m.CurrentDirectory, f.Name()))
-				info, err := os.Stat(symlinkPath)
-				if err != nil {
-					break
-				}
-				if info.IsDir() {
-					isDir = true
-				}
-			}
+		}
+		return []tea.Cmd{m.cmdReadDir(m.CurrentDirectory, m.ShowHidden)}
+	case key.Matches(msg, m.KeyMap.Open):
+		if len(m.files) == 0 || len(m.marked) > 0 && key.Matches(msg, m.KeyMap.Select) {
+			// marked entries are selected instead
+			break
+		}
 
-			if !isDir && m.FileAllowed || isDir && m.DirAllowed {
-				if key.Matches(msg, m.KeyMap.Select) {
-					// Select the current path as the selection
-					m.Path = filepath.Join(m.CurrentDirectory, f.Name())
-				}
+		f := m.files[m.selected]
+		if !f.isDir && m.FileAllowed || f.isDir && m.DirAllowed {
+			if key.Matches(msg, m.KeyMap.Select) {
+				// Select the current path as the selection
+				m.Path = m.join(m.CurrentDirectory, f.path)
 			}
+		}
 
-			if !isDir {
-				break
-			}
+		if !f.isDir {
+			break
+		}
 
-			m.CurrentDirectory = filepath.Join(m.CurrentDirectory, f.Name())
+		m.CurrentDirectory = m.join(m.CurrentDirectory, f.path)
+		if m.finding {
+			// found directory can be deep down, view of current one
+			// is not restored when going back
+			m.stopFind()
+			m.selectedStack, m.minStack, m.maxStack = nil, nil, nil
+		} else {
 			m.pushView()
-			m.selected = 0
-			m.min = 0
-			m.max = m.Height - 1
-			return []tea.Cmd{m.cmdReadDir(m.CurrentDirectory, m.ShowHidden)}
 		}
+		m.selected = 0
+		m.min = 0
+		m.max = m.Height - 1
+		return []tea.Cmd{m.cmdReadDir(m.CurrentDirectory, m.ShowHidden)}
 	}
 	return nil
 }
 
 // View returns the view of the file picker.
 func (m *Model) View(vb tea.Viewbox) {
+	if m.finding {
+		m.query.View(vb.Row(0))
+		vb = vb.PaddingTop(1)
+	}
+
+	if m.Preview {
+		vbList, vbBorder, vbPreview := vb.MaxWidth(vb.Width/2), vb.Sub(tea.Rectangle{Left: vb.Width / 2, Width: 1}), vb.PaddingLeft(vb.Width/2+2)
+		for y := range vbBorder.Height {
+			vbBorder.Styled(m.Styles.PreviewBorder).Set(y, 0, '│')
+		}
+		m.viewPreview(vbPreview)
+		vb = vbList
+	}
+
 	if len(m.files) == 0 {
 		vb.Styled(m.Styles.EmptyDirectory).WriteLine("Bummer. No Files Found.")
 		return
@@ -316,48 +509,67 @@ func (m *Model) View(vb tea.Viewbox) {
 			continue
 		}
 
-		info, _ := f.Info()
-		isSymlink := info.Mode()&os.ModeSymlink != 0
-		size := humanize.Bytes(uint64(info.Size()))
-		size = strings.Repeat(" ", _fileSizeWidth-len(size)) + size
+		isSymlink := f.info.Mode()&fs.ModeSymlink != 0
+		size := humanize.Bytes(uint64(f.info.Size()))
+		size = strings.Repeat(" ", max(_fileSizeWidth-len(size), 0)) + size
 		name := f.Name()
 
 		disabled := !m.canSelect(name) && !f.IsDir()
-		fileName := fun.If(!isSymlink, name).ElseF(func() string {
-			symlinkPath, _ := filepath.EvalSymlinks(filepath.Join(m.CurrentDirectory, name))
-			return fmt.Sprintf("%s → %s", name, symlinkPath)
-		})
+		target := fun.IF(f.target == "", "", fmt.Sprintf(" → %s", f.target))
 
-		vb0 := vb.Row(i)
+		vb0 := vb.Row(i - m.min)
+		if m.isMarked(f) {
+			vb0.Styled(m.Styles.Marked).Set(0, 1, _marker)
+		}
 		if m.selected == i {
 			styleCursor := fun.IF(disabled, m.Styles.DisabledSelected, m.Styles.Cursor)
 			styleSelected := fun.IF(disabled, m.Styles.DisabledSelected, m.Styles.Selected)
 			vb0.Styled(styleCursor).WriteLine(m.Cursor)
 			vb0 = vb0.PaddingLeft(2).Styled(styleSelected)
-			vb0.WriteLine(info.Mode().String())
+			vb0.WriteLine(f.info.Mode().String())
 			vb0 = vb0.PaddingLeft(1 + 3*3 + 1) // type(1) + perms(3*3) + space(1)
 			vb0.WriteLine(size)
 			vb0 = vb0.PaddingLeft(_fileSizeWidth + 1)
-			vb0.WriteLine(fileName)
+			if vb0, ok := m.writePath(vb0, f); ok {
+				vb0.WriteLine(target)
+			}
 			continue
 		}
 
 		vb0 = vb0.PaddingLeft(2)
 
-		vb0.Styled(m.Styles.Permission).WriteLine(info.Mode().String())
+		vb0.Styled(m.Styles.Permission).WriteLine(f.info.Mode().String())
 		vb0 = vb0.PaddingLeft(1 + 3*3 + 1) // type(1) + perms(3*3) + space(1)
 		vb0.Styled(m.Styles.FileSize).WriteLine(size)
 		vb0 = vb0.PaddingLeft(_fileSizeWidth + 1)
-		vb0.
-			Styled(fun.Switch(true, m.Styles.File).
-				Case(m.Styles.Directory, f.IsDir()).
-				Case(m.Styles.Symlink, isSymlink).
-				Case(m.Styles.DisabledFile, disabled).
-				End()).
-			WriteLine(fileName)
+		vb0 = vb0.Styled(fun.Switch(true, m.Styles.File).
+			Case(m.Styles.Directory, f.IsDir()).
+			Case(m.Styles.Symlink, isSymlink).
+			Case(m.Styles.DisabledFile, disabled).
+			End())
+		if vb0, ok := m.writePath(vb0, f); ok {
+			vb0.WriteLine(target)
+		}
 	}
 }
 
+// writePath writes entry path with characters matching find query
+// highlighted, returns viewbox after it and false if there is no room left.
+func (m *Model) writePath(vb tea.Viewbox, f entry) (tea.Viewbox, bool) {
+	for i, c := range f.path {
+		vbChar := vb
+		if slices.Contains(f.matches, i) {
+			vbChar = vb.Styled(m.Styles.Match)
+		}
+		w := vbChar.WriteLine(string(c))
+		if w >= vb.Width {
+			return vb, false
+		}
+		vb = vb.PaddingLeft(w)
+	}
+	return vb, true
+}
+
 // DidSelectFile returns whether a user has selected a file (on this msg).
 func (m *Model) DidSelectFile(msg tea.Msg) (bool, string) {
 	didSelect, path := m.didSelectFile(msg)
@@ -390,25 +602,7 @@ func (m *Model) didSelectFile(msg tea.Msg) (bool, string) {
 		// The key press was a selection, let's confirm whether the current file could
 		// be selected or used for navigating deeper into the stack.
 		f := m.files[m.selected]
-		info, err := f.Info()
-		if err != nil {
-			return false, ""
-		}
-
-		isDir := f.IsDir()
-		if info.Mode()&os.ModeSymlink != 0 { // is symlink
-			symlinkPath, _ := filepath.EvalSymlinks(filepath.Join(m.CurrentDirectory, f.Name()))
-			info, err := os.Stat(symlinkPath)
-			if err != nil {
-				return false, ""
-			}
-
-			if info.IsDir() {
-				isDir = true
-			}
-		}
-
-		if !isDir && m.FileAllowed || isDir && m.DirAllowed && m.Path != "" {
+		if !f.isDir && m.FileAllowed || f.isDir && m.DirAllowed && m.Path != "" {
 			return true, m.Path
 		}
 
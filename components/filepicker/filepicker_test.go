@@ -0,0 +1,273 @@
+package filepicker
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/rprtr258/assert"
+
+	"github.com/rprtr258/tea"
+)
+
+func TestFS(t *testing.T) {
+	m := New()
+	m.AutoHeight = false
+	m.Height = 5
+	m.FS = fstest.MapFS{
+		"go.mod":          {Data: []byte("module x\n"), Mode: 0o644},
+		"cmd/main.go":     {Data: make([]byte, 2000), Mode: 0o600},
+		"cmd/.hidden":     {},
+		"docs/README.md":  {Data: []byte("hi")},
+		".git/HEAD":       {},
+		"docs/api/foo.md": {},
+	}
+
+	var cmds []tea.Cmd
+	m.Init(func(c ...tea.Cmd) { cmds = append(cmds, c...) })
+	// run runs commands made so far and returns rendered view
+	run := func() []string {
+		for len(cmds) > 0 {
+			cmd := cmds[0]
+			cmds = append(cmds[1:], m.Update(cmd())...)
+		}
+
+		vb := tea.NewViewbox(4, 40)
+		m.View(vb)
+		return vb.Lines()
+	}
+	press := func(k string) {
+		cmds = append(cmds, m.Update(tea.MsgKey{Type: tea.KeyRunes, Runes: []rune(k)})...)
+	}
+
+	assert.Equal(t, []string{
+		"> dr-xr-xr-x      0 B cmd",
+		"  dr-xr-xr-x      0 B docs",
+		"  -rw-r--r--      9 B go.mod",
+		"",
+	}, run())
+
+	press("l")
+	assert.Equal(t, "cmd", m.CurrentDirectory)
+	assert.Equal(t, []string{
+		"> -rw-------   2.0 kB main.go",
+		"",
+		"",
+		"",
+	}, run())
+
+	press("h")
+	run()
+	press("j")
+	press("l")
+	assert.Equal(t, "docs", m.CurrentDirectory)
+	assert.Equal(t, []string{
+		"> dr-xr-xr-x      0 B api",
+		"  ----------      2 B README.md",
+		"",
+		"",
+	}, run())
+
+	press("j")
+	m.Update(tea.MsgKey{Type: tea.KeyEnter})
+	didSelect, path := m.DidSelectFile(tea.MsgKey{Type: tea.KeyEnter})
+	assert.True(t, didSelect)
+	assert.Equal(t, "docs/README.md", path)
+}
+
+func TestMarksFindPreview(t *testing.T) {
+	m := New()
+	m.AutoHeight = false
+	m.Height = 5
+	m.MultiSelect = true
+	m.Preview = true
+	m.FS = fstest.MapFS{
+		"go.mod":         {Data: []byte("module x\n"), Mode: 0o644},
+		"cmd/main.go":    {Data: []byte("package main\n\nfunc main() {}\n")},
+		"docs/README.md": {Data: []byte("hi")},
+		"docs/logo.png":  {Data: []byte{0x89, 'P', 'N', 'G', 0}},
+	}
+
+	var cmds []tea.Cmd
+	m.Init(func(c ...tea.Cmd) { cmds = append(cmds, c...) })
+	run := func() []string {
+		for len(cmds) > 0 {
+			cmd := cmds[0]
+			cmds = append(cmds[1:], m.Update(cmd())...)
+		}
+
+		vb := tea.NewViewbox(4, 60)
+		m.View(vb)
+		return vb.Lines()
+	}
+	press := func(msg tea.MsgKey) {
+		cmds = append(cmds, m.Update(msg)...)
+	}
+	typ := func(s string) {
+		press(tea.MsgKey{Type: tea.KeyRunes, Runes: []rune(s)})
+	}
+
+	t.Run("preview", func(t *testing.T) {
+		assert.Equal(t, []string{
+			"> dr-xr-xr-x      0 B cmd     │ main.go",
+			"  dr-xr-xr-x      0 B docs    │",
+			"  -rw-r--r--      9 B go.mod  │",
+			"                              │",
+		}, run())
+	})
+
+	t.Run("marks", func(t *testing.T) {
+		typ("j")
+		typ("j")
+		typ(" ")
+		run()
+		typ("k")
+		typ("l")
+		run()
+		typ("j")
+		typ(" ")
+		run()
+		assert.Equal(t, []string{"docs/logo.png", "go.mod"}, m.Marked())
+
+		// binary file preview is metadata
+		assert.True(t, strings.HasSuffix(run()[0], "│ Name:     logo.png"))
+
+		msg := tea.MsgKey{Type: tea.KeyEnter}
+		press(msg)
+		didSelect, paths := m.DidSelectFiles(msg)
+		assert.True(t, didSelect)
+		assert.Equal(t, []string{"docs/logo.png", "go.mod"}, paths)
+		assert.Equal(t, "docs", m.CurrentDirectory)
+		m.ClearMarks()
+	})
+
+	t.Run("find", func(t *testing.T) {
+		typ("h")
+		run()
+		typ("/")
+		assert.True(t, m.Finding())
+		// skip cursor blinking, walk is the last command
+		cmds = cmds[len(cmds)-1:]
+		typ("mgo")
+		assert.Equal(t, []string{
+			"/ mgo",
+			"> ----------     29 B cmd/main│ package main",
+			"                              │",
+			"                              │ func main() {}",
+		}, run())
+
+		msg := tea.MsgKey{Type: tea.KeyEnter}
+		press(msg)
+		didSelect, paths := m.DidSelectFiles(msg)
+		assert.True(t, didSelect)
+		assert.Equal(t, []string{"cmd/main.go"}, paths)
+
+		press(tea.MsgKey{Type: tea.KeyEsc})
+		assert.False(t, m.Finding())
+		assert.Equal(t, 3, len(run()[:3]))
+	})
+
+	t.Run("find nothing", func(t *testing.T) {
+		typ("/")
+		cmds = cmds[len(cmds)-1:]
+		typ("xyz")
+		run()
+		press(tea.MsgKey{Type: tea.KeyDown})
+		press(tea.MsgKey{Type: tea.KeyPgDown})
+		assert.Equal(t, []string{
+			"/ xyz",
+			"Bummer. No Files Found.       │",
+			"                              │",
+			"                              │",
+		}, run())
+	})
+}
+
+func TestWatch(t *testing.T) {
+	for name, osFS := range map[string]bool{
+		"os":   true,
+		"poll": false,
+	} {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, name := range []string{"b", "d", "f"} {
+				assert.NoError(t, os.WriteFile(filepath.Join(dir, name), nil, 0o644))
+			}
+
+			m := New()
+			m.AutoHeight = false
+			m.Height = 2
+			m.Watch = true
+			m.WatchInterval = 10 * time.Millisecond
+			if osFS {
+				m.CurrentDirectory = dir
+			} else {
+				m.FS = os.DirFS(dir)
+			}
+
+			// watching commands block, so commands are run concurrently
+			msgs := make(chan tea.Msg, 10)
+			start := func(cmds ...tea.Cmd) {
+				for _, cmd := range cmds {
+					go func() { msgs <- cmd() }()
+				}
+			}
+			// run handles messages until none is received for a while
+			run := func() []string {
+				for {
+					select {
+					case msg := <-msgs:
+						start(m.Update(msg)...)
+						continue
+					case <-time.After(200 * time.Millisecond):
+					}
+					break
+				}
+
+				vb := tea.NewViewbox(2, 40)
+				m.View(vb)
+				lines := vb.Lines()
+				for i, line := range lines {
+					lines[i] = line[strings.LastIndex(line, " ")+1:]
+				}
+				return lines
+			}
+
+			m.Init(start)
+			assert.Equal(t, []string{"b", "d"}, run())
+			start(m.Update(tea.MsgKey{Type: tea.KeyRunes, Runes: []rune("j")})...)
+			start(m.Update(tea.MsgKey{Type: tea.KeyRunes, Runes: []rune("j")})...)
+			assert.Equal(t, []string{"d", "f"}, run())
+			assert.Equal(t, 2, m.selected)
+
+			// highlighted entry and scroll window are kept
+			assert.NoError(t, os.WriteFile(filepath.Join(dir, "a"), nil, 0o644))
+			assert.NoError(t, os.WriteFile(filepath.Join(dir, "g"), nil, 0o644))
+			assert.Equal(t, []string{"d", "f"}, run())
+			assert.Equal(t, 3, m.selected)
+
+			assert.NoError(t, os.Remove(filepath.Join(dir, "f")))
+			assert.Equal(t, []string{"d", "g"}, run())
+			assert.Equal(t, 3, m.selected)
+
+			// watching is stopped
+			m.Watch = false
+			start(m.Update(nil)...)
+			assert.NoError(t, os.Remove(filepath.Join(dir, "g")))
+			assert.Equal(t, []string{"d", "g"}, run())
+
+			// watching is stopped when picker is closed
+			m.Watch = true
+			start(m.Update(nil)...)
+			w := m.watcher
+			m.Close()
+			assert.False(t, m.Watch)
+			assert.Equal(t, nil, m.watcher)
+			_, open := <-w.done
+			assert.False(t, open)
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package filepicker
+
+import (
+	"io/fs"
+	"strings"
+	"unsafe"
+
+	"github.com/sahilm/fuzzy"
+
+	"github.com/rprtr258/tea"
+	"github.com/rprtr258/tea/components/key"
+)
+
+// _maxFound is maximum number of entries found in subtree, so that finding
+// in huge trees does not take forever.
+const _maxFound = 10_000
+
+type msgWalk struct {
+	id      uintptr
+	dir     string
+	entries []entry
+}
+
+// cmdWalk reads entries of dir subtree.
+func (m *Model) cmdWalk(dir string, showHidden bool) tea.Cmd {
+	fsys := m.fsys()
+	return func() tea.Msg {
+		var entries []entry
+		err := fs.WalkDir(fsys, dir, func(name string, d fs.DirEntry, err error) error {
+			switch {
+			case name == dir:
+				return err
+			case err != nil:
+				// unreadable subdirectories are skipped
+				return nil
+			case !showHidden && isHidden(d.Name()):
+				if d.IsDir() {
+					return fs.SkipDir
+				}
+				return nil
+			case len(entries) == _maxFound:
+				return fs.SkipAll
+			}
+
+			rel := name
+			if dir != "." {
+				rel = strings.TrimPrefix(strings.TrimPrefix(name, dir), "/")
+			}
+			if e, err := readEntry(fsys, name, rel, d); err == nil {
+				entries = append(entries, e)
+			}
+			return nil
+		})
+		if err != nil {
+			return msgError{err}
+		}
+
+		return msgWalk{
+			id:      uintptr(unsafe.Pointer(m)),
+			dir:     dir,
+			entries: entries,
+		}
+	}
+}
+
+// startFind starts finding entries in current directory subtree.
+func (m *Model) startFind() []tea.Cmd {
+	m.finding = true
+	m.all = nil
+	m.files = nil
+	m.query.Reset()
+	m.selected, m.min, m.max = 0, 0, m.Height-1
+	return append(m.query.Focus(), m.cmdWalk(m.CurrentDirectory, m.ShowHidden))
+}
+
+// stopFind stops finding entries.
+func (m *Model) stopFind() {
+	m.finding = false
+	m.query.Blur()
+	m.all = nil
+}
+
+// Finding returns whether entries are being found by query.
+func (m *Model) Finding() bool {
+	return m.finding
+}
+
+// walked handles entries found in subtree.
+func (m *Model) walked(msg msgWalk) {
+	if msg.id != uintptr(unsafe.Pointer(m)) || !m.finding || msg.dir != m.CurrentDirectory {
+		return
+	}
+
+	m.all = msg.entries
+	m.find()
+}
+
+// find shows found entries which paths match query.
+func (m *Model) find() {
+	m.selected, m.min, m.max = 0, 0, m.Height-1
+	if m.query.Value() == "" {
+		m.files = m.all
+		return
+	}
+
+	paths := make([]string, len(m.all))
+	for i, e := range m.all {
+		paths[i] = e.path
+	}
+
+	m.files = nil
+	for _, match := range fuzzy.Find(m.query.Value(), paths) {
+		e := m.all[match.Index]
+		e.matches = match.MatchedIndexes
+		m.files = append(m.files, e)
+	}
+}
+
+// updateFind handles keys while finding. Keys not typing query, such as
+// arrows, move between found entries, open and mark them.
+func (m *Model) updateFind(msg tea.MsgKey) []tea.Cmd {
+	switch {
+	case key.Matches(msg, m.KeyMap.CancelFind):
+		m.stopFind()
+		m.selected, m.min, m.max = 0, 0, m.Height-1
+		return []tea.Cmd{m.cmdReadDir(m.CurrentDirectory, m.ShowHidden)}
+	case msg.Type != tea.KeyRunes && msg.Type != tea.KeySpace && !key.Matches(msg, m.KeyMap.Back) &&
+		key.Matches(msg, m.KeyMap.Up, m.KeyMap.Down, m.KeyMap.PageUp, m.KeyMap.PageDown, m.KeyMap.Open, m.KeyMap.Mark):
+		return m.updateKey(msg)
+	}
+
+	var cmds []tea.Cmd
+	query := m.query.Value()
+	m.query.Update(msg, func(c ...tea.Cmd) { cmds = append(cmds, c...) })
+	if m.query.Value() != query {
+		m.find()
+	}
+	return cmds
+}
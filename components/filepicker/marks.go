@@ -0,0 +1,74 @@
+package filepicker
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/rprtr258/tea"
+	"github.com/rprtr258/tea/components/key"
+)
+
+// markKey returns key of entry in marks, which is its path, so that marks
+// are kept while browsing other directories.
+func (m *Model) markKey(f entry) string {
+	return m.join(m.CurrentDirectory, f.path)
+}
+
+// isMarked returns whether entry is marked.
+func (m *Model) isMarked(f entry) bool {
+	return m.marked[m.markKey(f)]
+}
+
+// selectable returns whether entry can be selected.
+func (m *Model) selectable(f entry) bool {
+	return f.isDir && m.DirAllowed || !f.isDir && m.FileAllowed && m.canSelect(f.Name())
+}
+
+// ToggleMark toggles mark of highlighted entry, if MultiSelect is set and
+// entry can be selected.
+func (m *Model) ToggleMark() {
+	if !m.MultiSelect || len(m.files) == 0 {
+		return
+	}
+
+	f := m.files[m.selected]
+	if !m.selectable(f) {
+		return
+	}
+
+	if m.marked == nil {
+		m.marked = map[string]bool{}
+	}
+	if k := m.markKey(f); m.marked[k] {
+		delete(m.marked, k)
+	} else {
+		m.marked[k] = true
+	}
+}
+
+// Marked returns sorted paths of marked entries, including ones in other
+// directories.
+func (m *Model) Marked() []string {
+	return slices.Sorted(maps.Keys(m.marked))
+}
+
+// ClearMarks unmarks all entries.
+func (m *Model) ClearMarks() {
+	clear(m.marked)
+}
+
+// DidSelectFiles returns whether user has selected files (on this msg), which
+// are marked ones, or highlighted one if none is marked.
+func (m *Model) DidSelectFiles(msg tea.Msg) (bool, []string) {
+	if len(m.marked) == 0 {
+		if didSelect, path := m.DidSelectFile(msg); didSelect {
+			return true, []string{path}
+		}
+		return false, nil
+	}
+
+	if msg, ok := msg.(tea.MsgKey); ok && key.Matches(msg, m.KeyMap.Select) {
+		return true, m.Marked()
+	}
+	return false, nil
+}
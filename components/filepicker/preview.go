@@ -0,0 +1,205 @@
+package filepicker
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+	"unicode/utf8"
+	"unsafe"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/dustin/go-humanize"
+
+	"github.com/rprtr258/tea"
+	"github.com/rprtr258/tea/styles"
+)
+
+// _previewSize is maximum number of bytes of text file read for preview.
+const _previewSize = 64 << 10
+
+// token is a piece of highlighted text.
+type token struct {
+	text string
+	typ  chroma.TokenType
+}
+
+// preview is content of entry shown in preview pane: text file lines,
+// directory entries or, for other files, metadata.
+type preview struct {
+	// path of entry in file system
+	path   string
+	loaded bool
+	info   fs.FileInfo
+	err    error
+
+	dir     bool
+	entries []fs.DirEntry
+	// lines is nil if file is not text
+	lines [][]token
+}
+
+type msgPreview struct {
+	id      uintptr
+	preview preview
+}
+
+// cmdPreview returns command loading preview of highlighted entry, nil if it
+// is loaded already or nothing is highlighted.
+func (m *Model) cmdPreview() tea.Cmd {
+	if !m.Preview {
+		return nil
+	}
+	if m.selected < 0 || m.selected >= len(m.files) {
+		m.preview = nil
+		return nil
+	}
+
+	f := m.files[m.selected]
+	name := m.join(m.CurrentDirectory, f.path)
+	if m.preview != nil && m.preview.path == name {
+		return nil
+	}
+
+	m.preview = &preview{path: name}
+	fsys := m.fsys()
+	return func() tea.Msg {
+		return msgPreview{
+			id:      uintptr(unsafe.Pointer(m)),
+			preview: loadPreview(fsys, name, f),
+		}
+	}
+}
+
+// previewed handles loaded preview, unless other entry is highlighted since.
+func (m *Model) previewed(msg msgPreview) {
+	if msg.id != uintptr(unsafe.Pointer(m)) || m.preview == nil || m.preview.path != msg.preview.path {
+		return
+	}
+
+	m.preview = &msg.preview
+}
+
+// loadPreview reads preview of entry with given name.
+func loadPreview(fsys fs.FS, name string, f entry) preview {
+	p := preview{path: name, loaded: true, info: f.info, dir: f.isDir}
+	if f.isDir {
+		p.entries, p.err = fs.ReadDir(fsys, name)
+		return p
+	}
+
+	// only regular files are read, e.g. reading pipe could block forever
+	if info, err := fs.Stat(fsys, name); err != nil || !info.Mode().IsRegular() {
+		return p
+	}
+
+	file, err := fsys.Open(name)
+	if err != nil {
+		p.err = err
+		return p
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, _previewSize))
+	if err != nil {
+		p.err = err
+		return p
+	}
+
+	if len(data) == _previewSize {
+		// drop rune cut by limit
+		for i := 0; i < utf8.UTFMax && !utf8.Valid(data); i++ {
+			data = data[:len(data)-1]
+		}
+	}
+	if !utf8.Valid(data) || bytes.IndexByte(data, 0) != -1 {
+		// binary file
+		return p
+	}
+
+	p.lines = highlight(path.Base(name), strings.ReplaceAll(string(data), "\t", "    "))
+	return p
+}
+
+// highlight splits text into lines of tokens according to syntax of
+// language guessed by file name.
+func highlight(name, text string) [][]token {
+	lexer := lexers.Match(name)
+	if lexer == nil {
+		lexer = lexers.Fallback
+	}
+
+	lines := [][]token{nil}
+	it, err := chroma.Coalesce(lexer).Tokenise(nil, text)
+	if err != nil {
+		lines = nil
+		for _, line := range strings.Split(text, "\n") {
+			lines = append(lines, []token{{text: line, typ: chroma.Text}})
+		}
+		return lines
+	}
+
+	for t := it(); t != chroma.EOF; t = it() {
+		for i, part := range strings.Split(t.Value, "\n") {
+			if i > 0 {
+				lines = append(lines, nil)
+			}
+			if part != "" {
+				lines[len(lines)-1] = append(lines[len(lines)-1], token{text: part, typ: t.Type})
+			}
+		}
+	}
+	return lines
+}
+
+// highlightStyle returns style of token of given type.
+func (m *Model) highlightStyle(t chroma.TokenType) styles.Style {
+	for _, t := range []chroma.TokenType{t, t.SubCategory(), t.Category()} {
+		if st, ok := m.Styles.Highlight[t]; ok {
+			return st
+		}
+	}
+	return styles.Style{}
+}
+
+// viewPreview renders preview of highlighted entry.
+func (m *Model) viewPreview(vb tea.Viewbox) {
+	p := m.preview
+	if p == nil || !p.loaded {
+		return
+	}
+
+	switch {
+	case p.err != nil:
+		vb.Styled(m.Styles.DisabledFile).WriteLine(p.err.Error())
+	case p.dir:
+		if len(p.entries) == 0 {
+			vb.Styled(m.Styles.EmptyDirectory).WriteLine("Empty directory.")
+		}
+		for y, e := range p.entries[:min(len(p.entries), vb.Height)] {
+			vbEntry := vb.Row(y)
+			if e.IsDir() {
+				vbEntry = vbEntry.Styled(m.Styles.Directory)
+			}
+			vbEntry.WriteLine(e.Name())
+		}
+	case p.lines != nil:
+		for y, line := range p.lines[:min(len(p.lines), vb.Height)] {
+			x := 0
+			for _, t := range line {
+				x += vb.Row(y).PaddingLeft(x).Styled(m.highlightStyle(t.typ)).WriteLine(t.text)
+			}
+		}
+	default:
+		for y, line := range []string{
+			"Name:     " + p.info.Name(),
+			"Size:     " + humanize.Bytes(uint64(p.info.Size())),
+			"Mode:     " + p.info.Mode().String(),
+			"Modified: " + p.info.ModTime().Format("2006-01-02 15:04"),
+		} {
+			vb.Row(y).Styled(m.Styles.Permission).WriteLine(line)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package filepicker
+
+import (
+	"fmt"
+	"io/fs"
+	"slices"
+	"strings"
+	"time"
+	"unsafe"
+
+	"github.com/rprtr258/tea"
+)
+
+// _watchInterval is default interval of polling directory for changes.
+const _watchInterval = time.Second
+
+// watcher notifies about changes in directory until stopped.
+type watcher struct {
+	dir string
+	// changed receives a value when directory is changed, changes are
+	// coalesced until it is received
+	changed chan struct{}
+	done    chan struct{}
+}
+
+type msgWatch struct {
+	id      uintptr
+	watcher *watcher
+}
+
+// notify notifies about change of directory.
+func (w *watcher) notify() {
+	select {
+	case w.changed <- struct{}{}:
+	default:
+	}
+}
+
+// stop stops watching directory.
+func (w *watcher) stop() {
+	close(w.done)
+}
+
+// poll polls directory every interval, until watcher is stopped.
+func (w *watcher) poll(fsys fs.FS, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	last := dirState(fsys, w.dir)
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-ticker.C:
+		}
+
+		if state := dirState(fsys, w.dir); state != last {
+			last = state
+			w.notify()
+		}
+	}
+}
+
+// dirState returns summary of directory entries, which is changed when
+// entries are added, removed or modified.
+func dirState(fsys fs.FS, dir string) string {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return err.Error()
+	}
+
+	var sb strings.Builder
+	for _, e := range entries {
+		sb.WriteString(e.Name())
+		if info, err := e.Info(); err == nil {
+			fmt.Fprintf(&sb, " %v %d %d", info.Mode(), info.Size(), info.ModTime().UnixNano())
+		}
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// watch starts watching dir, by OS notifications if OS file system is
+// browsed and they are supported, by polling otherwise.
+func (m *Model) watch(dir string) *watcher {
+	w := &watcher{
+		dir:     dir,
+		changed: make(chan struct{}, 1),
+		done:    make(chan struct{}),
+	}
+	if m.FS == nil && w.watchOS() {
+		return w
+	}
+
+	interval := m.WatchInterval
+	if interval <= 0 {
+		interval = _watchInterval
+	}
+	go w.poll(m.fsys(), interval)
+	return w
+}
+
+// cmdWait returns command waiting for change of directory watched by w.
+func (m *Model) cmdWait(w *watcher) tea.Cmd {
+	return func() tea.Msg {
+		select {
+		case <-w.changed:
+			return msgWatch{
+				id:      uintptr(unsafe.Pointer(m)),
+				watcher: w,
+			}
+		case <-w.done:
+			return nil
+		}
+	}
+}
+
+// cmdWatchDir starts watching current directory, stopping watching previous
+// one, if directory is changed or Watch is toggled. Returns nil if watching
+// is not changed.
+func (m *Model) cmdWatchDir() tea.Cmd {
+	if m.Watch == (m.watcher != nil) && (m.watcher == nil || m.watcher.dir == m.CurrentDirectory) {
+		return nil
+	}
+
+	if m.watcher != nil {
+		m.watcher.stop()
+		m.watcher = nil
+	}
+	if !m.Watch {
+		return nil
+	}
+
+	m.watcher = m.watch(m.CurrentDirectory)
+	return m.cmdWait(m.watcher)
+}
+
+// Close stops watching current directory and turns Watch off, so that
+// picker which is no longer used does not leak watching goroutines.
+func (m *Model) Close() {
+	m.Watch = false
+	if m.watcher != nil {
+		m.watcher.stop()
+		m.watcher = nil
+	}
+}
+
+// cmdRefresh rereads entries of current directory.
+func (m *Model) cmdRefresh() tea.Cmd {
+	cmd := m.cmdReadDir(m.CurrentDirectory, m.ShowHidden)
+	return func() tea.Msg {
+		msg := cmd()
+		if msg, ok := msg.(msgReadDir); ok {
+			msg.refresh = true
+			return msg
+		}
+		return msg
+	}
+}
+
+// watched handles change of watched directory.
+func (m *Model) watched(msg msgWatch) []tea.Cmd {
+	if msg.id != uintptr(unsafe.Pointer(m)) || msg.watcher != m.watcher {
+		// previous directory has changed
+		return nil
+	}
+
+	cmds := []tea.Cmd{m.cmdWait(m.watcher)}
+	if !m.finding {
+		// found entries are not refreshed, as walking subtree is slow
+		cmds = append(cmds, m.cmdRefresh())
+	}
+	return cmds
+}
+
+// refreshed replaces entries with reread ones, keeping highlighted entry and
+// scroll window, unless it is shrunk. Whole directory is reread on purpose
+// instead of patching entries changed: notifications do not tell what is
+// changed when polling, and rereading keeps entries sorted and filtered the
+// same way as on entering directory. State of entries is kept by path.
+func (m *Model) refreshed(entries []entry) {
+	if m.selected < len(m.files) {
+		f := m.files[m.selected]
+		if i := slices.IndexFunc(entries, func(e entry) bool { return e.path == f.path }); i != -1 {
+			m.selected = i
+			if e := entries[i]; m.preview != nil && (e.info.Size() != f.info.Size() || !e.info.ModTime().Equal(f.info.ModTime())) {
+				// highlighted file is modified, so it is previewed again
+				m.preview = nil
+			}
+		}
+	}
+	m.files = entries
+
+	m.selected = max(min(m.selected, len(m.files)-1), 0)
+	if m.max >= len(m.files) {
+		// scroll up, so that window is filled with entries
+		delta := min(m.min, m.max-max(len(m.files)-1, 0))
+		m.min -= delta
+		m.max -= delta
+	}
+	if m.selected < m.min {
+		m.max -= m.min - m.selected
+		m.min = m.selected
+	}
+	if m.selected > m.max {
+		m.min += m.selected - m.max
+		m.max = m.selected
+	}
+}
@@ -0,0 +1,43 @@
+//go:build linux
+
+package filepicker
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// watchOS starts watching directory by inotify, returns false if it is not
+// possible.
+func (w *watcher) watchOS() bool {
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC | unix.IN_NONBLOCK)
+	if err != nil {
+		return false
+	}
+
+	const mask = unix.IN_CREATE | unix.IN_DELETE | unix.IN_MOVED_FROM | unix.IN_MOVED_TO |
+		unix.IN_MODIFY | unix.IN_ATTRIB | unix.IN_DELETE_SELF | unix.IN_MOVE_SELF
+	if _, err := unix.InotifyAddWatch(fd, w.dir, mask); err != nil {
+		unix.Close(fd)
+		return false
+	}
+
+	// non-blocking file is read through runtime poller, so that closing it
+	// interrupts read
+	f := os.NewFile(uintptr(fd), "inotify")
+	go func() {
+		<-w.done
+		f.Close()
+	}()
+	go func() {
+		buf := make([]byte, 4096)
+		for {
+			if _, err := f.Read(buf); err != nil {
+				return
+			}
+			w.notify()
+		}
+	}()
+	return true
+}
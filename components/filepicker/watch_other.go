@@ -0,0 +1,9 @@
+//go:build !linux
+
+package filepicker
+
+// watchOS returns false, as OS notifications are not supported, so directory
+// is polled.
+func (w *watcher) watchOS() bool {
+	return false
+}
@@ -0,0 +1,475 @@
+package form
+
+import (
+	"slices"
+
+	"github.com/rprtr258/tea"
+	"github.com/rprtr258/tea/components/headless/selector"
+	"github.com/rprtr258/tea/components/headless/selector_multiple"
+	"github.com/rprtr258/tea/components/headless/toggle"
+	"github.com/rprtr258/tea/components/key"
+	"github.com/rprtr258/tea/components/textarea"
+	"github.com/rprtr258/tea/components/textinput"
+)
+
+// Field is a single labeled form field bound to a variable, which is updated
+// as field is edited.
+type Field interface {
+	// Label returns text shown above the field.
+	Label() string
+	// Focus is called when field gets focus.
+	Focus() []tea.Cmd
+	// Blur is called when field loses focus.
+	Blur()
+	// Captures reports whether focused field handles key itself, so it is not
+	// used for moving between fields, e.g. enter in multiline text.
+	Captures(msg tea.MsgKey) bool
+	// Update handles message while field is focused.
+	Update(msg tea.Msg, f func(...tea.Cmd))
+	// View renders the field, without label.
+	View(vb tea.Viewbox, s Styles, focused bool)
+	// Height returns number of lines field takes.
+	Height() int
+	// Validate checks field value, returns nil if it is valid.
+	Validate() error
+}
+
+// InputField is a single line text field.
+type InputField struct {
+	label string
+	// Input is the underlying text input, it can be configured directly, e.g.
+	// to set placeholder, mask or echo mode.
+	Input textinput.Model
+	// Check validates text in addition to parsing it.
+	Check func(string) error
+
+	// set parses text and stores it to bound variable.
+	set func(string) error
+}
+
+var _ Field = (*InputField)(nil)
+
+// Input creates text field bound to value.
+func Input(label string, value *string) *InputField {
+	return newInputField(label, *value, func(s string) error {
+		*value = s
+		return nil
+	})
+}
+
+// Parsed creates text field parsing its text into value, e.g. with
+// textinput.Int(). Value is set only when text parses.
+func Parsed[V any](label string, value *V, parse textinput.Parser[V], initial string) *InputField {
+	return newInputField(label, initial, func(s string) error {
+		v, err := parse(s)
+		if err != nil {
+			return err
+		}
+
+		*value = v
+		return nil
+	})
+}
+
+func newInputField(label, initial string, set func(string) error) *InputField {
+	input := textinput.New()
+	input.Prompt = ""
+	input.SetValue(initial)
+	return &InputField{
+		label: label,
+		Input: input,
+		set:   set,
+	}
+}
+
+func (i *InputField) Label() string                         { return i.label }
+func (i *InputField) Focus() []tea.Cmd                      { return i.Input.Focus() }
+func (i *InputField) Blur()                                 { i.Input.Blur() }
+func (i *InputField) Captures(tea.MsgKey) bool              { return false }
+func (i *InputField) Height() int                           { return 1 }
+func (i *InputField) View(vb tea.Viewbox, _ Styles, _ bool) { i.Input.View(vb) }
+
+func (i *InputField) Update(msg tea.Msg, f func(...tea.Cmd)) {
+	i.Input.Update(msg, f)
+	_ = i.set(i.Input.Value())
+}
+
+func (i *InputField) Validate() error {
+	value := i.Input.Value()
+	if err := i.set(value); err != nil {
+		return err
+	}
+	if i.Check != nil {
+		return i.Check(value)
+	}
+	return nil
+}
+
+// TextField is a multiline text field.
+type TextField struct {
+	label string
+	// Area is the underlying text area, it can be configured directly.
+	Area textarea.Model
+	// Check validates text.
+	Check func(string) error
+
+	value *string
+}
+
+var _ Field = (*TextField)(nil)
+
+// Text creates multiline text field bound to value.
+func Text(label string, value *string) *TextField {
+	area := textarea.New()
+	area.ShowLineNumbers = false
+	area.SetHeight(3)
+	area.SetValue(*value)
+	return &TextField{
+		label: label,
+		Area:  area,
+		value: value,
+	}
+}
+
+func (t *TextField) Label() string    { return t.label }
+func (t *TextField) Focus() []tea.Cmd { return t.Area.Focus() }
+func (t *TextField) Blur()            { t.Area.Blur() }
+func (t *TextField) Height() int      { return t.Area.Height() }
+
+func (t *TextField) Captures(msg tea.MsgKey) bool {
+	return msg.Type == tea.KeyEnter || msg.Type == tea.KeyUp || msg.Type == tea.KeyDown
+}
+
+func (t *TextField) Update(msg tea.Msg, f func(...tea.Cmd)) {
+	t.Area.Update(msg, f)
+	*t.value = t.Area.Value()
+}
+
+func (t *TextField) View(vb tea.Viewbox, _ Styles, _ bool) {
+	t.Area.View(vb)
+}
+
+func (t *TextField) Validate() error {
+	if t.Check != nil {
+		return t.Check(t.Area.Value())
+	}
+	return nil
+}
+
+// SelectField is a dropdown choosing one of options.
+type SelectField[V comparable] struct {
+	label    string
+	selector *selector.Selector[V]
+	// Required makes field invalid until option is chosen.
+	Required bool
+
+	value *V
+}
+
+var _ Field = (*SelectField[int])(nil)
+
+// Select creates dropdown bound to value. Option equal to value is selected
+// initially.
+func Select[V comparable](label string, value *V, options ...selector.Item[V]) *SelectField[V] {
+	selected := slices.IndexFunc(options, func(item selector.Item[V]) bool {
+		return item.Value == *value
+	})
+	return &SelectField[V]{
+		label:    label,
+		selector: selector.New(options, selected, -1, nil),
+		value:    value,
+	}
+}
+
+func (s *SelectField[V]) Label() string    { return s.label }
+func (s *SelectField[V]) Focus() []tea.Cmd { return nil }
+func (s *SelectField[V]) Blur()            { s.selector.Hide() }
+
+func (s *SelectField[V]) Captures(msg tea.MsgKey) bool {
+	if s.selector.IsOpen() {
+		return true
+	}
+	return key.Matches(msg, keyToggle, keyLeft, keyRight)
+}
+
+func (s *SelectField[V]) Update(msg tea.Msg, _ func(...tea.Cmd)) {
+	msgKey, ok := msg.(tea.MsgKey)
+	if !ok {
+		return
+	}
+
+	switch {
+	case !s.selector.IsOpen() && key.Matches(msgKey, keyToggle):
+		s.highlight(max(s.selector.SelectedIndex(), 0))
+	case !s.selector.IsOpen() && key.Matches(msgKey, keyLeft, keyRight):
+		// cycle through options without opening dropdown
+		n := len(s.selector.Items())
+		i := s.selector.SelectedIndex()
+		if key.Matches(msgKey, keyLeft) {
+			i = (max(i, 0) - 1 + n) % n
+		} else {
+			i = (i + 1) % n
+		}
+		s.highlight(i)
+		s.selector.Select()
+		s.selector.Hide()
+	case key.Matches(msgKey, keyUp):
+		s.selector.HighlightPrev()
+	case key.Matches(msgKey, keyDown):
+		s.selector.HighlightNext()
+	case key.Matches(msgKey, keyToggle, keyChoose):
+		s.selector.Select()
+		s.selector.Hide()
+	case msgKey.Type == tea.KeyEsc:
+		s.selector.Hide()
+	}
+
+	if v, ok := s.selector.Selected(); ok {
+		*s.value = v
+	}
+}
+
+// highlight opens dropdown highlighting option with given index.
+func (s *SelectField[V]) highlight(i int) {
+	s.selector.Show()
+	s.selector.ResetHighlight()
+	for range i {
+		s.selector.HighlightNext()
+	}
+}
+
+func (s *SelectField[V]) Height() int {
+	if s.selector.IsOpen() {
+		return 1 + len(s.selector.Items())
+	}
+	return 1
+}
+
+func (s *SelectField[V]) View(vb tea.Viewbox, st Styles, focused bool) {
+	label := "(none)"
+	if item, ok := s.selector.SelectedItem(); ok {
+		label = item.Label
+	}
+	style := st.Option
+	if focused {
+		style = st.FocusedOption
+	}
+	vb.Row(0).Styled(style).WriteLine("‹ " + label + " ›")
+
+	if !s.selector.IsOpen() {
+		return
+	}
+
+	for i, item := range s.selector.Items() {
+		row := vb.Row(1 + i)
+		if i == s.selector.HighlightedIndex() {
+			row.Styled(st.FocusedOption).WriteLine("> " + item.Label)
+		} else {
+			row.Styled(st.Option).WriteLine("  " + item.Label)
+		}
+	}
+}
+
+func (s *SelectField[V]) Validate() error {
+	if _, ok := s.selector.Selected(); s.Required && !ok {
+		return ErrRequired
+	}
+	return nil
+}
+
+// ConfirmField is a checkbox.
+type ConfirmField struct {
+	label  string
+	toggle *toggle.Toggle
+	// Required makes field invalid until it is checked, e.g. for accepting
+	// terms.
+	Required bool
+
+	value *bool
+}
+
+var _ Field = (*ConfirmField)(nil)
+
+// Confirm creates checkbox bound to value.
+func Confirm(label string, value *bool) *ConfirmField {
+	t := toggle.New()
+	t.Set(*value)
+	return &ConfirmField{
+		label:  label,
+		toggle: t,
+		value:  value,
+	}
+}
+
+func (c *ConfirmField) Label() string    { return "" }
+func (c *ConfirmField) Focus() []tea.Cmd { return nil }
+func (c *ConfirmField) Blur()            {}
+func (c *ConfirmField) Height() int      { return 1 }
+
+func (c *ConfirmField) Captures(msg tea.MsgKey) bool {
+	return key.Matches(msg, keyToggle, keyLeft, keyRight)
+}
+
+func (c *ConfirmField) Update(msg tea.Msg, _ func(...tea.Cmd)) {
+	if msgKey, ok := msg.(tea.MsgKey); ok && c.Captures(msgKey) {
+		c.toggle.Toggle()
+		*c.value = c.toggle.Value()
+	}
+}
+
+func (c *ConfirmField) View(vb tea.Viewbox, st Styles, focused bool) {
+	box := "[ ] "
+	if c.toggle.Value() {
+		box = "[x] "
+	}
+	style := st.Option
+	if focused {
+		style = st.FocusedOption
+	}
+	vb.Styled(style).WriteLine(box + c.label)
+}
+
+func (c *ConfirmField) Validate() error {
+	if c.Required && !c.toggle.Value() {
+		return ErrRequired
+	}
+	return nil
+}
+
+// MultiSelectField is a list of checkboxes choosing several options.
+type MultiSelectField[V comparable] struct {
+	label    string
+	selector *selector_multiple.Selector[V]
+	// Min and Max limit number of chosen options, Max is not checked if zero.
+	Min, Max int
+
+	value *[]V
+}
+
+var _ Field = (*MultiSelectField[int])(nil)
+
+// MultiSelect creates list of checkboxes bound to value. Options contained
+// in value are chosen initially.
+func MultiSelect[V comparable](label string, value *[]V, options ...selector_multiple.Item[V]) *MultiSelectField[V] {
+	s := (&selector_multiple.Options[V]{}).WithItems(options...).Build()
+	for i, option := range options {
+		s.HighlightNext()
+		if slices.Contains(*value, option.Value) {
+			s.AddHighlighted()
+		}
+		if i == len(options)-1 {
+			s.HighlightFirst()
+		}
+	}
+	return &MultiSelectField[V]{
+		label:    label,
+		selector: s,
+		value:    value,
+	}
+}
+
+func (s *MultiSelectField[V]) Label() string    { return s.label }
+func (s *MultiSelectField[V]) Focus() []tea.Cmd { return nil }
+func (s *MultiSelectField[V]) Blur()            {}
+func (s *MultiSelectField[V]) Height() int      { return len(s.selector.Items()) }
+
+// Captures up and down keys unless highlight is at the edge of the list, so
+// that they move to adjacent fields.
+func (s *MultiSelectField[V]) Captures(msg tea.MsgKey) bool {
+	i := s.selector.HighlightedIndex()
+	switch {
+	case key.Matches(msg, keyUp):
+		return i > 0
+	case key.Matches(msg, keyDown):
+		return i < len(s.selector.Items())-1
+	default:
+		return key.Matches(msg, keyToggle)
+	}
+}
+
+func (s *MultiSelectField[V]) Update(msg tea.Msg, _ func(...tea.Cmd)) {
+	msgKey, ok := msg.(tea.MsgKey)
+	if !ok {
+		return
+	}
+
+	switch {
+	case key.Matches(msgKey, keyUp):
+		s.selector.HighlightPrev()
+	case key.Matches(msgKey, keyDown):
+		s.selector.HighlightNext()
+	case key.Matches(msgKey, keyToggle):
+		s.selector.ToggleHighlighted()
+		*s.value = s.selector.Selected()
+	}
+}
+
+func (s *MultiSelectField[V]) View(vb tea.Viewbox, st Styles, focused bool) {
+	for i, item := range s.selector.Items() {
+		box := "[ ] "
+		if s.selector.IsSelected(i) {
+			box = "[x] "
+		}
+
+		style := st.Option
+		if focused && i == s.selector.HighlightedIndex() {
+			style = st.FocusedOption
+		}
+		vb.Row(i).Styled(style).WriteLine(box + item.Label)
+	}
+}
+
+func (s *MultiSelectField[V]) Validate() error {
+	n := s.selector.SelectedCount()
+	switch {
+	case n < s.Min:
+		return &CountError{Min: s.Min, Max: s.Max}
+	case s.Max > 0 && n > s.Max:
+		return &CountError{Min: s.Min, Max: s.Max}
+	}
+	return nil
+}
+
+// ButtonField is a button submitting or aborting the form.
+type ButtonField struct {
+	label  string
+	submit bool
+}
+
+var _ Field = (*ButtonField)(nil)
+
+// Submit creates button submitting the form.
+func Submit(label string) *ButtonField {
+	return &ButtonField{label: label, submit: true}
+}
+
+// Cancel creates button aborting the form.
+func Cancel(label string) *ButtonField {
+	return &ButtonField{label: label, submit: false}
+}
+
+func (b *ButtonField) Label() string                    { return "" }
+func (b *ButtonField) Focus() []tea.Cmd                 { return nil }
+func (b *ButtonField) Blur()                            {}
+func (b *ButtonField) Captures(tea.MsgKey) bool         { return false }
+func (b *ButtonField) Update(tea.Msg, func(...tea.Cmd)) {}
+func (b *ButtonField) Height() int                      { return 1 }
+func (b *ButtonField) Validate() error                  { return nil }
+
+func (b *ButtonField) View(vb tea.Viewbox, st Styles, focused bool) {
+	style := st.Button
+	if focused {
+		style = st.FocusedButton
+	}
+	vb.Styled(style).WriteLine("[ " + b.label + " ]")
+}
+
+// keys used by fields themselves
+var (
+	keyToggle = key.Binding{Keys: []string{" "}}
+	keyChoose = key.Binding{Keys: []string{"enter"}}
+	keyUp     = key.Binding{Keys: []string{"up", "ctrl+p"}}
+	keyDown   = key.Binding{Keys: []string{"down", "ctrl+n"}}
+	keyLeft   = key.Binding{Keys: []string{"left", "h"}}
+	keyRight  = key.Binding{Keys: []string{"right", "l"}}
+)
@@ -0,0 +1,391 @@
+// Package form provides a component composing labeled fields into a form,
+// with focus traversal, validation, pages and submit flow. Fields are bound
+// to variables, usually fields of a struct, which is returned on submit.
+package form
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/rprtr258/tea"
+	"github.com/rprtr258/tea/components/key"
+	"github.com/rprtr258/tea/styles"
+)
+
+// ErrRequired is returned by fields which must be filled.
+var ErrRequired = errors.New("required")
+
+// CountError is returned by multi-select field when number of chosen options
+// is out of bounds.
+type CountError struct {
+	Min, Max int
+}
+
+func (e *CountError) Error() string {
+	switch {
+	case e.Max == 0:
+		return fmt.Sprintf("choose at least %d", e.Min)
+	case e.Min == e.Max:
+		return fmt.Sprintf("choose %d", e.Min)
+	default:
+		return fmt.Sprintf("choose from %d to %d", e.Min, e.Max)
+	}
+}
+
+// FieldError is error of cross-field validation related to a field, it is
+// shown under that field.
+type FieldError struct {
+	Field Field
+	Err   error
+}
+
+func (e *FieldError) Error() string { return e.Err.Error() }
+func (e *FieldError) Unwrap() error { return e.Err }
+
+// MsgSubmitted is sent when form is submitted with valid value.
+type MsgSubmitted[T any] struct {
+	Value T
+}
+
+// MsgAborted is sent when form is aborted.
+type MsgAborted struct{}
+
+// State is state of the form.
+type State int
+
+const (
+	StateEditing State = iota
+	StateSubmitted
+	StateAborted
+)
+
+// Group is a page of fields shown together.
+type Group struct {
+	Title  string
+	Fields []Field
+}
+
+// KeyMap is the key bindings for moving between fields.
+type KeyMap struct {
+	Next     key.Binding
+	Prev     key.Binding
+	NextPage key.Binding
+	PrevPage key.Binding
+	// Choose activates focused button, otherwise moves to the next field.
+	Choose key.Binding
+	Abort  key.Binding
+}
+
+// DefaultKeyMap is the default set of key bindings for the form.
+var DefaultKeyMap = KeyMap{
+	Next:     key.Binding{Keys: []string{"tab", "down"}},
+	Prev:     key.Binding{Keys: []string{"shift+tab", "up"}},
+	NextPage: key.Binding{Keys: []string{"pgdown"}},
+	PrevPage: key.Binding{Keys: []string{"pgup"}},
+	Choose:   key.Binding{Keys: []string{"enter"}},
+	Abort:    key.Binding{Keys: []string{"esc"}},
+}
+
+// Styles of the form.
+type Styles struct {
+	Title         styles.Style
+	Label         styles.Style
+	FocusedLabel  styles.Style
+	Error         styles.Style
+	Option        styles.Style
+	FocusedOption styles.Style
+	Button        styles.Style
+	FocusedButton styles.Style
+}
+
+// DefaultStyles returns default form styles.
+func DefaultStyles() Styles {
+	focused := styles.FgColor("205")
+	return Styles{
+		Title:         styles.Style{}.Bold(true),
+		Label:         styles.Style{}.Foreground(styles.FgColor("245")),
+		FocusedLabel:  styles.Style{}.Foreground(focused),
+		Error:         styles.Style{}.Foreground(styles.FgColor("9")),
+		Option:        styles.Style{},
+		FocusedOption: styles.Style{}.Foreground(focused),
+		Button:        styles.Style{}.Foreground(styles.FgColor("240")),
+		FocusedButton: styles.Style{}.Foreground(focused).Bold(true),
+	}
+}
+
+// Model is the Tea model of form filling value of type T.
+type Model[T any] struct {
+	KeyMap KeyMap
+	Styles Styles
+
+	// Validate checks the whole value before it is submitted, e.g. that
+	// fields agree with each other. Returned FieldError is shown under its
+	// field, other errors are shown under the form.
+	Validate func(T) error
+
+	value  *T
+	groups []Group
+	// page and field are indexes of focused group and field in it
+	page, field int
+	// errs are errors shown under fields, fields are validated once they lose
+	// focus or when form is submitted
+	errs  map[Field]error
+	err   error
+	state State
+}
+
+// New creates form filling value. Fields must be bound to value, so that it
+// is filled as they are edited.
+func New[T any](value *T, groups ...Group) Model[T] {
+	m := Model[T]{
+		KeyMap: DefaultKeyMap,
+		Styles: DefaultStyles(),
+		value:  value,
+		groups: groups,
+		errs:   map[Field]error{},
+	}
+	m.Focus()
+	return m
+}
+
+// Focus focuses current field.
+func (m *Model[T]) Focus() []tea.Cmd {
+	if field, ok := m.focused(); ok {
+		return field.Focus()
+	}
+	return nil
+}
+
+// State returns whether form is being edited, submitted or aborted.
+func (m *Model[T]) State() State {
+	return m.state
+}
+
+// Value returns value filled by form.
+func (m *Model[T]) Value() T {
+	return *m.value
+}
+
+// Page returns index of current page.
+func (m *Model[T]) Page() int {
+	return m.page
+}
+
+// Err returns error of field, nil if field is valid or not validated yet.
+func (m *Model[T]) Err(field Field) error {
+	return m.errs[field]
+}
+
+// focused returns focused field.
+func (m *Model[T]) focused() (Field, bool) {
+	if m.page >= len(m.groups) || m.field >= len(m.groups[m.page].Fields) {
+		return nil, false
+	}
+	return m.groups[m.page].Fields[m.field], true
+}
+
+// validate validates field, remembering its error to show.
+func (m *Model[T]) validate(field Field) bool {
+	err := field.Validate()
+	if err == nil {
+		delete(m.errs, field)
+		return true
+	}
+
+	m.errs[field] = err
+	return false
+}
+
+// validatePage validates all fields on page, focuses the first invalid one.
+func (m *Model[T]) validatePage(page int, f func(...tea.Cmd)) bool {
+	for i, field := range m.groups[page].Fields {
+		if !m.validate(field) {
+			m.focus(page, i, f)
+			return false
+		}
+	}
+	return true
+}
+
+// focus moves focus to field on page.
+func (m *Model[T]) focus(page, field int, f func(...tea.Cmd)) {
+	if current, ok := m.focused(); ok {
+		current.Blur()
+	}
+	m.page, m.field = page, field
+	if current, ok := m.focused(); ok {
+		f(current.Focus()...)
+	}
+}
+
+// next moves focus to the next field. Leaving the last field of page moves
+// to the next page if the page is valid, leaving the last page submits.
+func (m *Model[T]) next(f func(...tea.Cmd)) {
+	if field, ok := m.focused(); ok {
+		m.validate(field)
+	}
+
+	if m.field+1 < len(m.groups[m.page].Fields) {
+		m.focus(m.page, m.field+1, f)
+		return
+	}
+
+	m.nextPage(f)
+}
+
+// prev moves focus to the previous field, possibly on the previous page.
+func (m *Model[T]) prev(f func(...tea.Cmd)) {
+	switch {
+	case m.field > 0:
+		m.focus(m.page, m.field-1, f)
+	case m.page > 0:
+		m.focus(m.page-1, len(m.groups[m.page-1].Fields)-1, f)
+	}
+}
+
+// nextPage moves to the next page if current one is valid, submits on the
+// last page.
+func (m *Model[T]) nextPage(f func(...tea.Cmd)) {
+	if !m.validatePage(m.page, f) {
+		return
+	}
+
+	if m.page+1 < len(m.groups) {
+		m.focus(m.page+1, 0, f)
+		return
+	}
+
+	m.submit(f)
+}
+
+// submit validates all fields and the whole value, then submits it.
+func (m *Model[T]) submit(f func(...tea.Cmd)) {
+	for page := range m.groups {
+		if !m.validatePage(page, f) {
+			return
+		}
+	}
+
+	m.err = nil
+	if m.Validate != nil {
+		if err := m.Validate(*m.value); err != nil {
+			var fieldErr *FieldError
+			if !errors.As(err, &fieldErr) {
+				m.err = err
+				return
+			}
+
+			m.errs[fieldErr.Field] = fieldErr.Err
+			for page, group := range m.groups {
+				for i, field := range group.Fields {
+					if field == fieldErr.Field {
+						m.focus(page, i, f)
+					}
+				}
+			}
+			return
+		}
+	}
+
+	m.state = StateSubmitted
+	value := *m.value
+	f(func() tea.Msg { return MsgSubmitted[T]{Value: value} })
+}
+
+// abort aborts the form.
+func (m *Model[T]) abort(f func(...tea.Cmd)) {
+	m.state = StateAborted
+	f(func() tea.Msg { return MsgAborted{} })
+}
+
+// Update is the Tea update loop.
+func (m *Model[T]) Update(msg tea.Msg, f func(...tea.Cmd)) {
+	field, ok := m.focused()
+	if m.state != StateEditing || !ok {
+		return
+	}
+
+	msgKey, ok := msg.(tea.MsgKey)
+	if !ok {
+		field.Update(msg, f)
+		return
+	}
+
+	button, isButton := field.(*ButtonField)
+	switch {
+	case field.Captures(msgKey):
+		field.Update(msg, f)
+	case isButton && key.Matches(msgKey, m.KeyMap.Choose):
+		if button.submit {
+			m.submit(f)
+		} else {
+			m.abort(f)
+		}
+	case key.Matches(msgKey, m.KeyMap.Abort):
+		m.abort(f)
+	case key.Matches(msgKey, m.KeyMap.Next, m.KeyMap.Choose):
+		m.next(f)
+	case key.Matches(msgKey, m.KeyMap.Prev):
+		m.prev(f)
+	case key.Matches(msgKey, m.KeyMap.NextPage):
+		m.nextPage(f)
+	case key.Matches(msgKey, m.KeyMap.PrevPage):
+		if m.page > 0 {
+			m.focus(m.page-1, 0, f)
+		}
+	default:
+		field.Update(msg, f)
+	}
+
+	// error of field being fixed is updated as user types
+	if _, ok := m.errs[field]; ok {
+		m.validate(field)
+	}
+}
+
+// View renders the current page of the form.
+func (m *Model[T]) View(vb tea.Viewbox) {
+	if m.page >= len(m.groups) {
+		return
+	}
+
+	group := m.groups[m.page]
+	row := 0
+	if group.Title != "" || len(m.groups) > 1 {
+		title := group.Title
+		if len(m.groups) > 1 {
+			title += fmt.Sprintf(" (%d/%d)", m.page+1, len(m.groups))
+		}
+		vb.Row(row).Styled(m.Styles.Title).WriteLine(title)
+		row += 2
+	}
+
+	for i, field := range group.Fields {
+		if row >= vb.Height {
+			return
+		}
+
+		focused := i == m.field
+		if label := field.Label(); label != "" {
+			style := m.Styles.Label
+			if focused {
+				style = m.Styles.FocusedLabel
+			}
+			vb.Row(row).Styled(style).WriteLine(label)
+			row++
+		}
+
+		field.View(vb.PaddingTop(row).MaxHeight(field.Height()), m.Styles, focused)
+		row += field.Height()
+
+		if err := m.errs[field]; err != nil {
+			vb.Row(row).Styled(m.Styles.Error).WriteLine("✗ " + err.Error())
+			row++
+		}
+		row++
+	}
+
+	if m.err != nil && row < vb.Height {
+		vb.Row(row).Styled(m.Styles.Error).WriteLine("✗ " + m.err.Error())
+	}
+}
@@ -0,0 +1,98 @@
+package form
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/rprtr258/assert"
+
+	"github.com/rprtr258/tea"
+	"github.com/rprtr258/tea/components/headless/selector"
+	"github.com/rprtr258/tea/components/headless/selector_multiple"
+	"github.com/rprtr258/tea/components/textinput"
+)
+
+type signup struct {
+	Name     string
+	Age      int
+	Plan     string
+	Tags     []string
+	Terms    bool
+	Password string
+	Repeat   string
+}
+
+func TestForm(t *testing.T) {
+	var cmds []tea.Cmd
+	f := func(c ...tea.Cmd) { cmds = append(cmds, c...) }
+	keys := func(m *Model[signup], keys ...tea.MsgKey) {
+		for _, k := range keys {
+			m.Update(k, f)
+		}
+	}
+	runes := func(s string) tea.MsgKey {
+		return tea.MsgKey{Type: tea.KeyRunes, Runes: []rune(s)}
+	}
+	tab := tea.MsgKey{Type: tea.KeyTab}
+	space := tea.MsgKey{Type: tea.KeySpace, Runes: []rune{' '}}
+
+	var s signup
+	terms := Confirm("I accept terms", &s.Terms)
+	terms.Required = true
+	repeat := Input("Repeat password", &s.Repeat)
+	m := New(&s,
+		Group{Title: "Profile", Fields: []Field{
+			Input("Name", &s.Name),
+			Parsed("Age", &s.Age, textinput.Int().Where(textinput.InRange(18, 99)), ""),
+			Select("Plan", &s.Plan,
+				selector.Item[string]{Label: "Free", Value: "free"},
+				selector.Item[string]{Label: "Pro", Value: "pro"},
+			),
+			MultiSelect("Tags", &s.Tags,
+				selector_multiple.Item[string]{Label: "go", Value: "go"},
+				selector_multiple.Item[string]{Label: "rust", Value: "rust"},
+			),
+		}},
+		Group{Title: "Account", Fields: []Field{
+			Input("Password", &s.Password),
+			repeat,
+			terms,
+			Submit("Sign up"),
+		}},
+	)
+	m.Validate = func(s signup) error {
+		if s.Password != s.Repeat {
+			return &FieldError{Field: repeat, Err: errors.New("passwords differ")}
+		}
+		return nil
+	}
+
+	keys(&m, runes("Ann"), tab, runes("12"), tab)
+	assert.Equal(t, "Ann", s.Name)
+	assert.Equal(t, "must be between 18 and 99", m.Err(m.groups[0].Fields[1]).Error())
+
+	// page can not be left while it has errors
+	keys(&m, tab, tab, tea.MsgKey{Type: tea.KeyPgDown})
+	assert.Equal(t, 0, m.Page())
+	assert.Equal(t, 1, m.field)
+
+	keys(&m, tea.MsgKey{Type: tea.KeyBackspace}, runes("8"))
+	assert.Equal(t, 18, s.Age)
+	assert.NoError(t, m.Err(m.groups[0].Fields[1]))
+
+	// dropdown and checkboxes
+	keys(&m, tab, tea.MsgKey{Type: tea.KeyRight}, tab, tea.MsgKey{Type: tea.KeyDown}, space)
+	assert.Equal(t, "free", s.Plan)
+	assert.Equal(t, []string{"rust"}, s.Tags)
+
+	keys(&m, tab, runes("secret"), tab, runes("secre"), tab, tab, tab)
+	assert.Equal(t, 1, m.Page())
+	assert.Equal(t, ErrRequired, m.Err(terms))
+	keys(&m, space, tab, tea.MsgKey{Type: tea.KeyEnter})
+	assert.Equal(t, "passwords differ", m.Err(repeat).Error())
+	assert.Equal(t, StateEditing, m.State())
+
+	keys(&m, runes("t"), tab, tab, tea.MsgKey{Type: tea.KeyEnter})
+	assert.Equal(t, StateSubmitted, m.State())
+	assert.Equal(t, MsgSubmitted[signup]{Value: s}, cmds[len(cmds)-1]().(MsgSubmitted[signup]))
+}
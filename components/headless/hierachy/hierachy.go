@@ -1,54 +1,68 @@
 package hierachy
 
+import "slices"
+
 type Node[T any] struct {
 	Value    T
 	Children []Node[T]
+	// Lazy node has children which are not loaded yet. It is shown as
+	// collapsed node with children until SetChildren is called.
+	Lazy bool
 }
 
 type myNodeData[T any] struct {
 	value           T
+	id              int
 	depth           int
 	parentIdx       int // -1 for root
 	lastChildrenIdx int
 	collapsed       bool
+	lazy            bool
 }
 
-func remapTree[T any](node Node[T], parentIdx int, dest *[]myNodeData[T]) int {
+func remapTree[T any](node Node[T], parentIdx int, dest *[]myNodeData[T], lastID *int) int {
 	depth := 0
 	if parentIdx != -1 {
 		depth = (*dest)[parentIdx].depth + 1
 	}
 
+	*lastID++
 	*dest = append(*dest, myNodeData[T]{
 		value:           node.Value,
+		id:              *lastID,
 		depth:           depth,
 		parentIdx:       parentIdx,
 		lastChildrenIdx: -1,    // NOTE: filled later
 		collapsed:       false, // NOTE: filled later
+		lazy:            node.Lazy,
 	})
 	ownIdx := len(*dest) - 1
 	count := 0 // TODO: count actually is just len(*dest)-ownIdx after the loop, but I couldn't make it work vahui
 	for _, child := range node.Children {
-		count += remapTree(child, ownIdx, dest)
+		count += remapTree(child, ownIdx, dest, lastID)
 	}
 	(*dest)[ownIdx].lastChildrenIdx = ownIdx + count
 	(*dest)[ownIdx].collapsed = count == 0
 	return 1 + count
 }
 
-// NOTE: readonly
+// NOTE: readonly, except for loading children of lazy nodes
 type Hierachy[T any] struct {
 	nodes    []myNodeData[T]
 	selected int
+	// lastID is id of the last added node, ids are stable while nodes are
+	// added, unlike indexes
+	lastID int
+	filter func(T) bool
+	// visible is indexes of shown nodes in order
+	visible []int
 }
 
 func New[T any](tree Node[T]) *Hierachy[T] {
-	var nodes []myNodeData[T]
-	remapTree(tree, -1, &nodes)
-	return &Hierachy[T]{
-		nodes:    nodes,
-		selected: 0,
-	}
+	h := &Hierachy[T]{selected: 0}
+	remapTree(tree, -1, &h.nodes, &h.lastID)
+	h.update()
+	return h
 }
 
 func (h *Hierachy[T]) sel() *myNodeData[T] {
@@ -59,28 +73,88 @@ func (h *Hierachy[T]) Selected() T {
 	return h.sel().value
 }
 
+// SelectedID returns id of selected node.
+func (h *Hierachy[T]) SelectedID() int {
+	return h.sel().id
+}
+
+// SelectedPos returns position of selected node among shown ones, -1 if
+// no node is shown.
+func (h *Hierachy[T]) SelectedPos() int {
+	for pos, i := range h.visible {
+		if i == h.selected {
+			return pos
+		}
+	}
+	return -1
+}
+
+// Len returns number of shown nodes.
+func (h *Hierachy[T]) Len() int {
+	return len(h.visible)
+}
+
+// SelectPos selects shown node at given position, clamped to shown ones.
+func (h *Hierachy[T]) SelectPos(pos int) {
+	if len(h.visible) > 0 {
+		h.selected = h.visible[min(max(pos, 0), len(h.visible)-1)]
+	}
+}
+
+// Select selects shown node with given id, returns false if there is none.
+func (h *Hierachy[T]) Select(id int) bool {
+	for _, i := range h.visible {
+		if h.nodes[i].id == id {
+			h.selected = i
+			return true
+		}
+	}
+	return false
+}
+
+// IsCollapsed returns whether selected node children are hidden. While
+// filtering, node is collapsed if none of its children is shown.
 func (h *Hierachy[T]) IsCollapsed() bool {
+	if h.filter != nil {
+		pos := h.SelectedPos()
+		return pos == -1 || pos+1 == len(h.visible) || h.nodes[h.visible[pos+1]].parentIdx != h.selected
+	}
 	return h.sel().collapsed
 }
 
+// IsFiltered returns whether filter is set.
+func (h *Hierachy[T]) IsFiltered() bool {
+	return h.filter != nil
+}
+
+// IsLazy returns whether selected node children are not loaded yet.
+func (h *Hierachy[T]) IsLazy() bool {
+	return h.sel().lazy
+}
+
+// ToggleCollapsed collapses or expands selected node. It does nothing while
+// filtering, as then shown children depend on filter only.
 func (h *Hierachy[T]) ToggleCollapsed() {
-	if h.sel().lastChildrenIdx == h.selected {
+	if h.filter != nil || h.sel().lastChildrenIdx == h.selected && !h.sel().lazy {
 		// leaf nodes cannot be collapsed
 		return
 	}
 
 	h.sel().collapsed = !h.sel().collapsed
+	h.update()
 }
 
+// GoDown selects the first shown child of selected node.
 func (h *Hierachy[T]) GoDown() {
-	if h.sel().lastChildrenIdx > h.selected {
-		h.selected++
+	if pos := h.SelectedPos(); pos != -1 && pos+1 < len(h.visible) && h.nodes[h.visible[pos+1]].parentIdx == h.selected {
+		h.selected = h.visible[pos+1]
 	}
 }
 
+// GoUp selects parent of selected node, which is shown as well.
 func (h *Hierachy[T]) GoUp() {
 	parentIdx := h.sel().parentIdx
-	if parentIdx == -1 {
+	if parentIdx == -1 || !slices.Contains(h.visible, parentIdx) {
 		return
 	}
 
@@ -99,58 +173,162 @@ func (h *Hierachy[T]) GoNext() {
 	}
 }
 
+// GoPrevOrUp selects previous shown node, which is the previous sibling
+// last shown descendant or the parent.
 func (h *Hierachy[T]) GoPrevOrUp() {
-	if h.selected == 0 {
-		return
+	if pos := h.SelectedPos(); pos > 0 {
+		h.selected = h.visible[pos-1]
+	}
+}
+
+// GoNextOrUp selects next shown node, skipping children of collapsed one.
+func (h *Hierachy[T]) GoNextOrUp() {
+	if pos := h.SelectedPos(); pos != -1 && pos < len(h.visible)-1 {
+		h.selected = h.visible[pos+1]
 	}
+}
 
-	h.selected--
+// SetChildren replaces children of node with given id, e.g. when children
+// of lazy node are loaded. Node is expanded, if it has any children.
+func (h *Hierachy[T]) SetChildren(id int, children []Node[T]) {
+	idx := -1
+	for i, node := range h.nodes {
+		if node.id == id {
+			idx = i
+			break
+		}
+	}
+	if idx == -1 {
+		return
+	}
 
-	// if at least one parent is collapsed, go to highest one
-	highestCollapsedParentIdx := -1
-	for i := h.sel().parentIdx; i != -1; i = h.nodes[i].parentIdx {
-		if h.nodes[i].collapsed {
-			highestCollapsedParentIdx = i
+	last := h.nodes[idx].lastChildrenIdx
+	nodes := append([]myNodeData[T]{}, h.nodes[:idx+1]...)
+	count := 0
+	for _, child := range children {
+		count += remapTree(child, idx, &nodes, &h.lastID)
+	}
+	delta := len(nodes) - len(h.nodes[:last+1])
+	for _, node := range h.nodes[last+1:] {
+		if node.parentIdx > last {
+			node.parentIdx += delta
 		}
+		node.lastChildrenIdx += delta
+		nodes = append(nodes, node)
 	}
-	if highestCollapsedParentIdx != -1 {
-		h.selected = highestCollapsedParentIdx
+	for i := h.nodes[idx].parentIdx; i != -1; i = nodes[i].parentIdx {
+		nodes[i].lastChildrenIdx += delta
 	}
+	nodes[idx].lastChildrenIdx = idx + count
+	nodes[idx].collapsed = count == 0
+	nodes[idx].lazy = false
+
+	switch {
+	case h.selected > last:
+		h.selected += delta
+	case h.selected > idx:
+		h.selected = idx
+	}
+	h.nodes = nodes
+	h.update()
 }
 
-func (h *Hierachy[T]) GoNextOrUp() {
-	if h.sel().collapsed {
-		if newSelected := h.sel().lastChildrenIdx + 1; newSelected < len(h.nodes) {
-			h.selected = newSelected
+// SetFilter shows only nodes for which filter returns true and their
+// ancestors, regardless of collapsed ones. Nil filter shows all nodes.
+func (h *Hierachy[T]) SetFilter(filter func(T) bool) {
+	h.filter = filter
+	h.update()
+}
+
+// matches returns whether node at index matches filter.
+func (h *Hierachy[T]) matches(i int) bool {
+	return h.filter == nil || h.filter(h.nodes[i].value)
+}
+
+// update recomputes shown nodes. If selected node is hidden, the next shown
+// one is selected.
+func (h *Hierachy[T]) update() {
+	h.visible = h.visible[:0]
+	if h.filter == nil {
+		for i := 0; i < len(h.nodes); i++ {
+			h.visible = append(h.visible, i)
+			if h.nodes[i].collapsed {
+				i = h.nodes[i].lastChildrenIdx
+			}
+		}
+	} else {
+		keep := make([]bool, len(h.nodes))
+		for i := len(h.nodes) - 1; i >= 0; i-- {
+			keep[i] = keep[i] || h.matches(i)
+			if parent := h.nodes[i].parentIdx; keep[i] && parent != -1 {
+				keep[parent] = true
+			}
+		}
+		for i, ok := range keep {
+			if ok {
+				h.visible = append(h.visible, i)
+			}
 		}
-	} else if h.selected < len(h.nodes)-1 {
-		h.selected++
 	}
+
+	if h.SelectedPos() != -1 || len(h.visible) == 0 {
+		return
+	}
+	next := h.visible[len(h.visible)-1]
+	for _, i := range slices.Backward(h.visible) {
+		if i < h.selected {
+			break
+		}
+		next = i
+	}
+	h.selected = next
 }
 
 type IterItem[T any] struct {
 	Value       T
+	ID          int
 	Depth       int
 	IsSelected  bool
 	HasChildren bool
 	IsCollapsed bool
+	// IsLast is whether node is the last shown child of its parent
+	IsLast bool
+	// IsLazy is whether node children are not loaded yet
+	IsLazy bool
+	// IsMatch is whether node matches filter, ancestors of matching nodes
+	// are shown too
+	IsMatch bool
 }
 
 func (h *Hierachy[T]) Iter(yield func(IterItem[T]) bool) {
-	for i := 0; i < len(h.nodes); i++ {
+	// positions of shown nodes after which their parent has shown children
+	notLast := make([]bool, len(h.visible))
+	seen := map[int]bool{}
+	for k := len(h.visible) - 1; k >= 0; k-- {
+		parent := h.nodes[h.visible[k]].parentIdx
+		notLast[k] = seen[parent]
+		seen[parent] = true
+	}
+
+	for k, i := range h.visible {
 		node := h.nodes[i]
+		collapsed := node.collapsed
+		if h.filter != nil {
+			// while filtering, node is expanded if any child is shown
+			collapsed = k+1 == len(h.visible) || h.nodes[h.visible[k+1]].parentIdx != i
+		}
 		if !yield(IterItem[T]{
 			Value:       node.value,
+			ID:          node.id,
 			Depth:       node.depth,
 			IsSelected:  i == h.selected,
-			HasChildren: node.lastChildrenIdx != i,
-			IsCollapsed: node.collapsed,
+			HasChildren: node.lastChildrenIdx != i || node.lazy,
+			IsCollapsed: collapsed,
+			IsLast:      !notLast[k],
+			IsLazy:      node.lazy,
+			IsMatch:     h.filter != nil && h.matches(i),
 		}) {
 			return
 		}
-
-		if node.collapsed {
-			i = node.lastChildrenIdx
-		}
 	}
 }
@@ -20,6 +20,18 @@ func (h *History[T]) Push(item T) {
 	h.items = append(h.items[:h.current+1], item)
 }
 
+// Advance adds item after current one, dropping items after it, and makes
+// it current, unlike Push which keeps current item.
+func (h *History[T]) Advance(item T) {
+	h.Push(item)
+	h.current++
+}
+
+// Set replaces current item, keeping items after it.
+func (h *History[T]) Set(item T) {
+	h.items[h.current] = item
+}
+
 func (h *History[T]) BackCan() bool { return h.current > 0 }
 func (h *History[T]) Back() {
 	if h.BackCan() {
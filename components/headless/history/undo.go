@@ -0,0 +1,83 @@
+package history
+
+// EditKind is a kind of edit made in editor. Consecutive edits of the same
+// kind are merged into single undo step, except EditOther.
+type EditKind int
+
+const (
+	// EditOther is an edit which always makes its own undo step, e.g. paste
+	// or newline insertion.
+	EditOther EditKind = iota
+	// EditInsert is typing of characters.
+	EditInsert
+	// EditDelete is deletion of characters one by one.
+	EditDelete
+)
+
+// Undo is an undo/redo stack of editor states. T is a snapshot of editor
+// state, including cursor position, so it is restored on undo as well.
+type Undo[T any] struct {
+	history *History[T]
+	// last is kind of last recorded edit, if next edit can be merged into it
+	last EditKind
+}
+
+// NewUndo creates undo stack with initial editor state.
+func NewUndo[T any](init T) *Undo[T] {
+	return &Undo[T]{
+		history: New(init),
+		last:    EditOther,
+	}
+}
+
+// Reset forgets all steps, making init the only state.
+func (u *Undo[T]) Reset(init T) {
+	u.history = New(init)
+	u.last = EditOther
+}
+
+// Edit records an edit which turned editor state from before into after.
+// Edit is merged into previous one if they are of the same kind and nothing
+// broke the sequence, e.g. typing of a word becomes single step.
+func (u *Undo[T]) Edit(kind EditKind, before, after T) {
+	if kind != EditOther && kind == u.last {
+		u.history.Set(after)
+		return
+	}
+
+	// before differs from current state only by cursor position, which is
+	// where undo should return cursor to
+	u.history.Set(before)
+	u.history.Advance(after)
+	u.last = kind
+}
+
+// Break ends sequence of merged edits, so next edit makes new step. It should
+// be called e.g. when cursor moves.
+func (u *Undo[T]) Break() {
+	u.last = EditOther
+}
+
+// Undo steps back and returns state to restore, if there is one.
+func (u *Undo[T]) Undo() (T, bool) {
+	u.last = EditOther
+	if !u.history.BackCan() {
+		var zero T
+		return zero, false
+	}
+
+	u.history.Back()
+	return u.history.Current(), true
+}
+
+// Redo steps forward and returns state to restore, if there is one.
+func (u *Undo[T]) Redo() (T, bool) {
+	u.last = EditOther
+	if !u.history.ForwardCan() {
+		var zero T
+		return zero, false
+	}
+
+	u.history.Forward()
+	return u.history.Current(), true
+}
@@ -0,0 +1,61 @@
+package history
+
+import (
+	"testing"
+
+	"github.com/rprtr258/assert"
+)
+
+type state struct {
+	value string
+	pos   int
+}
+
+func TestUndo(t *testing.T) {
+	u := NewUndo(state{"", 0})
+	// type "ab", move cursor, type "c", delete it, paste "xyz"
+	u.Edit(EditInsert, state{"", 0}, state{"a", 1})
+	u.Edit(EditInsert, state{"a", 1}, state{"ab", 2})
+	u.Break()
+	u.Edit(EditInsert, state{"ab", 0}, state{"cab", 1})
+	u.Edit(EditDelete, state{"cab", 1}, state{"ab", 0})
+	u.Edit(EditOther, state{"ab", 0}, state{"xyzab", 3})
+
+	for _, want := range []state{
+		{"ab", 0},
+		{"cab", 1},
+		{"ab", 0}, // cursor is restored to where it was before typing
+		{"", 0},
+	} {
+		got, ok := u.Undo()
+		assert.True(t, ok)
+		assert.Equal(t, want, got)
+	}
+	_, ok := u.Undo()
+	assert.False(t, ok)
+
+	got, ok := u.Redo()
+	assert.True(t, ok)
+	assert.Equal(t, state{"ab", 0}, got)
+
+	// new edit drops undone steps
+	u.Edit(EditInsert, state{"ab", 2}, state{"abd", 3})
+	_, ok = u.Redo()
+	assert.False(t, ok)
+	got, _ = u.Undo()
+	assert.Equal(t, state{"ab", 2}, got)
+}
+
+func TestHistory(t *testing.T) {
+	h := New(0)
+	h.Push(1)
+	// pushed item is after current one, which is kept
+	assert.Equal(t, 0, h.Current())
+	assert.True(t, h.ForwardCan())
+
+	h.Advance(2)
+	// advanced item replaces ones after current and becomes current
+	assert.Equal(t, []int{0, 2}, h.Items())
+	assert.Equal(t, 2, h.Current())
+	assert.False(t, h.ForwardCan())
+}
@@ -18,9 +18,8 @@ type Selector[T any] struct {
 	// satisfy 0 <= indexSelected < len(items)
 	// TODO: allow unselected
 	indexSelected int
-	// indexHighlighted is the index of the currently highlighted item plus one,
-	// such that zero value is no highlight
-	// TODO: allow unhighlighted
+	// indexHighlighted is the index of the currently highlighted item, -1 if
+	// there is no highlight
 	indexHighlighted int
 	isOpen           bool
 }
@@ -74,11 +73,21 @@ func (s *Selector[T]) ResetHighlight() {
 }
 
 func (s *Selector[T]) Highlighted() (Item[T], bool) {
-	if s.indexHighlighted == 0 {
+	if s.indexHighlighted == -1 {
 		return Item[T]{}, false
 	}
 
-	return s.items[s.indexHighlighted-1], true
+	return s.items[s.indexHighlighted], true
+}
+
+// HighlightedIndex returns index of highlighted item, -1 if there is none.
+func (s *Selector[T]) HighlightedIndex() int {
+	return s.indexHighlighted
+}
+
+// SelectedIndex returns index of selected item, -1 if there is none.
+func (s *Selector[T]) SelectedIndex() int {
+	return s.indexSelected
 }
 
 // Select highlighted item. No change if no highlight.
@@ -126,6 +126,17 @@ func (s *Selector[T]) AddHighlighted() {
 	s.selectedIndexes = append(s.selectedIndexes, newIndex)
 }
 
+// HighlightedIndex returns index of highlighted item, -1 if there is none.
+func (s *Selector[T]) HighlightedIndex() int {
+	return s.highlightedIndex - 1
+}
+
+// IsSelected checks whether item with given index is selected.
+func (s *Selector[T]) IsSelected(i int) bool {
+	_, ok := s.selected[i]
+	return ok
+}
+
 func (s *Selector[T]) SelectedCount() int {
 	return len(s.selectedIndexes)
 }
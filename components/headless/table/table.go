@@ -8,11 +8,23 @@ type Column[T any] struct {
 	Cmp   func(T, T) int
 }
 
+// SortKey is a column rows are sorted by.
+type SortKey struct {
+	Column int
+	Desc   bool
+}
+
 type Table[T any] struct {
-	cols   []Column[T]
+	cols []Column[T]
+	rows []T
+	// order is indexes of shown rows, sorted and filtered
 	order  []int
-	rows   []T
+	sort   []SortKey
+	filter func(T) bool
 	cursor int
+	// marked is indexes of rows marked for bulk actions, they stay marked
+	// while sorted or hidden by filter
+	marked map[int]bool
 }
 
 func New[T any](cols []Column[T], rows []T) *Table[T] {
@@ -26,12 +38,13 @@ func New[T any](cols []Column[T], rows []T) *Table[T] {
 		rows:   rows,
 		order:  order,
 		cursor: -1,
+		marked: map[int]bool{},
 	}
 }
 
-func (t *Table[T]) RowsCount() int { return len(t.rows) }
+func (t *Table[T]) RowsCount() int { return len(t.order) }
 func (t *Table[T]) Rows() []T {
-	rows := make([]T, len(t.rows))
+	rows := make([]T, len(t.order))
 	for i := range rows {
 		rows[i] = t.rows[t.order[i]]
 	}
@@ -39,22 +52,127 @@ func (t *Table[T]) Rows() []T {
 }
 func (t *Table[T]) Columns() []Column[T] { return t.cols }
 
+// RowIndex returns index of shown row y in rows table was created with.
+func (t *Table[T]) RowIndex(y int) int { return t.order[y] }
+
+// SetRow replaces shown row y. Rows are sorted and filtered again, cursor
+// stays on the same row if it is still shown.
+func (t *Table[T]) SetRow(y int, row T) {
+	t.rows[t.order[y]] = row
+	t.update()
+}
+
 func (t *Table[T]) Cursor() int     { return t.cursor }
-func (t *Table[T]) Selected() T     { return t.rows[t.cursor] }
-func (t *Table[T]) MoveTo(y int)    { t.cursor = max(0, min(len(t.rows)-1, y)) }
+func (t *Table[T]) Selected() T     { return t.rows[t.order[t.cursor]] }
+func (t *Table[T]) MoveTo(y int)    { t.cursor = min(max(0, y), len(t.order)-1) }
 func (t *Table[T]) MoveTop()        { t.MoveTo(0) }
-func (t *Table[T]) MoveBottom()     { t.MoveTo(len(t.rows) - 1) }
+func (t *Table[T]) MoveBottom()     { t.MoveTo(len(t.order) - 1) }
 func (t *Table[T]) MoveUp(dy int)   { t.MoveTo(t.cursor - dy) }
 func (t *Table[T]) MoveDown(dy int) { t.MoveTo(t.cursor + dy) }
 
-// TODO: move current?
-func (t *Table[T]) SortColumn(i int) {
-	slices.SortFunc(t.order, func(i, j int) int {
-		return t.cols[i].Cmp(t.rows[t.order[i]], t.rows[t.order[j]])
-	})
+// SortKeys returns columns rows are sorted by, most significant first.
+func (t *Table[T]) SortKeys() []SortKey { return t.sort }
+
+// SortBy sorts rows by given columns, most significant first. Rows equal by
+// all of them keep their original order. Cursor stays on the same row.
+func (t *Table[T]) SortBy(keys ...SortKey) {
+	t.sort = keys
+	t.update()
+}
+
+func (t *Table[T]) SortColumn(i int)     { t.SortBy(SortKey{Column: i}) }
+func (t *Table[T]) SortColumnDesc(i int) { t.SortBy(SortKey{Column: i, Desc: true}) }
+
+// SetFilter shows only rows for which filter returns true, nil filter shows
+// all rows. Cursor stays on the same row if it is still shown.
+func (t *Table[T]) SetFilter(filter func(T) bool) {
+	t.filter = filter
+	t.update()
 }
-func (t *Table[T]) SortColumnDesc(i int) {
-	slices.SortFunc(t.order, func(i, j int) int {
-		return -t.cols[i].Cmp(t.rows[t.order[i]], t.rows[t.order[j]])
+
+// update recomputes order of shown rows, keeping cursor on the same row.
+func (t *Table[T]) update() {
+	selected := -1
+	if t.cursor >= 0 && t.cursor < len(t.order) {
+		selected = t.order[t.cursor]
+	}
+
+	t.order = t.order[:0]
+	for i, row := range t.rows {
+		if t.filter == nil || t.filter(row) {
+			t.order = append(t.order, i)
+		}
+	}
+
+	slices.SortStableFunc(t.order, func(i, j int) int {
+		for _, key := range t.sort {
+			c := t.cols[key.Column].Cmp(t.rows[i], t.rows[j])
+			if key.Desc {
+				c = -c
+			}
+			if c != 0 {
+				return c
+			}
+		}
+		return i - j
 	})
+
+	if i := slices.Index(t.order, selected); i != -1 {
+		t.cursor = i
+	} else {
+		t.MoveTo(t.cursor)
+	}
+}
+
+// IsMarked returns whether shown row y is marked.
+func (t *Table[T]) IsMarked(y int) bool { return t.marked[t.order[y]] }
+
+// Mark marks or unmarks shown row y.
+func (t *Table[T]) Mark(y int, marked bool) {
+	if marked {
+		t.marked[t.order[y]] = true
+	} else {
+		delete(t.marked, t.order[y])
+	}
+}
+
+// ToggleMark toggles mark of row under cursor.
+func (t *Table[T]) ToggleMark() {
+	if t.cursor >= 0 && t.cursor < len(t.order) {
+		t.Mark(t.cursor, !t.IsMarked(t.cursor))
+	}
+}
+
+// MarkRange marks or unmarks shown rows from y1 to y2 inclusive, in any
+// order.
+func (t *Table[T]) MarkRange(y1, y2 int, marked bool) {
+	y1, y2 = max(min(y1, y2), 0), min(max(y1, y2), len(t.order)-1)
+	for y := y1; y <= y2; y++ {
+		t.Mark(y, marked)
+	}
+}
+
+// MarkAll marks all shown rows, e.g. all rows matching filter.
+func (t *Table[T]) MarkAll() { t.MarkRange(0, len(t.order)-1, true) }
+
+// InvertMarks inverts marks of shown rows.
+func (t *Table[T]) InvertMarks() {
+	for y := range t.order {
+		t.Mark(y, !t.IsMarked(y))
+	}
+}
+
+// ClearMarks unmarks all rows, including hidden ones.
+func (t *Table[T]) ClearMarks() { clear(t.marked) }
+
+// Marked returns marked rows in display order. Rows hidden by filter are
+// not returned.
+func (t *Table[T]) Marked() []T {
+	var rows []T
+	for _, i := range t.order {
+		if t.marked[i] {
+			rows = append(rows, t.rows[i])
+		}
+	}
+	return rows
 }
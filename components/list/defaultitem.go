@@ -29,6 +29,9 @@ type DefaultItemStyles struct {
 
 	// Characters matching the current filter, if any.
 	FilterMatch styles.Style
+
+	// Marker of items marked for bulk actions.
+	Marked styles.Style
 }
 
 // NewDefaultItemStyles returns style definitions for a default item. See
@@ -41,6 +44,7 @@ var NewDefaultItemStyles = func() DefaultItemStyles {
 			Foreground(styles.FgAdaptiveColor("#EE6FF8", "#EE6FF8")),
 		DimmedTitle: styles.Style{}.Foreground(styles.FgAdaptiveColor("#A49FA5", "#777777")),
 		FilterMatch: styles.Style{}.Underline(),
+		Marked:      styles.Style{}.Foreground(styles.FgColor("214")),
 	}
 	s.NormalDesc = s.NormalTitle.Copy().Foreground(styles.FgAdaptiveColor("#A49FA5", "#777777"))
 	s.SelectedDesc = s.SelectedTitle.Copy().Foreground(styles.FgAdaptiveColor("#F793FF", "#AD58B4"))
@@ -148,6 +152,11 @@ func (d DefaultDelegate[I]) Render(vb tea.Viewbox, m *Model[DefaultItem[I]], ind
 		desc = strings.Join(lines, "\n")
 	}
 
+	// Marker goes between selection border and title
+	if m.IsMarked(index) {
+		vb.Styled(s.Marked).Set(0, 1, bullet)
+	}
+
 	// Conditions
 	isSelected := index == m.Index()
 	emptyFilter := m.FilterState() == Filtering && m.FilterValue() == ""
@@ -15,6 +15,15 @@ type KeyMap struct {
 	Filter      key.Binding
 	ClearFilter key.Binding
 
+	// Keybindings marking items for bulk actions. MarkUp and MarkDown mark
+	// items while moving cursor, MarkAll marks all visible items or unmarks
+	// them if they are all marked already.
+	ToggleMark  key.Binding
+	MarkUp      key.Binding
+	MarkDown    key.Binding
+	MarkAll     key.Binding
+	InvertMarks key.Binding
+
 	// Keybindings used when setting a filter.
 	CancelWhileFiltering key.Binding
 	AcceptWhileFiltering key.Binding
@@ -61,6 +70,26 @@ var DefaultKeyMap = KeyMap{
 		Keys: []string{"/"},
 		Help: key.Help{"/", "filter"},
 	},
+	ToggleMark: key.Binding{
+		Keys: []string{" "},
+		Help: key.Help{"space", "mark"},
+	},
+	MarkUp: key.Binding{
+		Keys: []string{"shift+up", "K"},
+		Help: key.Help{"shift+↑/K", "mark up"},
+	},
+	MarkDown: key.Binding{
+		Keys: []string{"shift+down", "J"},
+		Help: key.Help{"shift+↓/J", "mark down"},
+	},
+	MarkAll: key.Binding{
+		Keys: []string{"ctrl+a"},
+		Help: key.Help{"ctrl+a", "mark all"},
+	},
+	InvertMarks: key.Binding{
+		Keys: []string{"*"},
+		Help: key.Help{"*", "invert marks"},
+	},
 	ClearFilter: key.Binding{
 		Keys: []string{"esc"},
 		Help: key.Help{"esc", "clear filter"},
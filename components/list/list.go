@@ -45,6 +45,7 @@ type ItemDelegate[I any] struct {
 }
 
 type filteredItem[I any] struct {
+	index   int   // index of item in items
 	item    I     // item matched
 	matches []int // rune indices of matched items
 }
@@ -145,6 +146,15 @@ type Model[I any] struct {
 	// this field should be considered ephemeral.
 	filteredItems []filteredItem[I]
 
+	// Data source items are loaded from instead of items, if set.
+	source *source[I]
+	// SourceDebounce is how long filter must stay unchanged before data
+	// source is queried. By default this is 200 milliseconds.
+	SourceDebounce time.Duration
+
+	// Items marked for bulk actions, see itemIndex for keys.
+	marked map[int]bool
+
 	ItemDelegate[I]
 }
 
@@ -182,10 +192,13 @@ func New[I any](items []I, delegate ItemDelegate[I], filter func(I) string) Mode
 		Title:                 "List",
 		FilterInput:           filterInput,
 		StatusMessageLifetime: time.Second,
+		SourceDebounce:        200 * time.Millisecond,
 
-		ItemDelegate: delegate,
-		items:        items,
-		Paginator:    p,
+		ItemDelegate:    delegate,
+		ItemFilterValue: filter,
+		items:           items,
+		marked:          map[int]bool{},
+		Paginator:       p,
 		spinner: spinner.New(
 			spinner.WithSpinner(spinner.Line),
 			spinner.WithStyle(DefaultStyle.Spinner),
@@ -295,9 +308,11 @@ func (m *Model[I]) Items() []I {
 	return m.items
 }
 
-// SetItems sets the items available in the list. This returns a command.
+// SetItems sets the items available in the list, marks are cleared. This
+// returns a command.
 func (m *Model[I]) SetItems(items []I) tea.Cmd {
 	m.items = items
+	m.ClearMarks()
 
 	cmd := func() tea.Msg { return nil }
 	if m.filterState != Unfiltered {
@@ -342,6 +357,7 @@ func (m *Model[I]) SetItem(index int, item I) []tea.Cmd {
 // the item will be appended. This returns a command.
 func (m *Model[I]) InsertItem(index int, item I) []tea.Cmd {
 	m.items = slices.Insert(m.items, index, item)
+	m.shiftMarks(index, 1)
 
 	var cmd []tea.Cmd
 	if m.filterState != Unfiltered {
@@ -358,9 +374,18 @@ func (m *Model[I]) InsertItem(index int, item I) []tea.Cmd {
 // case of a TUI.
 func (m *Model[I]) RemoveItem(index int) {
 	m.items = slices.Replace(m.items, index, index+1)
+	delete(m.marked, index)
+	m.shiftMarks(index+1, -1)
 
 	if m.filterState != Unfiltered {
-		m.filteredItems = slices.Replace(m.filteredItems, index, index+1)
+		m.filteredItems = slices.DeleteFunc(m.filteredItems, func(fi filteredItem[I]) bool {
+			return fi.index == index
+		})
+		for i := range m.filteredItems {
+			if m.filteredItems[i].index > index {
+				m.filteredItems[i].index--
+			}
+		}
 		if len(m.filteredItems) == 0 {
 			m.resetFiltering()
 		}
@@ -374,8 +399,13 @@ func (m *Model[I]) SetDelegate(d ItemDelegate[I]) {
 	m.updatePagination()
 }
 
-// VisibleItems returns the total items available to be shown.
+// VisibleItems returns the total items available to be shown. With data
+// source only loaded items are returned.
 func (m *Model[I]) VisibleItems() []I {
+	if m.source != nil {
+		return m.loadedItems()
+	}
+
 	if m.filterState != Unfiltered {
 		return fun.Map[I](func(v filteredItem[I]) I {
 			return v.item
@@ -385,17 +415,10 @@ func (m *Model[I]) VisibleItems() []I {
 }
 
 // SelectedItem returns the current selected item in the list and true if it
-// exists. False otherwise.
+// exists. False otherwise, also while the item is being loaded from data
+// source.
 func (m *Model[I]) SelectedItem() (I, bool) {
-	i := m.Index()
-
-	items := m.VisibleItems()
-	if i < 0 || i >= len(items) {
-		var i I
-		return i, false
-	}
-
-	return items[i], true
+	return m.visibleItem(m.Index())
 }
 
 // MatchesForItem returns rune positions matched by the current filter, if any.
@@ -556,7 +579,8 @@ func (m *Model[I]) itemsAsFilterItems() []filteredItem[I] {
 	fi := make([]filteredItem[I], len(m.items))
 	for i, item := range m.items {
 		fi[i] = filteredItem[I]{
-			item: item,
+			index: i,
+			item:  item,
 		}
 	}
 	return fi
@@ -574,6 +598,11 @@ func (m *Model[I]) updateKeybindings() {
 		m.KeyMap.GoToEnd.SetEnabled(false)
 		m.KeyMap.Filter.SetEnabled(false)
 		m.KeyMap.ClearFilter.SetEnabled(false)
+		m.KeyMap.ToggleMark.SetEnabled(false)
+		m.KeyMap.MarkUp.SetEnabled(false)
+		m.KeyMap.MarkDown.SetEnabled(false)
+		m.KeyMap.MarkAll.SetEnabled(false)
+		m.KeyMap.InvertMarks.SetEnabled(false)
 		m.KeyMap.CancelWhileFiltering.SetEnabled(true)
 		m.KeyMap.AcceptWhileFiltering.SetEnabled(m.FilterInput.Value() != "")
 		m.KeyMap.Quit.SetEnabled(false)
@@ -581,7 +610,7 @@ func (m *Model[I]) updateKeybindings() {
 		m.KeyMap.CloseFullHelp.SetEnabled(false)
 
 	default:
-		hasItems := len(m.items) != 0
+		hasItems := m.hasItems()
 		m.KeyMap.CursorUp.SetEnabled(hasItems)
 		m.KeyMap.CursorDown.SetEnabled(hasItems)
 
@@ -592,6 +621,12 @@ func (m *Model[I]) updateKeybindings() {
 		m.KeyMap.GoToStart.SetEnabled(hasItems)
 		m.KeyMap.GoToEnd.SetEnabled(hasItems)
 
+		m.KeyMap.ToggleMark.SetEnabled(hasItems)
+		m.KeyMap.MarkUp.SetEnabled(hasItems)
+		m.KeyMap.MarkDown.SetEnabled(hasItems)
+		m.KeyMap.MarkAll.SetEnabled(hasItems)
+		m.KeyMap.InvertMarks.SetEnabled(hasItems)
+
 		m.KeyMap.Filter.SetEnabled(m.filteringEnabled && hasItems)
 		m.KeyMap.ClearFilter.SetEnabled(m.filterState == FilterApplied)
 		m.KeyMap.CancelWhileFiltering.SetEnabled(false)
@@ -629,7 +664,7 @@ func (m *Model[I]) updatePagination() {
 
 	m.Paginator.Pager.PerPageSet(max(1, availHeight/(m.Height+m.Spacing)))
 
-	if pages := len(m.VisibleItems()); pages < 1 {
+	if pages := m.visibleLen(); pages < 1 {
 		m.Paginator.SetTotalPages(1)
 	} else {
 		m.Paginator.SetTotalPages(pages)
@@ -672,10 +707,12 @@ func (m *Model[I]) Update(c tea.Context[*Model[I]], msg tea.Msg) {
 
 	if m.filterState == Filtering {
 		m.handleFiltering(c, msg)
-		return
+	} else {
+		m.handleBrowsing(c, msg)
 	}
 
-	m.handleBrowsing(c, msg)
+	// cursor or filter could be changed, so other items must be shown
+	m.load(c)
 }
 
 // Updates for when a user is browsing the list.
@@ -707,6 +744,25 @@ func (m *Model[I]) handleBrowsing(c tea.Context[*Model[I]], msg tea.Msg) {
 		case key.Matches(msg, m.KeyMap.GoToEnd):
 			m.Paginator.Pager.SelectLast()
 
+		case key.Matches(msg, m.KeyMap.ToggleMark):
+			m.ToggleMark()
+		case key.Matches(msg, m.KeyMap.MarkUp):
+			m.Mark(m.Index(), true)
+			m.CursorUp()
+			m.Mark(m.Index(), true)
+		case key.Matches(msg, m.KeyMap.MarkDown):
+			m.Mark(m.Index(), true)
+			m.CursorDown()
+			m.Mark(m.Index(), true)
+		case key.Matches(msg, m.KeyMap.MarkAll):
+			if m.markedCount() < m.visibleLen() {
+				m.MarkAll()
+			} else {
+				m.InvertMarks()
+			}
+		case key.Matches(msg, m.KeyMap.InvertMarks):
+			m.InvertMarks()
+
 		case key.Matches(msg, m.KeyMap.Filter):
 			m.hideStatusMessage()
 			if m.FilterInput.Value() == "" {
@@ -745,14 +801,12 @@ func (m *Model[I]) handleFiltering(c tea.Context[*Model[I]], msg tea.Msg) {
 		case key.Matches(msg, m.KeyMap.AcceptWhileFiltering):
 			m.hideStatusMessage()
 
-			if len(m.items) == 0 {
+			if !m.hasItems() {
 				break
 			}
 
-			h := m.VisibleItems()
-
 			// If we've filtered down to nothing, clear the filter
-			if len(h) == 0 {
+			if m.visibleLen() == 0 && !m.Loading() {
 				m.resetFiltering()
 				break
 			}
@@ -772,9 +826,12 @@ func (m *Model[I]) handleFiltering(c tea.Context[*Model[I]], msg tea.Msg) {
 	m.FilterInput.Update(msg, c.Dispatch)
 	filterChanged := oldValue != m.FilterInput.Value()
 
-	// If the filtering input has changed, request updated filtering
+	// If the filtering input has changed, request updated filtering. Data
+	// source is queried after update.
 	if filterChanged {
-		c.Dispatch(cmdFilterItems(*m))
+		if m.source == nil {
+			c.Dispatch(cmdFilterItems(*m))
+		}
 		m.KeyMap.AcceptWhileFiltering.SetEnabled(m.FilterInput.Value() != "")
 	}
 
@@ -928,7 +985,18 @@ func (m *Model[I]) titleView(vb tea.Viewbox) {
 func (m *Model[I]) statusView(vb tea.Viewbox) {
 	vb = vb.Styled(m.Styles.StatusBar)
 
-	visibleItems := len(m.VisibleItems())
+	if m.Loading() && m.source.total < 0 {
+		m.spinnerView(vb)
+		vb.PaddingLeft(2).WriteLine("Loading…")
+		return
+	}
+
+	if err := m.Err(); err != nil {
+		vb.Styled(m.Styles.StatusEmpty).WriteLine("Error: " + err.Error())
+		return
+	}
+
+	visibleItems := m.visibleLen()
 
 	itemName := m.itemNameSingular
 	if visibleItems != 1 {
@@ -945,7 +1013,7 @@ func (m *Model[I]) statusView(vb tea.Viewbox) {
 		} else {
 			vb = vb.WriteLineX(itemsDisplay)
 		}
-	case len(m.items) == 0:
+	case !m.hasItems():
 		// Not filtering: no items.
 		vb = vb.Styled(m.Styles.StatusEmpty).WriteLineX("No " + m.itemNamePlural)
 	default:
@@ -962,9 +1030,16 @@ func (m *Model[I]) statusView(vb tea.Viewbox) {
 	}
 
 	totalItems := len(m.items)
+	if m.source != nil {
+		totalItems = m.source.all
+	}
 	if numFiltered := totalItems - visibleItems; numFiltered > 0 {
 		vb = vb.Styled(m.Styles.DividerDot).WriteLineX(string([]rune{' ', bullet, ' '}))
-		vb.Styled(m.Styles.StatusBarFilterCount).WriteLine(fmt.Sprintf("%d filtered", numFiltered))
+		vb = vb.Styled(m.Styles.StatusBarFilterCount).WriteLineX(fmt.Sprintf("%d filtered", numFiltered))
+	}
+	if numMarked := m.markedCount(); numMarked > 0 {
+		vb = vb.Styled(m.Styles.DividerDot).WriteLineX(string([]rune{' ', bullet, ' '}))
+		vb.Styled(m.Styles.StatusBarMarkedCount).WriteLine(fmt.Sprintf("%d marked", numMarked))
 	}
 }
 
@@ -985,23 +1060,26 @@ func (m *Model[I]) paginationView(vb tea.Viewbox) {
 }
 
 func (m *Model[I]) populatedView(vb tea.Viewbox) {
-	items := m.VisibleItems()
+	total := m.visibleLen()
 
 	// Empty states
-	if len(items) == 0 {
-		if m.filterState != Filtering {
+	if total == 0 {
+		if m.filterState != Filtering && !m.Loading() {
 			vb.Styled(m.Styles.NoItems).WriteLine("No " + m.itemNamePlural + ".")
 		}
 
 		return
 	}
 
-	start, end := m.Paginator.GetSliceBounds(len(items))
-	docs := items[start:end]
-
-	for i, item := range docs {
-		m.ItemDelegate.Render(vb.MaxHeight(m.Height), m, i+start, item)
-		if i < len(docs)-1 {
+	start, end := m.Paginator.GetSliceBounds(total)
+	for i := start; i < end; i++ {
+		if item, ok := m.visibleItem(i); ok {
+			m.ItemDelegate.Render(vb.MaxHeight(m.Height), m, i, item)
+		} else {
+			// item is being loaded from data source
+			m.spinnerView(vb.PaddingLeft(2).MaxHeight(m.Height))
+		}
+		if i < end-1 {
 			vb = vb.PaddingTop(m.Spacing + m.Height)
 		}
 	}
@@ -1019,6 +1097,13 @@ func (m *Model[I]) helpView(vb tea.Viewbox) {
 			m.KeyMap.Filter,
 			m.KeyMap.ClearFilter,
 		},
+		{
+			m.KeyMap.ToggleMark,
+			m.KeyMap.MarkUp,
+			m.KeyMap.MarkDown,
+			m.KeyMap.MarkAll,
+			m.KeyMap.InvertMarks,
+		},
 		{
 			m.KeyMap.CancelWhileFiltering,
 			m.KeyMap.AcceptWhileFiltering,
@@ -1059,6 +1144,7 @@ func cmdFilterItems[I any](m Model[I]) tea.Cmd {
 		filterMatches := []filteredItem[I]{}
 		for _, r := range m.Filter(m.FilterInput.Value(), targets) {
 			filterMatches = append(filterMatches, filteredItem[I]{
+				index:   r.Index,
 				item:    m.items[r.Index],
 				matches: r.MatchedIndexes,
 			})
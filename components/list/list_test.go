@@ -1,9 +1,17 @@
 package list
 
 import (
+	"context"
 	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rprtr258/assert"
 
 	"github.com/rprtr258/tea"
+	"github.com/rprtr258/tea/components/spinner"
 )
 
 type item string
@@ -43,3 +51,131 @@ var itemDelegate = ItemDelegate[item]{
 // 	list.SetItems([]item{})
 // 	assert.True(t, strings.Contains(list.statusView(), "No connections"))
 // }
+
+type items []item
+
+func (s items) filter(query string) []item {
+	var res []item
+	for _, i := range s {
+		if strings.Contains(string(i), query) {
+			res = append(res, i)
+		}
+	}
+	return res
+}
+
+func (s items) Count(_ context.Context, query string) (int, error) {
+	return len(s.filter(query)), nil
+}
+
+func (s items) Fetch(_ context.Context, query string, offset, limit int) ([]item, error) {
+	items := s.filter(query)
+	return items[offset:min(offset+limit, len(items))], nil
+}
+
+func TestDataSource(t *testing.T) {
+	var queue []func() tea.Msg2[*Model[item]]
+	c := tea.Context[*Model[item]]{
+		Dispatch: func(...tea.Cmd) {},
+		F: func(fs ...func() tea.Msg2[*Model[item]]) {
+			queue = append(queue, fs...)
+		},
+	}
+	m := New(nil, itemDelegate, nil)
+	m.SetSpinner(spinner.Spinner{Frames: []string{"."}})
+	// run requests made so far, responses are applied in order they were made
+	flush := func() {
+		for _, f := range slices.Clone(queue) {
+			queue = queue[1:]
+			f()(&m)
+		}
+	}
+	runes := func(s string) tea.MsgKey {
+		return tea.MsgKey{Type: tea.KeyRunes, Runes: []rune(s)}
+	}
+
+	var src items
+	for i := range 30 {
+		src = append(src, item(fmt.Sprintf("item %d", i)))
+	}
+	m.SetDataSource(c, src)
+	assert.True(t, m.Loading())
+	flush()
+	assert.Equal(t, 30, m.visibleLen())
+	flush()
+	assert.False(t, m.Loading())
+	assert.Equal(t, []item{"item 0"}, m.VisibleItems())
+
+	// item of the next page is fetched as cursor moves to it
+	m.Update(c, tea.MsgKey{Type: tea.KeyDown})
+	_, ok := m.SelectedItem()
+	assert.False(t, ok)
+	flush()
+	selected, _ := m.SelectedItem()
+	assert.Equal(t, item("item 1"), selected)
+
+	// source is queried once filter stops changing, responses to outdated
+	// filter queries are dropped
+	m.SourceDebounce = time.Millisecond
+	gen := m.source.gen
+	m.Update(c, runes("/"))
+	m.Update(c, runes("1"))
+	m.Update(c, runes("2"))
+	flush()
+	assert.True(t, m.Loading())
+	assert.Equal(t, gen+1, m.source.gen)
+	assert.Equal(t, "12", m.source.query)
+	flush()
+	assert.Equal(t, 1, m.visibleLen())
+	flush()
+	assert.Equal(t, []item{"item 12"}, m.VisibleItems())
+
+	// selection is kept when items are reloaded
+	m.Update(c, tea.MsgKey{Type: tea.KeyEsc})
+	flush()
+	flush()
+	m.Select(5)
+	m.Reload(c)
+	flush()
+	flush()
+	assert.Equal(t, 5, m.Index())
+	selected, _ = m.SelectedItem()
+	assert.Equal(t, item("item 5"), selected)
+
+	// items are used again without data source
+	m.SetItems([]item{"a", "b"})
+	m.SetDataSource(c, src)
+	m.SetDataSource(c, nil)
+	assert.Equal(t, []item{"a", "b"}, m.VisibleItems())
+}
+
+func TestMarks(t *testing.T) {
+	c := tea.Context[*Model[item]]{
+		Dispatch: func(cmds ...tea.Cmd) {},
+		F:        func(...func() tea.Msg2[*Model[item]]) {},
+	}
+	press := func(m *Model[item], keys ...tea.MsgKey) {
+		for _, k := range keys {
+			m.Update(c, k)
+		}
+	}
+	space := tea.MsgKey{Type: tea.KeySpace, Runes: []rune{' '}}
+
+	m := New([]item{"foo", "bar", "baz", "qux"}, itemDelegate, func(i item) string { return string(i) })
+	press(&m, space, tea.MsgKey{Type: tea.KeyShiftDown})
+	assert.Equal(t, []item{"foo", "bar"}, m.MarkedItems())
+
+	// marks stay on items while filtered
+	m.filterState = FilterApplied
+	m.FilterInput.SetValue("ba")
+	m.Update(c, cmdFilterItems(m)())
+	assert.Equal(t, []item{"bar"}, m.MarkedItems())
+	press(&m, tea.MsgKey{Type: tea.KeyCtrlA})
+	assert.Equal(t, 2, len(m.MarkedItems()))
+
+	m.resetFiltering()
+	assert.Equal(t, []item{"foo", "bar", "baz"}, m.MarkedItems())
+	m.RemoveItem(1)
+	press(&m, tea.MsgKey{Type: tea.KeyRunes, Runes: []rune("*")})
+	assert.Equal(t, []item{"qux"}, m.MarkedItems())
+}
@@ -0,0 +1,93 @@
+package list
+
+// itemIndex returns key of visible item in marks: its index in items, so
+// that marks stay while filter changes, or index among items loaded from
+// data source for current query.
+func (m *Model[I]) itemIndex(index int) int {
+	if m.source == nil && m.filterState != Unfiltered && index < len(m.filteredItems) {
+		return m.filteredItems[index].index
+	}
+	return index
+}
+
+// IsMarked returns whether visible item at index is marked. Delegates use it
+// to render marked items.
+func (m *Model[I]) IsMarked(index int) bool {
+	return m.marked[m.itemIndex(index)]
+}
+
+// Mark marks or unmarks visible item at index.
+func (m *Model[I]) Mark(index int, marked bool) {
+	if marked {
+		m.marked[m.itemIndex(index)] = true
+	} else {
+		delete(m.marked, m.itemIndex(index))
+	}
+}
+
+// ToggleMark toggles mark of selected item.
+func (m *Model[I]) ToggleMark() {
+	if index := m.Index(); index >= 0 && index < m.visibleLen() {
+		m.Mark(index, !m.IsMarked(index))
+	}
+}
+
+// MarkAll marks all visible items, e.g. all items matching filter.
+func (m *Model[I]) MarkAll() {
+	for i := range m.visibleLen() {
+		m.Mark(i, true)
+	}
+}
+
+// InvertMarks inverts marks of visible items.
+func (m *Model[I]) InvertMarks() {
+	for i := range m.visibleLen() {
+		m.Mark(i, !m.IsMarked(i))
+	}
+}
+
+// ClearMarks unmarks all items, including ones hidden by filter.
+func (m *Model[I]) ClearMarks() {
+	clear(m.marked)
+}
+
+// MarkedItems returns marked items in display order. Items hidden by filter
+// or not loaded from data source yet are not returned.
+func (m *Model[I]) MarkedItems() []I {
+	var items []I
+	for i := range m.visibleLen() {
+		if !m.IsMarked(i) {
+			continue
+		}
+
+		if item, ok := m.visibleItem(i); ok {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// markedCount returns number of marked visible items.
+func (m *Model[I]) markedCount() int {
+	count := 0
+	for i := range m.visibleLen() {
+		if m.IsMarked(i) {
+			count++
+		}
+	}
+	return count
+}
+
+// shiftMarks moves marks of items starting from index by delta, after items
+// are inserted or removed.
+func (m *Model[I]) shiftMarks(index, delta int) {
+	marked := make(map[int]bool, len(m.marked))
+	for i := range m.marked {
+		if i < index {
+			marked[i] = true
+		} else {
+			marked[i+delta] = true
+		}
+	}
+	m.marked = marked
+}
@@ -0,0 +1,296 @@
+package list
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/rprtr258/tea"
+)
+
+// DataSource provides items lazily, e.g. from paginated API, so that list
+// does not need all of them up front. Filtering is done by the source too.
+type DataSource[I any] interface {
+	// Count returns number of items matching query. Query is empty when list
+	// is not filtered.
+	Count(ctx context.Context, query string) (int, error)
+	// Fetch returns items in range [offset, offset+limit) matching query.
+	// Returning less items than asked means there are no more items.
+	Fetch(ctx context.Context, query string, offset, limit int) ([]I, error)
+}
+
+// source is the state of items loaded from data source for current query.
+type source[I any] struct {
+	DataSource[I]
+
+	// gen is incremented on each query, so that responses to outdated
+	// queries are dropped
+	gen int
+	// ctx is canceled when query changes, aborting its requests
+	ctx    context.Context
+	cancel context.CancelFunc
+	query  string
+	// total is number of items matching query, -1 while counting
+	total int
+	// all is number of items without filter
+	all     int
+	items   map[int]I
+	pending map[int]bool // offsets of pages being fetched
+	err     error
+	// waiting is query to be made once filter stops changing, wait is
+	// incremented on each change, so that only the last wait is finished
+	waiting *string
+	wait    int
+}
+
+// SetDataSource makes list load items from src as they are shown, instead of
+// items set with SetItems. Filter query is passed to src, so Filter is not
+// used. Items set with SetItems are kept, nil src makes list use them again.
+func (m *Model[I]) SetDataSource(c tea.Context[*Model[I]], src DataSource[I]) {
+	if m.source != nil {
+		m.source.cancel()
+	}
+
+	m.source = nil
+	if src != nil {
+		m.source = &source[I]{DataSource: src}
+		m.query(c)
+	}
+
+	m.updatePagination()
+	m.updateKeybindings()
+}
+
+// Reload drops loaded items and loads them again from data source. Selected
+// index is kept, marks are cleared.
+func (m *Model[I]) Reload(c tea.Context[*Model[I]]) {
+	if m.source == nil {
+		return
+	}
+
+	m.query(c)
+}
+
+// Loading returns whether items are being loaded from data source.
+func (m *Model[I]) Loading() bool {
+	return m.source != nil && (m.source.total < 0 || len(m.source.pending) > 0)
+}
+
+// Err returns error of loading items from data source, if any.
+func (m *Model[I]) Err() error {
+	if m.source == nil {
+		return nil
+	}
+	return m.source.err
+}
+
+// filterQuery returns query passed to data source.
+func (m *Model[I]) filterQuery() string {
+	if m.filterState == Unfiltered {
+		return ""
+	}
+	return m.FilterInput.Value()
+}
+
+// query cancels requests of previous query and starts counting items
+// matching current filter.
+func (m *Model[I]) query(c tea.Context[*Model[I]]) {
+	s := m.source
+	if s.cancel != nil {
+		s.cancel()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.gen++
+	s.ctx, s.cancel = ctx, cancel
+	s.query = m.filterQuery()
+	s.total = -1
+	s.items = map[int]I{}
+	s.pending = map[int]bool{}
+	s.err = nil
+	// marks are kept by index, which changes with query
+	m.ClearMarks()
+	m.StartSpinner(c)
+
+	gen, src, query := s.gen, s.DataSource, s.query
+	c.F(func() tea.Msg2[*Model[I]] {
+		total, err := src.Count(ctx, query)
+		return func(m *Model[I]) {
+			m.counted(c, gen, total, err)
+		}
+	})
+}
+
+// counted handles response to counting items.
+func (m *Model[I]) counted(c tea.Context[*Model[I]], gen, total int, err error) {
+	s := m.source
+	if s == nil || s.gen != gen {
+		return
+	}
+
+	s.total = total
+	if err != nil {
+		s.err = err
+		s.total = 0
+	}
+	if s.query == "" {
+		s.all = s.total
+	}
+
+	m.updatePagination()
+	m.updateKeybindings()
+	m.fetchVisible(c)
+}
+
+// fetchVisible fetches items of current page which are not loaded yet.
+func (m *Model[I]) fetchVisible(c tea.Context[*Model[I]]) {
+	s := m.source
+	if s == nil || s.total < 0 {
+		return
+	}
+
+	start, end := m.Paginator.GetSliceBounds(s.total)
+	for i := start; i < end; i++ {
+		if _, ok := s.items[i]; ok {
+			continue
+		}
+
+		if s.pending[start] || s.err != nil {
+			break
+		}
+
+		s.pending[start] = true
+		gen, src, ctx, query, limit := s.gen, s.DataSource, s.ctx, s.query, end-start
+		c.F(func() tea.Msg2[*Model[I]] {
+			items, err := src.Fetch(ctx, query, start, limit)
+			return func(m *Model[I]) {
+				m.fetched(c, gen, start, limit, items, err)
+			}
+		})
+		break
+	}
+
+	if !m.Loading() {
+		m.StopSpinner()
+	}
+}
+
+// fetched handles response to fetching items.
+func (m *Model[I]) fetched(c tea.Context[*Model[I]], gen, offset, limit int, items []I, err error) {
+	s := m.source
+	if s == nil || s.gen != gen {
+		return
+	}
+
+	delete(s.pending, offset)
+	if err != nil {
+		s.err = err
+		if !m.Loading() {
+			m.StopSpinner()
+		}
+		return
+	}
+
+	for i, item := range items {
+		s.items[offset+i] = item
+	}
+	if len(items) < limit {
+		// source has less items than counted
+		s.total = offset + len(items)
+		if s.query == "" {
+			s.all = s.total
+		}
+		m.updatePagination()
+		m.updateKeybindings()
+	}
+
+	// page could be changed while items were being fetched
+	m.fetchVisible(c)
+}
+
+// load queries data source if filter has changed, otherwise fetches items of
+// current page.
+func (m *Model[I]) load(c tea.Context[*Model[I]]) {
+	if m.source == nil {
+		return
+	}
+
+	if query := m.filterQuery(); m.source.query != query {
+		m.debounceQuery(c, query)
+		return
+	}
+
+	m.fetchVisible(c)
+}
+
+// debounceQuery queries data source once filter is not changed for
+// SourceDebounce, so that source is not queried on each keystroke.
+func (m *Model[I]) debounceQuery(c tea.Context[*Model[I]], query string) {
+	s := m.source
+	if m.SourceDebounce <= 0 {
+		m.query(c)
+		return
+	}
+	if s.waiting != nil && *s.waiting == query {
+		return
+	}
+
+	s.wait++
+	s.waiting = &query
+	wait, d := s.wait, m.SourceDebounce
+	c.F(func() tea.Msg2[*Model[I]] {
+		time.Sleep(d)
+		return func(m *Model[I]) {
+			if m.source != s || s.wait != wait {
+				return
+			}
+
+			s.waiting = nil
+			if s.query != m.filterQuery() {
+				m.query(c)
+			}
+		}
+	})
+}
+
+// visibleLen returns number of items matching filter.
+func (m *Model[I]) visibleLen() int {
+	if m.source != nil {
+		return max(m.source.total, 0)
+	}
+	return len(m.VisibleItems())
+}
+
+// hasItems returns whether there are any items, regardless of filter.
+func (m *Model[I]) hasItems() bool {
+	if m.source != nil {
+		return m.source.all != 0
+	}
+	return len(m.items) != 0
+}
+
+// visibleItem returns item at index among items matching filter, false if
+// it is not loaded yet.
+func (m *Model[I]) visibleItem(index int) (I, bool) {
+	if m.source != nil {
+		item, ok := m.source.items[index]
+		return item, ok
+	}
+
+	items := m.VisibleItems()
+	if index < 0 || index >= len(items) {
+		var item I
+		return item, false
+	}
+	return items[index], true
+}
+
+// loadedItems returns items loaded from data source in order.
+func (m *Model[I]) loadedItems() []I {
+	items := make([]I, 0, len(m.source.items))
+	for _, i := range slices.Sorted(maps.Keys(m.source.items)) {
+		items = append(items, m.source.items[i])
+	}
+	return items
+}
@@ -26,6 +26,7 @@ type Styles struct {
 	StatusEmpty           styles.Style
 	StatusBarActiveFilter styles.Style
 	StatusBarFilterCount  styles.Style
+	StatusBarMarkedCount  styles.Style
 
 	NoItems styles.Style
 
@@ -66,6 +67,8 @@ var (
 			Foreground(styles.FgAdaptiveColor("#1a1a1a", "#dddddd")),
 		StatusBarFilterCount: styles.Style{}.
 			Foreground(verySubduedColor),
+		StatusBarMarkedCount: styles.Style{}.
+			Foreground(styles.FgColor("214")),
 		NoItems: styles.Style{}.
 			Foreground(styles.FgAdaptiveColor("#909090", "#626262")),
 		ArabicPagination: styles.Style{}.
@@ -0,0 +1,209 @@
+package logview
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/rprtr258/scuf"
+
+	"github.com/rprtr258/tea/styles"
+)
+
+// _tab replaces tabs, as they are not rendered by framebuffer.
+const _tab = "    "
+
+// segment is a part of line rendered with the same style.
+type segment struct {
+	text string
+	// start is byte offset of text in line without escape sequences
+	start int
+	style styles.Style
+	// styled is whether style is set by escape sequences, otherwise line
+	// is styled according to its level
+	styled bool
+}
+
+// sgr is graphic rendition set by SGR escape sequences.
+type sgr struct {
+	fg, bg                                                        scuf.Modifier
+	bold, faint, italic, underline, blink, reverse, strikethrough bool
+}
+
+// extendedColor parses parameters of 256 or true color after 38 or 48 code,
+// returns color and number of parameters used.
+func extendedColor(params []string, fg bool) (scuf.Modifier, int) {
+	atoi := func(s string) int {
+		n, _ := strconv.Atoi(s)
+		return n
+	}
+
+	switch {
+	case len(params) >= 2 && params[0] == "5":
+		if fg {
+			return scuf.FgANSI(atoi(params[1])), 2
+		}
+		return scuf.BgANSI(atoi(params[1])), 2
+	case len(params) >= 4 && params[0] == "2":
+		r, g, b := uint8(atoi(params[1])), uint8(atoi(params[2])), uint8(atoi(params[3]))
+		if fg {
+			return scuf.FgRGB(r, g, b), 4
+		}
+		return scuf.BgRGB(r, g, b), 4
+	default:
+		return nil, len(params)
+	}
+}
+
+// apply applies SGR parameters, e.g. "1;31" from "\x1b[1;31m".
+func (s *sgr) apply(params string) {
+	codes := strings.FieldsFunc(params, func(r rune) bool { return r == ';' || r == ':' })
+	if len(codes) == 0 {
+		*s = sgr{}
+		return
+	}
+
+	for i := 0; i < len(codes); i++ {
+		n, err := strconv.Atoi(codes[i])
+		if err != nil {
+			continue
+		}
+
+		switch {
+		case n == 0:
+			*s = sgr{}
+		case n == 1:
+			s.bold = true
+		case n == 2:
+			s.faint = true
+		case n == 3:
+			s.italic = true
+		case n == 4:
+			s.underline = true
+		case n == 5, n == 6:
+			s.blink = true
+		case n == 7:
+			s.reverse = true
+		case n == 9:
+			s.strikethrough = true
+		case n == 22:
+			s.bold, s.faint = false, false
+		case n == 23:
+			s.italic = false
+		case n == 24:
+			s.underline = false
+		case n == 25:
+			s.blink = false
+		case n == 27:
+			s.reverse = false
+		case n == 29:
+			s.strikethrough = false
+		case n >= 30 && n <= 37:
+			s.fg = scuf.FgANSI(n - 30)
+		case n == 38:
+			color, used := extendedColor(codes[i+1:], true)
+			s.fg = color
+			i += used
+		case n == 39:
+			s.fg = nil
+		case n >= 40 && n <= 47:
+			s.bg = scuf.BgANSI(n - 40)
+		case n == 48:
+			color, used := extendedColor(codes[i+1:], false)
+			s.bg = color
+			i += used
+		case n == 49:
+			s.bg = nil
+		case n >= 90 && n <= 97:
+			s.fg = scuf.FgANSI(n - 90 + 8)
+		case n >= 100 && n <= 107:
+			s.bg = scuf.BgANSI(n - 100 + 8)
+		}
+	}
+}
+
+// style returns style of graphic rendition and whether it is not default.
+func (s sgr) style() (styles.Style, bool) {
+	st := styles.Style{}.
+		Foreground(s.fg).
+		Background(s.bg).
+		Bold(s.bold).
+		Reverse(s.reverse).
+		Strikethrough(s.strikethrough)
+	if s.faint {
+		st = st.Faint()
+	}
+	if s.italic {
+		st = st.Italic()
+	}
+	if s.underline {
+		st = st.Underline()
+	}
+	if s.blink {
+		st = st.Blink()
+	}
+	return st, s.fg != nil || s.bg != nil || s.bold || s.faint || s.italic || s.underline || s.blink || s.reverse || s.strikethrough
+}
+
+// parseANSI splits text into segments styled by SGR escape sequences. Other
+// escape sequences, e.g. hyperlinks or cursor movement, and control
+// characters are dropped. Returns text without them too.
+func parseANSI(text string) (string, []segment) {
+	var (
+		plain    strings.Builder
+		segments []segment
+		state    sgr
+		current  segment
+	)
+	// flush ends current segment, next one is styled by current state
+	flush := func() {
+		if plain.Len() > current.start {
+			current.text = plain.String()[current.start:]
+			segments = append(segments, current)
+		}
+		style, styled := state.style()
+		current = segment{start: plain.Len(), style: style, styled: styled}
+	}
+
+	for i := 0; i < len(text); {
+		switch c := text[i]; {
+		case c == '\x1b' && strings.HasPrefix(text[i:], "\x1b["):
+			// control sequence: parameters and intermediate bytes, then
+			// final byte
+			j := i + 2
+			for j < len(text) && (text[j] < 0x40 || text[j] > 0x7e) {
+				j++
+			}
+			if j < len(text) && text[j] == 'm' {
+				flush()
+				state.apply(text[i+2 : j])
+				flush()
+			}
+			i = j + 1
+		case c == '\x1b' && strings.HasPrefix(text[i:], "\x1b]"):
+			// operating system command, e.g. hyperlink, terminated by BEL
+			// or ST
+			j := i + 2
+			for j < len(text) && text[j] != '\a' && !strings.HasPrefix(text[j:], "\x1b\\") {
+				j++
+			}
+			if strings.HasPrefix(text[j:], "\x1b\\") {
+				j++
+			}
+			i = j + 1
+		case c == '\x1b':
+			// two characters escape sequence
+			i += 2
+		case c == '\t':
+			plain.WriteString(_tab)
+			i++
+		case c < 0x20 || c == 0x7f:
+			i++
+		default:
+			plain.WriteByte(c)
+			i++
+		}
+	}
+	flush()
+
+	return plain.String(), segments
+}
@@ -0,0 +1,594 @@
+package logview
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/rivo/uniseg"
+
+	"github.com/rprtr258/tea"
+	"github.com/rprtr258/tea/components/key"
+	"github.com/rprtr258/tea/components/textinput"
+	"github.com/rprtr258/tea/components/viewport"
+	"github.com/rprtr258/tea/styles"
+)
+
+const (
+	// _batchSize is maximum number of lines added at once, so that fast
+	// source does not stall rendering.
+	_batchSize = 1000
+	// _maxLineSize is maximum size of line read, longer lines are errors.
+	_maxLineSize = 1 << 20
+)
+
+// _lastID is used to generate unique ids of log views.
+var _lastID atomic.Int64
+
+// Line is a line of log.
+type Line struct {
+	// Text is line as read, possibly with ANSI escape sequences.
+	Text string
+	// Level is level parsed from line or, if line has none, level of the
+	// previous line, e.g. for lines of stack trace.
+	Level Level
+	// Time is time parsed from line, zero if line has none.
+	Time time.Time
+
+	// plain is text without escape sequences, which is matched by filter
+	plain    string
+	segments []segment
+}
+
+// source is a channel lines are received from, err is set before it is
+// closed if lines are read from reader.
+type source struct {
+	lines <-chan string
+	err   error
+}
+
+type msgLines struct {
+	id     int64
+	source *source
+	lines  []string
+	// done is whether source is closed
+	done bool
+}
+
+// KeyMap defines keybindings of log view.
+type KeyMap struct {
+	LineUp, LineDown         key.Binding
+	PageUp, PageDown         key.Binding
+	HalfPageUp, HalfPageDown key.Binding
+	GotoTop, GotoBottom      key.Binding
+	// Follow toggles following new lines.
+	Follow key.Binding
+	// Level cycles minimum level of shown lines.
+	Level key.Binding
+
+	Filter, AcceptFilter, CancelFilter key.Binding
+}
+
+// DefaultKeyMap is default set of keybindings.
+var DefaultKeyMap = KeyMap{
+	LineUp: key.Binding{
+		Keys: []string{"up", "k"},
+		Help: key.Help{"↑/k", "up"},
+	},
+	LineDown: key.Binding{
+		Keys: []string{"down", "j"},
+		Help: key.Help{"↓/j", "down"},
+	},
+	PageUp: key.Binding{
+		Keys: []string{"b", "pgup"},
+		Help: key.Help{"b/pgup", "page up"},
+	},
+	PageDown: key.Binding{
+		Keys: []string{"f", "pgdown"},
+		Help: key.Help{"f/pgdn", "page down"},
+	},
+	HalfPageUp: key.Binding{
+		Keys: []string{"u", "ctrl+u"},
+		Help: key.Help{"u", "½ page up"},
+	},
+	HalfPageDown: key.Binding{
+		Keys: []string{"d", "ctrl+d"},
+		Help: key.Help{"d", "½ page down"},
+	},
+	GotoTop: key.Binding{
+		Keys: []string{"home", "g"},
+		Help: key.Help{"g/home", "go to start"},
+	},
+	GotoBottom: key.Binding{
+		Keys: []string{"end", "G"},
+		Help: key.Help{"G/end", "go to end"},
+	},
+	Follow: key.Binding{
+		Keys: []string{"F"},
+		Help: key.Help{"F", "follow"},
+	},
+	Level: key.Binding{
+		Keys: []string{"l"},
+		Help: key.Help{"l", "cycle level"},
+	},
+	Filter: key.Binding{
+		Keys: []string{"/"},
+		Help: key.Help{"/", "filter"},
+	},
+	AcceptFilter: key.Binding{
+		Keys: []string{"enter"},
+		Help: key.Help{"enter", "apply filter"},
+	},
+	CancelFilter: key.Binding{
+		Keys: []string{"esc"},
+		Help: key.Help{"esc", "cancel filter"},
+	},
+}
+
+// Styles contains style definitions of log view.
+type Styles struct {
+	// Levels are styles of lines by their level, lines with colors of
+	// their own keep them.
+	Levels map[Level]styles.Style
+	// Match is style of filter matches, applied over line style.
+	Match  styles.Style
+	Status styles.Style
+	Error  styles.Style
+}
+
+// DefaultStyles is default styling of log view.
+var DefaultStyles = Styles{
+	Levels: map[Level]styles.Style{
+		LevelTrace: styles.Style{}.Faint(),
+		LevelDebug: styles.Style{}.Foreground(styles.FgColor("245")),
+		LevelWarn:  styles.Style{}.Foreground(styles.FgColor("11")),
+		LevelError: styles.Style{}.Foreground(styles.FgColor("9")),
+		LevelFatal: styles.Style{}.Bold(true).Foreground(styles.FgColor("9")),
+	},
+	Match:  styles.Style{}.Reverse(true),
+	Status: styles.Style{}.Faint(),
+	Error:  styles.Style{}.Foreground(styles.FgColor("9")),
+}
+
+// Model is a log viewer, keeping the last lines read from readers or
+// received from channels.
+type Model struct {
+	id    int64
+	lines ring[Line]
+	// shown are sequence numbers of lines passing level and filter, in
+	// order
+	shown []int
+
+	// Follow keeps the last line shown as lines are added. It is unset when
+	// view is scrolled up, and set again when scrolled to the bottom.
+	Follow bool
+	// Err is error of reading lines, if any.
+	Err error
+
+	level     Level
+	regex     *regexp.Regexp
+	filter    textinput.Model
+	filtering bool
+
+	viewport viewport.Model
+
+	KeyMap KeyMap
+	Styles Styles
+}
+
+// New returns log view keeping at most capacity lines, the oldest lines are
+// dropped when more are added.
+func New(capacity, height, width int) Model {
+	filter := textinput.New()
+	filter.Prompt = "/ "
+	filter.Check = func(s string) error {
+		_, err := regexp.Compile(s)
+		return err
+	}
+
+	return Model{
+		id:       _lastID.Add(1),
+		lines:    newRing[Line](max(capacity, 1)),
+		Follow:   true,
+		filter:   filter,
+		viewport: viewport.New(width, height),
+		KeyMap:   DefaultKeyMap,
+		Styles:   DefaultStyles,
+	}
+}
+
+// SetSize sets size of log view.
+func (m *Model) SetSize(height, width int) {
+	m.viewport.Height = height
+	m.viewport.Width = width
+	m.scrolled()
+}
+
+// Append adds line, dropping the oldest one if there are too many.
+func (m *Model) Append(text string) {
+	plain, segments := parseANSI(text)
+	line := Line{
+		Text:     text,
+		plain:    plain,
+		segments: segments,
+	}
+	line.Level, line.Time = parseFields(plain)
+	if line.Level == LevelUnknown && m.lines.len() > 0 {
+		line.Level = m.lines.at(m.lines.last()).Level
+	}
+
+	if m.lines.push(line) && len(m.shown) > 0 && m.shown[0] < m.lines.dropped {
+		m.shown = m.shown[1:]
+		if !m.Follow {
+			// keep the same lines in view
+			m.viewport.LineUp(1)
+		}
+	}
+	if m.matches(line) {
+		m.shown = append(m.shown, m.lines.last())
+	}
+	m.scrolled()
+}
+
+// Len returns number of shown lines.
+func (m *Model) Len() int {
+	return len(m.shown)
+}
+
+// Line returns i-th shown line.
+func (m *Model) Line(i int) Line {
+	return m.lines.at(m.shown[i])
+}
+
+// Listen returns command adding lines received from channel, until it is
+// closed.
+func (m *Model) Listen(lines <-chan string) tea.Cmd {
+	return m.cmdReceive(&source{lines: lines})
+}
+
+// Read returns command adding lines read from reader, until it is
+// exhausted. Reading error is set to Err.
+func (m *Model) Read(r io.Reader) tea.Cmd {
+	lines := make(chan string, _batchSize)
+	src := &source{lines: lines}
+	go func() {
+		defer close(lines)
+
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(nil, _maxLineSize)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		src.err = scanner.Err()
+	}()
+	return m.cmdReceive(src)
+}
+
+// cmdReceive returns command waiting for lines from source, which receives
+// lines available at once.
+func (m *Model) cmdReceive(src *source) tea.Cmd {
+	id := m.id
+	return func() tea.Msg {
+		line, ok := <-src.lines
+		if !ok {
+			return msgLines{id: id, source: src, done: true}
+		}
+
+		lines := []string{line}
+		for len(lines) < _batchSize {
+			select {
+			case line, ok := <-src.lines:
+				if !ok {
+					return msgLines{id: id, source: src, lines: lines, done: true}
+				}
+				lines = append(lines, line)
+			default:
+				return msgLines{id: id, source: src, lines: lines}
+			}
+		}
+		return msgLines{id: id, source: src, lines: lines}
+	}
+}
+
+// matches returns whether line passes level and filter.
+func (m *Model) matches(line Line) bool {
+	return (line.Level == LevelUnknown || line.Level >= m.level) &&
+		(m.regex == nil || m.regex.MatchString(line.plain))
+}
+
+// refilter recomputes shown lines, keeping the top line in view if it is
+// still shown.
+func (m *Model) refilter() {
+	top := -1
+	if m.viewport.YOffset < len(m.shown) {
+		top = m.shown[m.viewport.YOffset]
+	}
+
+	m.shown = m.shown[:0]
+	for seq := m.lines.dropped; seq <= m.lines.last(); seq++ {
+		if m.matches(m.lines.at(seq)) {
+			m.shown = append(m.shown, seq)
+		}
+	}
+
+	if i, _ := slices.BinarySearch(m.shown, top); top != -1 {
+		m.viewport.SetYOffset(i)
+	}
+	m.scrolled()
+}
+
+// SetLevel shows only lines of given level and above. Lines of unknown
+// level are always shown.
+func (m *Model) SetLevel(level Level) {
+	m.level = level
+	m.refilter()
+}
+
+// Level returns minimum level of shown lines.
+func (m *Model) Level() Level {
+	return m.level
+}
+
+// SetFilter shows only lines matching regular expression, highlighting
+// matches. Empty pattern shows all lines. If pattern is invalid, filter is
+// not changed.
+func (m *Model) SetFilter(pattern string) error {
+	m.filter.SetValue(pattern)
+
+	var regex *regexp.Regexp
+	if pattern != "" {
+		var err error
+		if regex, err = regexp.Compile(pattern); err != nil {
+			return err
+		}
+	}
+
+	m.regex = regex
+	m.refilter()
+	return nil
+}
+
+// FilterValue returns current filter pattern.
+func (m *Model) FilterValue() string {
+	return m.filter.Value()
+}
+
+// Filtering returns whether filter pattern is being typed.
+func (m *Model) Filtering() bool {
+	return m.filtering
+}
+
+// statusShown returns whether status line with filter and level is shown.
+func (m *Model) statusShown() bool {
+	return m.filtering || m.filter.Value() != "" || m.level != LevelUnknown
+}
+
+// linesHeight returns number of lines in view.
+func (m *Model) linesHeight() int {
+	if m.statusShown() {
+		return max(m.viewport.Height-1, 0)
+	}
+	return m.viewport.Height
+}
+
+// scrolled keeps view within shown lines, at the bottom if following.
+func (m *Model) scrolled() {
+	bottom := max(len(m.shown)-m.linesHeight(), 0)
+	if m.Follow || m.viewport.YOffset > bottom {
+		m.viewport.SetYOffset(bottom)
+	}
+}
+
+// scroll moves view by delta lines, following if it reaches the bottom.
+func (m *Model) scroll(delta int) {
+	m.viewport.SetYOffset(m.viewport.YOffset + delta)
+	m.Follow = m.viewport.YOffset >= len(m.shown)-m.linesHeight()
+	m.scrolled()
+}
+
+// updateFilter handles keys while filter is being typed.
+func (m *Model) updateFilter(msg tea.MsgKey, f func(...tea.Cmd)) {
+	switch {
+	case key.Matches(msg, m.KeyMap.AcceptFilter):
+		if m.filter.Err == nil {
+			m.filtering = false
+			m.filter.Blur()
+		}
+	case key.Matches(msg, m.KeyMap.CancelFilter):
+		m.filtering = false
+		m.filter.Blur()
+		_ = m.SetFilter("")
+	default:
+		pattern := m.filter.Value()
+		m.filter.Update(msg, f)
+		if m.filter.Value() != pattern && m.filter.Err == nil {
+			_ = m.SetFilter(m.filter.Value())
+		}
+	}
+}
+
+// Update handles lines received, scrolling and filtering.
+func (m *Model) Update(msg tea.Msg, f func(...tea.Cmd)) {
+	switch msg := msg.(type) {
+	case msgLines:
+		if msg.id != m.id {
+			return
+		}
+
+		for _, line := range msg.lines {
+			m.Append(line)
+		}
+		if msg.done {
+			m.Err = msg.source.err
+		} else {
+			f(m.cmdReceive(msg.source))
+		}
+	case tea.MsgMouse:
+		switch msg.Type {
+		case tea.MouseWheelUp:
+			m.scroll(-m.viewport.MouseWheelDelta)
+		case tea.MouseWheelDown:
+			m.scroll(m.viewport.MouseWheelDelta)
+		}
+	case tea.MsgKey:
+		if m.filtering {
+			m.updateFilter(msg, f)
+			return
+		}
+
+		height := m.linesHeight()
+		switch {
+		case key.Matches(msg, m.KeyMap.LineUp):
+			m.scroll(-1)
+		case key.Matches(msg, m.KeyMap.LineDown):
+			m.scroll(1)
+		case key.Matches(msg, m.KeyMap.PageUp):
+			m.scroll(-height)
+		case key.Matches(msg, m.KeyMap.PageDown):
+			m.scroll(height)
+		case key.Matches(msg, m.KeyMap.HalfPageUp):
+			m.scroll(-height / 2)
+		case key.Matches(msg, m.KeyMap.HalfPageDown):
+			m.scroll(height / 2)
+		case key.Matches(msg, m.KeyMap.GotoTop):
+			m.scroll(-m.viewport.YOffset)
+		case key.Matches(msg, m.KeyMap.GotoBottom):
+			m.scroll(len(m.shown))
+		case key.Matches(msg, m.KeyMap.Follow):
+			m.Follow = !m.Follow
+			m.scrolled()
+		case key.Matches(msg, m.KeyMap.Level):
+			// cycle through all, debug and above, ..., fatal only
+			m.SetLevel((m.level + 1) % (LevelFatal + 1))
+		case key.Matches(msg, m.KeyMap.Filter):
+			m.filtering = true
+			f(m.filter.Focus()...)
+			m.scrolled()
+		}
+	}
+}
+
+// overlay returns style with attributes set in over applied on top of it.
+func overlay(style, over styles.Style) styles.Style {
+	if fg := over.GetForeground(); fg != nil {
+		style = style.Foreground(fg)
+	}
+	if bg := over.GetBackground(); bg != nil {
+		style = style.Background(bg)
+	}
+	if over.GetBold() {
+		style = style.Bold(true)
+	}
+	if over.GetFaint() {
+		style = style.Faint()
+	}
+	if over.GetItalic() {
+		style = style.Italic()
+	}
+	if over.GetUnderline() {
+		style = style.Underline()
+	}
+	if over.GetBlink() {
+		style = style.Blink()
+	}
+	if over.GetReverse() {
+		style = style.Reverse(true)
+	}
+	if over.GetStrikethrough() {
+		style = style.Strikethrough(true)
+	}
+	return style
+}
+
+// writeRun writes text at x with style, so that background is set under
+// text only. Returns x after text.
+func writeRun(vb tea.Viewbox, x int, text string, style styles.Style) int {
+	width := min(uniseg.StringWidth(text), vb.Width-x)
+	if width <= 0 {
+		return x
+	}
+
+	vbRun := vb.Padding(tea.PaddingOptions{Left: x, Right: vb.Width - x - width})
+	return x + vbRun.Styled(style).WriteLine(text)
+}
+
+// viewLine renders line with its colors, or colored by level if it has
+// none, and filter matches highlighted.
+func (m *Model) viewLine(vb tea.Viewbox, line Line) {
+	var matches [][]int
+	if m.regex != nil {
+		matches = m.regex.FindAllStringIndex(line.plain, -1)
+	}
+
+	x := 0
+	for _, seg := range line.segments {
+		style := seg.style
+		if !seg.styled {
+			style = m.Styles.Levels[line.Level]
+		}
+
+		// split segment by match bounds
+		for start, end := seg.start, seg.start+len(seg.text); start < end && x < vb.Width; {
+			next, inMatch := end, false
+			for _, match := range matches {
+				if match[1] <= start || match[0] == match[1] {
+					continue
+				}
+				if match[0] <= start {
+					next, inMatch = min(end, match[1]), true
+				} else {
+					next = min(end, match[0])
+				}
+				break
+			}
+
+			runStyle := style
+			if inMatch {
+				runStyle = overlay(style, m.Styles.Match)
+			}
+			x = writeRun(vb, x, line.plain[start:next], runStyle)
+			start = next
+		}
+	}
+}
+
+// View renders the last lines, or lines scrolled to, and status line with
+// filter and level.
+func (m *Model) View(vb tea.Viewbox) {
+	vb = vb.MaxHeight(m.viewport.Height)
+	for y := range min(m.linesHeight(), vb.Height) {
+		if i := m.viewport.YOffset + y; i < len(m.shown) {
+			m.viewLine(vb.Row(y), m.lines.at(m.shown[i]))
+		}
+	}
+
+	if !m.statusShown() || vb.Height == 0 {
+		return
+	}
+
+	vbStatus := vb.Row(vb.Height - 1)
+	var status []string
+	if m.level != LevelUnknown {
+		status = append(status, "level ≥ "+m.level.String())
+	}
+	if !m.Follow {
+		status = append(status, "paused")
+	}
+	if m.filter.Err != nil {
+		status = []string{m.filter.Err.Error()}
+	}
+	statusWidth := uniseg.StringWidth(strings.Join(status, ", "))
+	if width := vbStatus.Width - statusWidth - 1; width > 0 && (m.filtering || m.filter.Value() != "") {
+		m.filter.View(vbStatus.MaxWidth(width))
+	}
+	vbStatus = vbStatus.PaddingLeft(max(vbStatus.Width-statusWidth, 0))
+	if m.filter.Err != nil {
+		vbStatus.Styled(m.Styles.Error).WriteLine(strings.Join(status, ", "))
+	} else {
+		vbStatus.Styled(m.Styles.Status).WriteLine(strings.Join(status, ", "))
+	}
+}
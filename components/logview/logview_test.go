@@ -0,0 +1,147 @@
+package logview
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rprtr258/assert"
+	"github.com/rprtr258/scuf"
+
+	"github.com/rprtr258/tea"
+)
+
+func TestParse(t *testing.T) {
+	for line, want := range map[string]struct {
+		level Level
+		time  time.Time
+	}{
+		`{"level":"warn","time":"2024-01-02T10:00:00Z","msg":"disk"}`: {LevelWarn, time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)},
+		`{"level":50,"time":1704189600000,"msg":"pino"}`:              {LevelError, time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)},
+		`ts=2024-01-02T10:00:00Z lvl=dbg msg="level=error inside"`:    {LevelDebug, time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)},
+		`2024-01-02 10:00:00 [ERROR] connection refused`:              {LevelError, time.Time{}},
+		`INFO server started`:           {LevelInfo, time.Time{}},
+		`this is an error, not a level`: {LevelUnknown, time.Time{}},
+	} {
+		level, tm := parseFields(line)
+		assert.Equal(t, want.level, level)
+		assert.True(t, want.time.Equal(tm))
+	}
+}
+
+func TestANSI(t *testing.T) {
+	plain, segments := parseANSI("\x1b[1;31mred\x1b[0m\tplain \x1b]8;;http://x\x1b\\link\x1b]8;;\x1b\\\x1b[38;5;200mpink\x1b[K")
+	assert.Equal(t, "red    plain linkpink", plain)
+	assert.Equal(t, 3, len(segments))
+
+	assert.Equal(t, "red", segments[0].text)
+	assert.True(t, segments[0].styled)
+	assert.True(t, segments[0].style.GetBold())
+	assert.Equal(t, scuf.FgANSI(1), segments[0].style.GetForeground())
+
+	assert.Equal(t, "    plain link", segments[1].text)
+	assert.False(t, segments[1].styled)
+
+	assert.Equal(t, "pink", segments[2].text)
+	assert.Equal(t, 17, segments[2].start)
+	assert.Equal(t, scuf.FgANSI(200), segments[2].style.GetForeground())
+}
+
+func TestView(t *testing.T) {
+	m := New(5, 3, 30)
+	view := func() []string {
+		vb := tea.NewViewbox(3, 30)
+		m.View(vb)
+		return vb.Lines()
+	}
+	press := func(k string) {
+		m.Update(tea.MsgKey{Type: tea.KeyRunes, Runes: []rune(k)}, func(...tea.Cmd) {})
+	}
+
+	var cmds []tea.Cmd
+	cmds = append(cmds, m.Read(strings.NewReader(strings.Join([]string{
+		"INFO starting",
+		"DEBUG config loaded",
+		"WARN disk is almost full",
+		"ERROR panic: oops",
+		"\tgoroutine 1",
+		"INFO \x1b[32mready\x1b[0m",
+	}, "\n"))))
+	for len(cmds) > 0 {
+		msg := cmds[0]()
+		cmds = cmds[1:]
+		m.Update(msg, func(c ...tea.Cmd) { cmds = append(cmds, c...) })
+	}
+	assert.NoError(t, m.Err)
+
+	t.Run("follow", func(t *testing.T) {
+		// the oldest line is dropped, the last ones are shown
+		assert.Equal(t, 5, m.Len())
+		assert.Equal(t, LevelError, m.Line(3).Level)
+		assert.Equal(t, []string{
+			"ERROR panic: oops",
+			"    goroutine 1",
+			"INFO ready",
+		}, view())
+
+		press("k")
+		assert.False(t, m.Follow)
+		m.Append("INFO new")
+		assert.Equal(t, []string{
+			"WARN disk is almost full",
+			"ERROR panic: oops",
+			"    goroutine 1",
+		}, view())
+
+		press("G")
+		assert.True(t, m.Follow)
+		vb := tea.NewViewbox(3, 30)
+		m.View(vb)
+		// colors of line are kept
+		assert.True(t, strings.Contains(string(vb.Render()), "\x1b[32mr"))
+		assert.Equal(t, []string{
+			"    goroutine 1",
+			"INFO ready",
+			"INFO new",
+		}, view())
+	})
+
+	t.Run("level", func(t *testing.T) {
+		press("l")
+		press("l")
+		press("l")
+		press("l")
+		assert.Equal(t, LevelWarn, m.Level())
+		assert.Equal(t, []string{
+			"ERROR panic: oops",
+			"    goroutine 1",
+			"                  level ≥ WARN",
+		}, view())
+		m.SetLevel(LevelUnknown)
+	})
+
+	t.Run("filter", func(t *testing.T) {
+		press("/")
+		assert.True(t, m.Filtering())
+		press("in")
+		assert.Equal(t, []string{
+			"    goroutine 1",
+			"",
+			"/ in",
+		}, view())
+
+		// invalid pattern is not applied
+		press("[")
+		assert.Equal(t, "in[", m.FilterValue())
+		assert.Equal(t, 1, m.Len())
+
+		m.Update(tea.MsgKey{Type: tea.KeyBackspace}, func(...tea.Cmd) {})
+		m.Update(tea.MsgKey{Type: tea.KeyEnter}, func(...tea.Cmd) {})
+		assert.False(t, m.Filtering())
+
+		vb := tea.NewViewbox(3, 30)
+		m.View(vb)
+		// match is highlighted over style of line level
+		assert.True(t, strings.Contains(string(vb.Render()), "\x1b[7;91mi\x1b[0m\x1b[7;91mn"))
+	})
+}
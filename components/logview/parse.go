@@ -0,0 +1,222 @@
+package logview
+
+import (
+	"encoding/json"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Level is severity of log line.
+type Level int
+
+const (
+	LevelUnknown Level = iota
+	LevelTrace
+	LevelDebug
+	LevelInfo
+	LevelWarn
+	LevelError
+	LevelFatal
+)
+
+var _levelNames = [...]string{
+	LevelUnknown: "",
+	LevelTrace:   "TRACE",
+	LevelDebug:   "DEBUG",
+	LevelInfo:    "INFO",
+	LevelWarn:    "WARN",
+	LevelError:   "ERROR",
+	LevelFatal:   "FATAL",
+}
+
+func (l Level) String() string {
+	if l < 0 || int(l) >= len(_levelNames) {
+		return ""
+	}
+	return _levelNames[l]
+}
+
+// ParseLevel parses level name case insensitively, e.g. "warn", "WARNING"
+// or "wrn". Returns LevelUnknown if s is not a level name.
+func ParseLevel(s string) Level {
+	switch strings.ToUpper(s) {
+	case "TRACE", "TRC", "T":
+		return LevelTrace
+	case "DEBUG", "DBG", "D":
+		return LevelDebug
+	case "INFO", "INF", "I", "NOTICE":
+		return LevelInfo
+	case "WARN", "WARNING", "WRN", "W":
+		return LevelWarn
+	case "ERROR", "ERR", "E":
+		return LevelError
+	case "FATAL", "FTL", "F", "PANIC", "CRITICAL", "CRIT":
+		return LevelFatal
+	default:
+		return LevelUnknown
+	}
+}
+
+var (
+	// _levelKeys are keys of level field in structured lines
+	_levelKeys = []string{"level", "lvl", "severity", "log.level"}
+	// _timeKeys are keys of time field in structured lines
+	_timeKeys = []string{"time", "ts", "timestamp", "@timestamp", "t"}
+	// _timeLayouts are layouts of time values tried in order
+	_timeLayouts = []string{
+		time.RFC3339Nano,
+		"2006-01-02 15:04:05.999999999Z07:00",
+		"2006-01-02T15:04:05.999999999",
+		"2006-01-02 15:04:05.999999999",
+	}
+)
+
+// parseTime parses time value, returns zero time if it is not a time.
+func parseTime(s string) time.Time {
+	for _, layout := range _timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// parseFields returns level and time of line, which are parsed from fields
+// of JSON object or logfmt pairs, or level is guessed from leading words of
+// unstructured line.
+func parseFields(line string) (Level, time.Time) {
+	if strings.HasPrefix(strings.TrimSpace(line), "{") {
+		if level, t, ok := parseJSON(line); ok {
+			return level, t
+		}
+	}
+	if level, t, ok := parseLogfmt(line); ok {
+		return level, t
+	}
+	return parseWords(line), time.Time{}
+}
+
+// parseJSON parses JSON object line, returns false if line is not one.
+func parseJSON(line string) (Level, time.Time, bool) {
+	var fields map[string]any
+	decoder := json.NewDecoder(strings.NewReader(line))
+	decoder.UseNumber()
+	if err := decoder.Decode(&fields); err != nil {
+		return LevelUnknown, time.Time{}, false
+	}
+
+	level := LevelUnknown
+	for _, key := range _levelKeys {
+		switch v := fields[key].(type) {
+		case string:
+			level = ParseLevel(v)
+		case json.Number:
+			// numeric levels of bunyan and pino: 10 is trace, 20 is debug
+			// and so on up to 60 which is fatal
+			if n, err := v.Int64(); err == nil && n >= 10 {
+				level = min(LevelTrace+Level(n/10-1), LevelFatal)
+			}
+		default:
+			continue
+		}
+		break
+	}
+
+	var t time.Time
+	for _, key := range _timeKeys {
+		switch v := fields[key].(type) {
+		case string:
+			t = parseTime(v)
+		case json.Number:
+			// unix time in seconds or milliseconds
+			if f, err := v.Float64(); err == nil {
+				if f > 1e12 {
+					f /= 1e3
+				}
+				t = time.Unix(0, int64(f*1e9))
+			}
+		default:
+			continue
+		}
+		break
+	}
+
+	return level, t, true
+}
+
+// parseLogfmt parses logfmt pairs, e.g. `level=info msg="hello world"`,
+// returns false if line has no level or time pair.
+func parseLogfmt(line string) (Level, time.Time, bool) {
+	var (
+		level Level
+		t     time.Time
+		ok    bool
+	)
+	for s := line; s != ""; {
+		s = strings.TrimLeft(s, " ")
+		i := strings.IndexAny(s, "= ")
+		if i == -1 {
+			break
+		}
+		if s[i] == ' ' {
+			// word without value
+			s = s[i:]
+			continue
+		}
+
+		key, value := s[:i], ""
+		s = s[i+1:]
+		if strings.HasPrefix(s, `"`) {
+			end := 1
+			for end < len(s) && s[end] != '"' {
+				if s[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			end = min(end+1, len(s))
+			var err error
+			if value, err = strconv.Unquote(s[:end]); err != nil {
+				value = strings.Trim(s[:end], `"`)
+			}
+			s = s[end:]
+		} else {
+			end := strings.IndexByte(s, ' ')
+			if end == -1 {
+				end = len(s)
+			}
+			value, s = s[:end], s[end:]
+		}
+
+		switch {
+		case slices.Contains(_levelKeys, key):
+			level, ok = ParseLevel(value), true
+		case slices.Contains(_timeKeys, key):
+			t, ok = parseTime(value), true
+		}
+	}
+	return level, t, ok
+}
+
+// _maxLevelWord is number of leading words of unstructured line, among
+// which level is looked for.
+const _maxLevelWord = 4
+
+// parseWords guesses level of unstructured line, e.g.
+// "2024-01-02 10:00:00 [WARN] disk is full", by leading upper case words.
+func parseWords(line string) Level {
+	words := strings.Fields(line)
+	for _, word := range words[:min(len(words), _maxLevelWord)] {
+		word = strings.Trim(word, "[]():|")
+		if len(word) < 3 || strings.ToUpper(word) != word {
+			continue
+		}
+
+		if level := ParseLevel(word); level != LevelUnknown {
+			return level
+		}
+	}
+	return LevelUnknown
+}
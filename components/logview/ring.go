@@ -0,0 +1,46 @@
+package logview
+
+// ring is a buffer of fixed capacity, which drops the oldest items when full.
+type ring[T any] struct {
+	items []T
+	// start is index of the oldest item
+	start int
+	// dropped is number of items dropped so far, item with sequence number
+	// seq is the (seq-dropped)-th buffered one, so that sequence numbers are
+	// stable while items are added
+	dropped int
+}
+
+// newRing returns empty buffer of given capacity, which must be positive.
+func newRing[T any](capacity int) ring[T] {
+	return ring[T]{items: make([]T, 0, capacity)}
+}
+
+// len returns number of buffered items.
+func (r *ring[T]) len() int {
+	return len(r.items)
+}
+
+// push adds item, dropping the oldest one if buffer is full. Returns whether
+// item is dropped.
+func (r *ring[T]) push(item T) bool {
+	if len(r.items) < cap(r.items) {
+		r.items = append(r.items, item)
+		return false
+	}
+
+	r.items[r.start] = item
+	r.start = (r.start + 1) % len(r.items)
+	r.dropped++
+	return true
+}
+
+// at returns item with given sequence number, which must be buffered.
+func (r *ring[T]) at(seq int) T {
+	return r.items[(r.start+seq-r.dropped)%len(r.items)]
+}
+
+// last returns sequence number of the last item, -1 if there is none.
+func (r *ring[T]) last() int {
+	return r.dropped + len(r.items) - 1
+}
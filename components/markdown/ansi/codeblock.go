@@ -9,6 +9,7 @@ import (
 	"github.com/alecthomas/chroma/v2/styles"
 	"github.com/muesli/reflow/indent"
 	"github.com/muesli/termenv"
+	"github.com/rprtr258/fun"
 )
 
 // chromaStyleTheme name used for rendering.
@@ -58,6 +59,132 @@ func chromaStyle(style StylePrimitive) string {
 	return s
 }
 
+// DarkChroma is chroma settings of code blocks in dark markdown style. It is
+// kept here, so that code is highlighted the same way outside of markdown
+// without depending on markdown renderer.
+var DarkChroma = &Chroma{
+	Text: StylePrimitive{
+		ForegroundColor: fun.Ptr("#C4C4C4"),
+	},
+	Error: StylePrimitive{
+		ForegroundColor: fun.Ptr("#F1F1F1"),
+		BackgroundColor: fun.Ptr("#F05B5B"),
+	},
+	Comment: StylePrimitive{
+		ForegroundColor: fun.Ptr("#676767"),
+	},
+	CommentPreproc: StylePrimitive{
+		ForegroundColor: fun.Ptr("#FF875F"),
+	},
+	Keyword: StylePrimitive{
+		ForegroundColor: fun.Ptr("#00AAFF"),
+	},
+	KeywordReserved: StylePrimitive{
+		ForegroundColor: fun.Ptr("#FF5FD2"),
+	},
+	KeywordNamespace: StylePrimitive{
+		ForegroundColor: fun.Ptr("#FF5F87"),
+	},
+	KeywordType: StylePrimitive{
+		ForegroundColor: fun.Ptr("#6E6ED8"),
+	},
+	Operator: StylePrimitive{
+		ForegroundColor: fun.Ptr("#EF8080"),
+	},
+	Punctuation: StylePrimitive{
+		ForegroundColor: fun.Ptr("#E8E8A8"),
+	},
+	Name: StylePrimitive{
+		ForegroundColor: fun.Ptr("#C4C4C4"),
+	},
+	NameBuiltin: StylePrimitive{
+		ForegroundColor: fun.Ptr("#FF8EC7"),
+	},
+	NameTag: StylePrimitive{
+		ForegroundColor: fun.Ptr("#B083EA"),
+	},
+	NameAttribute: StylePrimitive{
+		ForegroundColor: fun.Ptr("#7A7AE6"),
+	},
+	NameClass: StylePrimitive{
+		ForegroundColor: fun.Ptr("#F1F1F1"),
+		Underline:       fun.Ptr(true),
+		Bold:            fun.Ptr(true),
+	},
+	NameDecorator: StylePrimitive{
+		ForegroundColor: fun.Ptr("#FFFF87"),
+	},
+	NameFunction: StylePrimitive{
+		ForegroundColor: fun.Ptr("#00D787"),
+	},
+	LiteralNumber: StylePrimitive{
+		ForegroundColor: fun.Ptr("#6EEFC0"),
+	},
+	LiteralString: StylePrimitive{
+		ForegroundColor: fun.Ptr("#C69669"),
+	},
+	LiteralStringEscape: StylePrimitive{
+		ForegroundColor: fun.Ptr("#AFFFD7"),
+	},
+	GenericDeleted: StylePrimitive{
+		ForegroundColor: fun.Ptr("#FD5B5B"),
+	},
+	GenericEmph: StylePrimitive{
+		Italic: fun.Ptr(true),
+	},
+	GenericInserted: StylePrimitive{
+		ForegroundColor: fun.Ptr("#00D787"),
+	},
+	GenericStrong: StylePrimitive{
+		Bold: fun.Ptr(true),
+	},
+	GenericSubheading: StylePrimitive{
+		ForegroundColor: fun.Ptr("#777777"),
+	},
+	Background: StylePrimitive{
+		BackgroundColor: fun.Ptr("#373737"),
+	},
+}
+
+// ChromaStyle returns chroma style with given name made of chroma settings of
+// code blocks, so that code can be highlighted the same way outside of
+// markdown.
+func ChromaStyle(name string, c *Chroma) *chroma.Style {
+	return chroma.MustNewStyle(name, chroma.StyleEntries{
+		chroma.Text:                chromaStyle(c.Text),
+		chroma.Error:               chromaStyle(c.Error),
+		chroma.Comment:             chromaStyle(c.Comment),
+		chroma.CommentPreproc:      chromaStyle(c.CommentPreproc),
+		chroma.Keyword:             chromaStyle(c.Keyword),
+		chroma.KeywordReserved:     chromaStyle(c.KeywordReserved),
+		chroma.KeywordNamespace:    chromaStyle(c.KeywordNamespace),
+		chroma.KeywordType:         chromaStyle(c.KeywordType),
+		chroma.Operator:            chromaStyle(c.Operator),
+		chroma.Punctuation:         chromaStyle(c.Punctuation),
+		chroma.Name:                chromaStyle(c.Name),
+		chroma.NameBuiltin:         chromaStyle(c.NameBuiltin),
+		chroma.NameTag:             chromaStyle(c.NameTag),
+		chroma.NameAttribute:       chromaStyle(c.NameAttribute),
+		chroma.NameClass:           chromaStyle(c.NameClass),
+		chroma.NameConstant:        chromaStyle(c.NameConstant),
+		chroma.NameDecorator:       chromaStyle(c.NameDecorator),
+		chroma.NameException:       chromaStyle(c.NameException),
+		chroma.NameFunction:        chromaStyle(c.NameFunction),
+		chroma.NameOther:           chromaStyle(c.NameOther),
+		chroma.Literal:             chromaStyle(c.Literal),
+		chroma.LiteralNumber:       chromaStyle(c.LiteralNumber),
+		chroma.LiteralDate:         chromaStyle(c.LiteralDate),
+		chroma.LiteralString:       chromaStyle(c.LiteralString),
+		chroma.LiteralStringEscape: chromaStyle(c.LiteralStringEscape),
+		chroma.GenericDeleted:      chromaStyle(c.GenericDeleted),
+		chroma.GenericEmph:         chromaStyle(c.GenericEmph),
+		chroma.GenericInserted:     chromaStyle(c.GenericInserted),
+		chroma.GenericStrong:       chromaStyle(c.GenericStrong),
+		chroma.GenericSubheading:   chromaStyle(c.GenericSubheading),
+		chroma.Background:          chromaStyle(c.Background),
+	})
+}
+
 func (e *CodeBlockElement) Render(w io.Writer, ctx RenderContext) error {
 	bs := ctx.blockStack
 
@@ -81,39 +208,7 @@ func (e *CodeBlockElement) Render(w io.Writer, ctx RenderContext) error {
 		// Don't register the style if it's already registered.
 		_, ok := styles.Registry[theme]
 		if !ok {
-			styles.Register(chroma.MustNewStyle(theme, chroma.StyleEntries{
-				chroma.Text:                chromaStyle(rules.Chroma.Text),
-				chroma.Error:               chromaStyle(rules.Chroma.Error),
-				chroma.Comment:             chromaStyle(rules.Chroma.Comment),
-				chroma.CommentPreproc:      chromaStyle(rules.Chroma.CommentPreproc),
-				chroma.Keyword:             chromaStyle(rules.Chroma.Keyword),
-				chroma.KeywordReserved:     chromaStyle(rules.Chroma.KeywordReserved),
-				chroma.KeywordNamespace:    chromaStyle(rules.Chroma.KeywordNamespace),
-				chroma.KeywordType:         chromaStyle(rules.Chroma.KeywordType),
-				chroma.Operator:            chromaStyle(rules.Chroma.Operator),
-				chroma.Punctuation:         chromaStyle(rules.Chroma.Punctuation),
-				chroma.Name:                chromaStyle(rules.Chroma.Name),
-				chroma.NameBuiltin:         chromaStyle(rules.Chroma.NameBuiltin),
-				chroma.NameTag:             chromaStyle(rules.Chroma.NameTag),
-				chroma.NameAttribute:       chromaStyle(rules.Chroma.NameAttribute),
-				chroma.NameClass:           chromaStyle(rules.Chroma.NameClass),
-				chroma.NameConstant:        chromaStyle(rules.Chroma.NameConstant),
-				chroma.NameDecorator:       chromaStyle(rules.Chroma.NameDecorator),
-				chroma.NameException:       chromaStyle(rules.Chroma.NameException),
-				chroma.NameFunction:        chromaStyle(rules.Chroma.NameFunction),
-				chroma.NameOther:           chromaStyle(rules.Chroma.NameOther),
-				chroma.Literal:             chromaStyle(rules.Chroma.Literal),
-				chroma.LiteralNumber:       chromaStyle(rules.Chroma.LiteralNumber),
-				chroma.LiteralDate:         chromaStyle(rules.Chroma.LiteralDate),
-				chroma.LiteralString:       chromaStyle(rules.Chroma.LiteralString),
-				chroma.LiteralStringEscape: chromaStyle(rules.Chroma.LiteralStringEscape),
-				chroma.GenericDeleted:      chromaStyle(rules.Chroma.GenericDeleted),
-				chroma.GenericEmph:         chromaStyle(rules.Chroma.GenericEmph),
-				chroma.GenericInserted:     chromaStyle(rules.Chroma.GenericInserted),
-				chroma.GenericStrong:       chromaStyle(rules.Chroma.GenericStrong),
-				chroma.GenericSubheading:   chromaStyle(rules.Chroma.GenericSubheading),
-				chroma.Background:          chromaStyle(rules.Chroma.Background),
-			}))
+			styles.Register(ChromaStyle(theme, rules.Chroma))
 		}
 		mutex.Unlock()
 	}
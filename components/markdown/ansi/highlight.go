@@ -0,0 +1,36 @@
+package ansi
+
+import (
+	"github.com/alecthomas/chroma/v2"
+
+	"github.com/rprtr258/tea/styles"
+)
+
+// HighlightStyles converts chroma style, e.g. one made by ChromaStyle or
+// taken from chroma styles registry, into styles of token types, so that
+// highlighters outside of markdown can color code the same way.
+func HighlightStyles(style *chroma.Style) map[chroma.TokenType]styles.Style {
+	res := map[chroma.TokenType]styles.Style{}
+	for _, t := range style.Types() {
+		entry := style.Get(t)
+
+		var st styles.Style
+		if entry.Colour.IsSet() {
+			st = st.Foreground(styles.FgColor(entry.Colour.String()))
+		}
+		if entry.Background.IsSet() && t != chroma.Background {
+			st = st.Background(styles.BgColor(entry.Background.String()))
+		}
+		if entry.Bold == chroma.Yes {
+			st = st.Bold(true)
+		}
+		if entry.Italic == chroma.Yes {
+			st = st.Italic()
+		}
+		if entry.Underline == chroma.Yes {
+			st = st.Underline()
+		}
+		res[t] = st
+	}
+	return res
+}
@@ -232,89 +232,7 @@ var (
 				},
 				Margin: Ptr[uint](2),
 			},
-			Chroma: &ansi.Chroma{
-				Text: ansi.StylePrimitive{
-					ForegroundColor: Ptr("#C4C4C4"),
-				},
-				Error: ansi.StylePrimitive{
-					ForegroundColor: Ptr("#F1F1F1"),
-					BackgroundColor: Ptr("#F05B5B"),
-				},
-				Comment: ansi.StylePrimitive{
-					ForegroundColor: Ptr("#676767"),
-				},
-				CommentPreproc: ansi.StylePrimitive{
-					ForegroundColor: Ptr("#FF875F"),
-				},
-				Keyword: ansi.StylePrimitive{
-					ForegroundColor: Ptr("#00AAFF"),
-				},
-				KeywordReserved: ansi.StylePrimitive{
-					ForegroundColor: Ptr("#FF5FD2"),
-				},
-				KeywordNamespace: ansi.StylePrimitive{
-					ForegroundColor: Ptr("#FF5F87"),
-				},
-				KeywordType: ansi.StylePrimitive{
-					ForegroundColor: Ptr("#6E6ED8"),
-				},
-				Operator: ansi.StylePrimitive{
-					ForegroundColor: Ptr("#EF8080"),
-				},
-				Punctuation: ansi.StylePrimitive{
-					ForegroundColor: Ptr("#E8E8A8"),
-				},
-				Name: ansi.StylePrimitive{
-					ForegroundColor: Ptr("#C4C4C4"),
-				},
-				NameBuiltin: ansi.StylePrimitive{
-					ForegroundColor: Ptr("#FF8EC7"),
-				},
-				NameTag: ansi.StylePrimitive{
-					ForegroundColor: Ptr("#B083EA"),
-				},
-				NameAttribute: ansi.StylePrimitive{
-					ForegroundColor: Ptr("#7A7AE6"),
-				},
-				NameClass: ansi.StylePrimitive{
-					ForegroundColor: Ptr("#F1F1F1"),
-					Underline:       Ptr(true),
-					Bold:            Ptr(true),
-				},
-				NameDecorator: ansi.StylePrimitive{
-					ForegroundColor: Ptr("#FFFF87"),
-				},
-				NameFunction: ansi.StylePrimitive{
-					ForegroundColor: Ptr("#00D787"),
-				},
-				LiteralNumber: ansi.StylePrimitive{
-					ForegroundColor: Ptr("#6EEFC0"),
-				},
-				LiteralString: ansi.StylePrimitive{
-					ForegroundColor: Ptr("#C69669"),
-				},
-				LiteralStringEscape: ansi.StylePrimitive{
-					ForegroundColor: Ptr("#AFFFD7"),
-				},
-				GenericDeleted: ansi.StylePrimitive{
-					ForegroundColor: Ptr("#FD5B5B"),
-				},
-				GenericEmph: ansi.StylePrimitive{
-					Italic: Ptr(true),
-				},
-				GenericInserted: ansi.StylePrimitive{
-					ForegroundColor: Ptr("#00D787"),
-				},
-				GenericStrong: ansi.StylePrimitive{
-					Bold: Ptr(true),
-				},
-				GenericSubheading: ansi.StylePrimitive{
-					ForegroundColor: Ptr("#777777"),
-				},
-				Background: ansi.StylePrimitive{
-					BackgroundColor: Ptr("#373737"),
-				},
-			},
+			Chroma: ansi.DarkChroma,
 		},
 		Table: ansi.StyleTable{
 			StyleBlock: ansi.StyleBlock{
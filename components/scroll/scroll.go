@@ -0,0 +1,256 @@
+// Package scroll provides scroll container, which renders content of
+// arbitrary size into smaller viewbox, showing only visible part of it.
+package scroll
+
+import (
+	"github.com/rprtr258/fun"
+
+	"github.com/rprtr258/tea"
+	"github.com/rprtr258/tea/components/key"
+	"github.com/rprtr258/tea/styles"
+)
+
+// KeyMap defines keybindings for scrolling.
+type KeyMap struct {
+	Up, Down, Left, Right key.Binding
+	PageUp, PageDown      key.Binding
+	Top, Bottom           key.Binding
+}
+
+// DefaultKeyMap returns a default set of keybindings.
+var DefaultKeyMap = KeyMap{
+	Up: key.Binding{
+		Keys: []string{"up", "k"},
+		Help: key.Help{"↑/k", "up"},
+	},
+	Down: key.Binding{
+		Keys: []string{"down", "j"},
+		Help: key.Help{"↓/j", "down"},
+	},
+	Left: key.Binding{
+		Keys: []string{"left", "h"},
+		Help: key.Help{"←/h", "left"},
+	},
+	Right: key.Binding{
+		Keys: []string{"right", "l"},
+		Help: key.Help{"→/l", "right"},
+	},
+	PageUp: key.Binding{
+		Keys: []string{"pgup", "b"},
+		Help: key.Help{"b/pgup", "page up"},
+	},
+	PageDown: key.Binding{
+		Keys: []string{"pgdown", "f"},
+		Help: key.Help{"f/pgdn", "page down"},
+	},
+	Top: key.Binding{
+		Keys: []string{"home", "g"},
+		Help: key.Help{"g/home", "go to start"},
+	},
+	Bottom: key.Binding{
+		Keys: []string{"end", "G"},
+		Help: key.Help{"G/end", "go to end"},
+	},
+}
+
+// Styles contains style definitions for scrollbars.
+type Styles struct {
+	Track, Thumb styles.Style
+}
+
+// DefaultStyles returns default scrollbars styles.
+var DefaultStyles = Styles{
+	Track: styles.Style{}.Foreground(styles.FgColor("240")),
+	Thumb: styles.Style{}.Foreground(styles.FgColor("250")),
+}
+
+// Model is scroll container state.
+type Model struct {
+	// ContentHeight and ContentWidth are size of scrolled content.
+	ContentHeight, ContentWidth int
+	// YOffset and XOffset are scroll position, i.e. position of content cell
+	// shown in top left corner.
+	YOffset, XOffset int
+
+	KeyMap KeyMap
+	Styles Styles
+
+	// Whether or not to show scrollbars when content does not fit.
+	ScrollbarY, ScrollbarX bool
+
+	// Whether or not to respond to the mouse.
+	// The mouse must be enabled in Tea for this to work.
+	// Wheel scrolls vertically, wheel with alt pressed scrolls horizontally.
+	MouseWheelEnabled bool
+
+	// The number of lines the mouse wheel will scroll.
+	// By default, this is 3.
+	MouseWheelDelta int
+
+	// size of visible area during last render
+	height, width int
+}
+
+// New creates scroll container for content of given size.
+func New(contentHeight, contentWidth int) Model {
+	return Model{
+		ContentHeight:     contentHeight,
+		ContentWidth:      contentWidth,
+		KeyMap:            DefaultKeyMap,
+		Styles:            DefaultStyles,
+		ScrollbarY:        true,
+		ScrollbarX:        true,
+		MouseWheelEnabled: true,
+		MouseWheelDelta:   3,
+	}
+}
+
+// SetContentSize sets size of scrolled content.
+func (m *Model) SetContentSize(height, width int) {
+	m.ContentHeight, m.ContentWidth = height, width
+	m.clamp()
+}
+
+// Height returns visible area height, known after first render.
+func (m *Model) Height() int { return m.height }
+
+// Width returns visible area width, known after first render.
+func (m *Model) Width() int { return m.width }
+
+func (m *Model) clamp() {
+	m.YOffset = max(0, min(m.YOffset, m.ContentHeight-m.height))
+	m.XOffset = max(0, min(m.XOffset, m.ContentWidth-m.width))
+}
+
+// SetYOffset sets the Y offset.
+func (m *Model) SetYOffset(n int) {
+	m.YOffset = n
+	m.clamp()
+}
+
+// SetXOffset sets the X offset.
+func (m *Model) SetXOffset(n int) {
+	m.XOffset = n
+	m.clamp()
+}
+
+func (m *Model) ScrollUp(n int)    { m.SetYOffset(m.YOffset - n) } // ScrollUp moves the view up by the given number of lines
+func (m *Model) ScrollDown(n int)  { m.SetYOffset(m.YOffset + n) } // ScrollDown moves the view down by the given number of lines
+func (m *Model) ScrollLeft(n int)  { m.SetXOffset(m.XOffset - n) } // ScrollLeft moves the view left by the given number of columns
+func (m *Model) ScrollRight(n int) { m.SetXOffset(m.XOffset + n) } // ScrollRight moves the view right by the given number of columns
+
+func (m *Model) GotoTop()    { m.SetYOffset(0) }               // GotoTop scrolls to the top of content
+func (m *Model) GotoBottom() { m.SetYOffset(m.ContentHeight) } // GotoBottom scrolls to the bottom of content
+
+// AtTop returns whether or not the view is at the very top position.
+func (m *Model) AtTop() bool {
+	return m.YOffset <= 0
+}
+
+// AtBottom returns whether or not the view is at the very bottom position.
+func (m *Model) AtBottom() bool {
+	return m.YOffset >= m.ContentHeight-m.height
+}
+
+// ScrollIntoView scrolls minimally so that given region of content is
+// visible. If region is bigger than visible area, its top left corner is
+// shown.
+func (m *Model) ScrollIntoView(rect tea.Rectangle) {
+	scrollIntoView := func(offset, visible, start, size int) int {
+		if start+size > offset+visible {
+			offset = start + size - visible
+		}
+		return min(offset, start)
+	}
+
+	m.YOffset = scrollIntoView(m.YOffset, m.height, rect.Top, rect.Height)
+	m.XOffset = scrollIntoView(m.XOffset, m.width, rect.Left, rect.Width)
+	m.clamp()
+}
+
+// Update handles keyboard and mouse wheel scrolling.
+func (m *Model) Update(msg tea.Msg) {
+	switch msg := msg.(type) {
+	case tea.MsgKey:
+		switch {
+		case key.Matches(msg, m.KeyMap.Up):
+			m.ScrollUp(1)
+		case key.Matches(msg, m.KeyMap.Down):
+			m.ScrollDown(1)
+		case key.Matches(msg, m.KeyMap.Left):
+			m.ScrollLeft(1)
+		case key.Matches(msg, m.KeyMap.Right):
+			m.ScrollRight(1)
+		case key.Matches(msg, m.KeyMap.PageUp):
+			m.ScrollUp(m.height)
+		case key.Matches(msg, m.KeyMap.PageDown):
+			m.ScrollDown(m.height)
+		case key.Matches(msg, m.KeyMap.Top):
+			m.GotoTop()
+		case key.Matches(msg, m.KeyMap.Bottom):
+			m.GotoBottom()
+		}
+	case tea.MsgMouse:
+		if !m.MouseWheelEnabled {
+			break
+		}
+
+		switch {
+		case msg.Type == tea.MouseWheelUp && msg.Alt:
+			m.ScrollLeft(m.MouseWheelDelta)
+		case msg.Type == tea.MouseWheelDown && msg.Alt:
+			m.ScrollRight(m.MouseWheelDelta)
+		case msg.Type == tea.MouseWheelUp:
+			m.ScrollUp(m.MouseWheelDelta)
+		case msg.Type == tea.MouseWheelDown:
+			m.ScrollDown(m.MouseWheelDelta)
+		}
+	}
+}
+
+// thumb returns position and size of scrollbar thumb on track of given size
+func thumb(track, visible, content, offset int) (int, int) {
+	size := max(1, track*visible/content)
+	if content == visible {
+		return 0, size
+	}
+	return (track - size) * offset / (content - visible), size
+}
+
+// View renders content into viewbox. Content is given viewbox of content
+// size scrolled according to offsets, writes to invisible part are
+// discarded.
+func (m *Model) View(vb tea.Viewbox, content func(tea.Viewbox)) {
+	showY := m.ScrollbarY && m.ContentHeight > vb.Height
+	showX := m.ScrollbarX && m.ContentWidth > vb.Width-fun.IF(showY, 1, 0)
+	// horizontal scrollbar might make content not fit vertically
+	showY = showY || m.ScrollbarY && showX && m.ContentHeight > vb.Height-1
+
+	vbContent := vb.Padding(tea.PaddingOptions{
+		Right:  fun.IF(showY, 1, 0),
+		Bottom: fun.IF(showX, 1, 0),
+	})
+
+	m.height, m.width = vbContent.Height, vbContent.Width
+	m.clamp()
+
+	content(vbContent.Virtual(
+		max(m.ContentHeight, m.height),
+		max(m.ContentWidth, m.width),
+		m.YOffset,
+		m.XOffset,
+	))
+
+	if showY {
+		vbTrack := vb.Sub(tea.Rectangle{Left: vb.Width - 1, Height: m.height, Width: 1})
+		vbTrack.Styled(m.Styles.Track).Fill('│')
+		pos, size := thumb(m.height, m.height, m.ContentHeight, m.YOffset)
+		vbTrack.Sub(tea.Rectangle{Top: pos, Height: size, Width: 1}).Styled(m.Styles.Thumb).Fill('┃')
+	}
+	if showX {
+		vbTrack := vb.Sub(tea.Rectangle{Top: vb.Height - 1, Height: 1, Width: m.width})
+		vbTrack.Styled(m.Styles.Track).Fill('─')
+		pos, size := thumb(m.width, m.width, m.ContentWidth, m.XOffset)
+		vbTrack.Sub(tea.Rectangle{Left: pos, Height: 1, Width: size}).Styled(m.Styles.Thumb).Fill('━')
+	}
+}
@@ -0,0 +1,69 @@
+package scroll
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rprtr258/assert"
+
+	"github.com/rprtr258/tea"
+)
+
+func content(vb tea.Viewbox) {
+	for y := 0; y < vb.Height; y++ {
+		vb.Row(y).WriteLine(strings.Repeat(string(rune('a'+y)), y+1))
+	}
+}
+
+func render(m *Model, height, width int) string {
+	vb := tea.NewViewbox(height, width)
+	m.View(vb, content)
+	return string(vb.Render())
+}
+
+func TestView(t *testing.T) {
+	m := New(6, 6)
+	m.Styles = Styles{}
+
+	assert.Equal(t, strings.Join([]string{
+		"a  ┃",
+		"bb │",
+		"ccc│",
+		"━── ",
+	}, "\n"), render(&m, 4, 4))
+
+	m.ScrollDown(2)
+	m.ScrollRight(1)
+	assert.Equal(t, strings.Join([]string{
+		"cc │",
+		"ddd┃",
+		"eee│",
+		"━── ",
+	}, "\n"), render(&m, 4, 4))
+
+	m.GotoBottom()
+	m.ScrollRight(10)
+	assert.Equal(t, strings.Join([]string{
+		"d  │",
+		"ee │",
+		"fff┃",
+		"──━ ",
+	}, "\n"), render(&m, 4, 4))
+}
+
+func TestScrollIntoView(t *testing.T) {
+	m := New(100, 100)
+	render(&m, 10, 10)
+
+	m.ScrollIntoView(tea.Rectangle{Top: 20, Left: 5, Height: 2, Width: 2})
+	assert.Equal(t, 13, m.YOffset)
+	assert.Equal(t, 0, m.XOffset)
+
+	m.ScrollIntoView(tea.Rectangle{Top: 15, Left: 50, Height: 1, Width: 3})
+	assert.Equal(t, 13, m.YOffset)
+	assert.Equal(t, 44, m.XOffset)
+
+	m.ScrollIntoView(tea.Rectangle{Top: 5, Left: 0, Height: 20, Width: 1})
+	assert.Equal(t, 5, m.YOffset)
+	assert.Equal(t, 0, m.XOffset)
+}
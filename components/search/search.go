@@ -0,0 +1,482 @@
+// Package search provides search bar, which finds text in lines of
+// components content, e.g. textarea or viewport, and optionally replaces it.
+// Search bar does not own the text, instead its Update reports actions the
+// owner must perform, like finding matches again or replacing current match.
+package search
+
+import (
+	"fmt"
+	"iter"
+	"regexp"
+	"sort"
+	"unicode/utf8"
+
+	rw "github.com/mattn/go-runewidth"
+
+	"github.com/rprtr258/tea"
+	"github.com/rprtr258/tea/components/key"
+	"github.com/rprtr258/tea/components/textinput"
+	"github.com/rprtr258/tea/styles"
+)
+
+// KeyMap defines keybindings of search bar.
+type KeyMap struct {
+	Next, Previous   key.Binding
+	ToggleRegexp     key.Binding
+	ToggleIgnoreCase key.Binding
+	// SwitchField moves focus between query and replacement inputs.
+	SwitchField key.Binding
+	// ReplaceOne replaces current match, works in replacement input only.
+	ReplaceOne key.Binding
+	ReplaceAll key.Binding
+	Close      key.Binding
+}
+
+// DefaultKeyMap is the default set of search bar keybindings.
+var DefaultKeyMap = KeyMap{
+	Next: key.Binding{
+		Keys: []string{"enter", "ctrl+n"},
+		Help: key.Help{"enter", "next match"},
+	},
+	Previous: key.Binding{
+		Keys: []string{"ctrl+p"},
+		Help: key.Help{"ctrl+p", "previous match"},
+	},
+	ToggleRegexp: key.Binding{
+		Keys: []string{"alt+r"},
+		Help: key.Help{"alt+r", "toggle regexp"},
+	},
+	ToggleIgnoreCase: key.Binding{
+		Keys: []string{"alt+c"},
+		Help: key.Help{"alt+c", "toggle ignore case"},
+	},
+	SwitchField: key.Binding{
+		Keys: []string{"tab"},
+		Help: key.Help{"tab", "switch field"},
+	},
+	ReplaceOne: key.Binding{
+		Keys: []string{"enter"},
+		Help: key.Help{"enter", "replace"},
+	},
+	ReplaceAll: key.Binding{
+		Keys: []string{"alt+enter"},
+		Help: key.Help{"alt+enter", "replace all"},
+	},
+	Close: key.Binding{
+		Keys: []string{"esc"},
+		Help: key.Help{"esc", "close"},
+	},
+}
+
+// Styles contains style definitions for search bar and matches.
+type Styles struct {
+	Match, CurrentMatch styles.Style
+	Counter, Error      styles.Style
+	// FlagOn and FlagOff are styles of regexp and ignore case indicators.
+	FlagOn, FlagOff styles.Style
+}
+
+// DefaultStyles returns default search styles.
+var DefaultStyles = Styles{
+	Match:        styles.Style{}.Background(styles.BgAdaptiveColor("229", "58")),
+	CurrentMatch: styles.Style{}.Background(styles.BgAdaptiveColor("214", "130")),
+	Counter:      styles.Style{}.Foreground(styles.FgColor("240")),
+	Error:        styles.Style{}.Foreground(styles.FgColor("9")),
+	FlagOn:       styles.Style{}.Foreground(styles.FgColor("12")).Bold(true),
+	FlagOff:      styles.Style{}.Foreground(styles.FgColor("240")),
+}
+
+// Action is what owner of search bar must do after Update.
+type Action int
+
+const (
+	// ActionNone requires nothing.
+	ActionNone Action = iota
+	// ActionFind requires finding matches again using Find, since query
+	// changed.
+	ActionFind
+	// ActionMove requires scrolling to current match.
+	ActionMove
+	// ActionReplace requires replacing current match using Replace.
+	ActionReplace
+	// ActionReplaceAll requires replacing all matches using ReplaceAll.
+	ActionReplaceAll
+	// ActionClose tells search bar is closed.
+	ActionClose
+)
+
+// Match is an occurrence of query in text.
+type Match struct {
+	Line int
+	// Start and End are rune offsets of match in line, End is exclusive.
+	Start, End int
+	// X and Width are display column and width of match.
+	X, Width int
+}
+
+// Model is search bar state along with matches found.
+type Model struct {
+	KeyMap KeyMap
+	Styles Styles
+
+	// Regexp makes query to be regular expression instead of literal text.
+	Regexp bool
+	// IgnoreCase makes search case insensitive.
+	IgnoreCase bool
+
+	query, replacement textinput.Model
+	// replacing is set if replacement input is shown
+	replacing bool
+	active    bool
+
+	re  *regexp.Regexp
+	err error
+
+	// matches sorted by position, current is index of current match
+	matches []Match
+	current int
+}
+
+// New creates inactive search bar.
+func New() Model {
+	query := textinput.New()
+	query.Prompt = "Find: "
+	replacement := textinput.New()
+	replacement.Prompt = "Replace: "
+
+	return Model{
+		KeyMap:      DefaultKeyMap,
+		Styles:      DefaultStyles,
+		query:       query,
+		replacement: replacement,
+	}
+}
+
+// Active returns whether search bar is open.
+func (m *Model) Active() bool {
+	return m.active
+}
+
+// Open search bar, replacement input is shown if replace is set.
+func (m *Model) Open(replace bool) []tea.Cmd {
+	m.active = true
+	m.replacing = replace
+	m.replacement.Blur()
+	return m.query.Focus()
+}
+
+// Close search bar. Matches and current match are kept, so that owner can
+// still jump between them, until query changes.
+func (m *Model) Close() {
+	m.active = false
+	m.query.Blur()
+	m.replacement.Blur()
+}
+
+// Height returns number of rows taken by search bar.
+func (m *Model) Height() int {
+	switch {
+	case !m.active:
+		return 0
+	case m.replacing:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// Query returns text searched for.
+func (m *Model) Query() string {
+	return m.query.Value()
+}
+
+// SetQuery sets text searched for. Find must be called afterwards.
+func (m *Model) SetQuery(query string) {
+	m.query.SetValue(query)
+	m.compile()
+}
+
+// Err returns error of query compilation, e.g. invalid regexp.
+func (m *Model) Err() error {
+	return m.err
+}
+
+func (m *Model) compile() {
+	m.re, m.err = nil, nil
+	query := m.query.Value()
+	if query == "" {
+		return
+	}
+
+	if !m.Regexp {
+		query = regexp.QuoteMeta(query)
+	}
+	if m.IgnoreCase {
+		query = "(?i)" + query
+	}
+	m.re, m.err = regexp.Compile(query)
+}
+
+// indices of non-empty matches of query in line, along with submatches
+func (m *Model) indices(line string) [][]int {
+	if m.re == nil {
+		return nil
+	}
+
+	var res [][]int
+	for _, idx := range m.re.FindAllStringSubmatchIndex(line, -1) {
+		if idx[0] < idx[1] {
+			res = append(res, idx)
+		}
+	}
+	return res
+}
+
+// Find all matches in given lines, which must be enumerated in order.
+// Current match is reset to the first one.
+func (m *Model) Find(lines iter.Seq2[int, string]) {
+	m.matches, m.current = m.matches[:0], 0
+	if m.re == nil {
+		return
+	}
+
+	for i, line := range lines {
+		// lines without matches are skipped fast
+		if !m.re.MatchString(line) {
+			continue
+		}
+
+		// byte offset, rune offset and column of last match start
+		start, runes, x := 0, 0, 0
+		for _, idx := range m.indices(line) {
+			runes += utf8.RuneCountInString(line[start:idx[0]])
+			x += rw.StringWidth(line[start:idx[0]])
+			m.matches = append(m.matches, Match{
+				Line:  i,
+				Start: runes,
+				End:   runes + utf8.RuneCountInString(line[idx[0]:idx[1]]),
+				X:     x,
+				Width: rw.StringWidth(line[idx[0]:idx[1]]),
+			})
+			start = idx[0]
+		}
+	}
+}
+
+// Matches returns all matches found.
+func (m *Model) Matches() []Match {
+	return m.matches
+}
+
+// MatchesIn returns matches in given line.
+func (m *Model) MatchesIn(line int) []Match {
+	i := sort.Search(len(m.matches), func(i int) bool { return m.matches[i].Line >= line })
+	j := sort.Search(len(m.matches), func(i int) bool { return m.matches[i].Line > line })
+	return m.matches[i:j]
+}
+
+// Current returns current match, if any.
+func (m *Model) Current() (Match, bool) {
+	if len(m.matches) == 0 {
+		return Match{}, false
+	}
+	return m.matches[m.current], true
+}
+
+// IsCurrent checks whether given match is current one.
+func (m *Model) IsCurrent(match Match) bool {
+	current, ok := m.Current()
+	return ok && current == match
+}
+
+// Nearest makes first match starting at or after given position current,
+// wrapping to the first match if there are none.
+func (m *Model) Nearest(line, col int) {
+	m.current = sort.Search(len(m.matches), func(i int) bool {
+		match := m.matches[i]
+		return match.Line > line || match.Line == line && match.Start >= col
+	})
+	if m.current == len(m.matches) {
+		m.current = 0
+	}
+}
+
+// Next makes next match current, wrapping around.
+func (m *Model) Next() {
+	if len(m.matches) > 0 {
+		m.current = (m.current + 1) % len(m.matches)
+	}
+}
+
+// Previous makes previous match current, wrapping around.
+func (m *Model) Previous() {
+	if len(m.matches) > 0 {
+		m.current = (m.current - 1 + len(m.matches)) % len(m.matches)
+	}
+}
+
+// expand replacement for match at given byte offset of line
+func (m *Model) expand(line string, idx []int) string {
+	if !m.Regexp {
+		return m.replacement.Value()
+	}
+	return string(m.re.ExpandString(nil, m.replacement.Value(), line, idx))
+}
+
+// Replace returns line with given match replaced. In regexp mode
+// replacement might refer to submatches, e.g. $1.
+func (m *Model) Replace(line string, match Match) string {
+	start, runes := 0, 0
+	for _, idx := range m.indices(line) {
+		runes += utf8.RuneCountInString(line[start:idx[0]])
+		start = idx[0]
+		if runes == match.Start {
+			return line[:idx[0]] + m.expand(line, idx) + line[idx[1]:]
+		}
+	}
+	return line
+}
+
+// ReplaceAll returns line with all matches replaced.
+func (m *Model) ReplaceAll(line string) string {
+	var res []byte
+	last := 0
+	for _, idx := range m.indices(line) {
+		res = append(res, line[last:idx[0]]...)
+		res = append(res, m.expand(line, idx)...)
+		last = idx[1]
+	}
+	return string(append(res, line[last:]...))
+}
+
+// Update handles keys while search bar is open.
+func (m *Model) Update(msg tea.Msg, f func(...tea.Cmd)) Action {
+	if !m.active {
+		return ActionNone
+	}
+
+	msgKey, ok := msg.(tea.MsgKey)
+	if !ok {
+		return ActionNone
+	}
+
+	inReplacement := m.replacing && m.replacement.Focused()
+	switch {
+	case key.Matches(msgKey, m.KeyMap.Close):
+		m.Close()
+		return ActionClose
+	case key.Matches(msgKey, m.KeyMap.ReplaceAll) && m.replacing:
+		return ActionReplaceAll
+	case key.Matches(msgKey, m.KeyMap.ReplaceOne) && inReplacement:
+		return ActionReplace
+	case key.Matches(msgKey, m.KeyMap.Next):
+		m.Next()
+		return ActionMove
+	case key.Matches(msgKey, m.KeyMap.Previous):
+		m.Previous()
+		return ActionMove
+	case key.Matches(msgKey, m.KeyMap.ToggleRegexp):
+		m.Regexp = !m.Regexp
+		m.compile()
+		return ActionFind
+	case key.Matches(msgKey, m.KeyMap.ToggleIgnoreCase):
+		m.IgnoreCase = !m.IgnoreCase
+		m.compile()
+		return ActionFind
+	case key.Matches(msgKey, m.KeyMap.SwitchField) && m.replacing:
+		if inReplacement {
+			m.replacement.Blur()
+			f(m.query.Focus()...)
+		} else {
+			m.query.Blur()
+			f(m.replacement.Focus()...)
+		}
+		return ActionNone
+	case inReplacement:
+		m.replacement.Update(msg, f)
+		return ActionNone
+	default:
+		query := m.query.Value()
+		m.query.Update(msg, f)
+		if m.query.Value() == query {
+			return ActionNone
+		}
+
+		m.compile()
+		return ActionFind
+	}
+}
+
+// Highlight matches of given line in row of viewbox, where line text was
+// written already.
+func (m *Model) Highlight(vb tea.Viewbox, line int) {
+	for _, match := range m.MatchesIn(line) {
+		if match.X >= vb.Width {
+			break
+		}
+
+		vb.
+			PaddingLeft(match.X).
+			MaxHeight(1).
+			MaxWidth(match.Width).
+			Styled(m.MatchStyle(match))
+	}
+}
+
+// MatchStyle returns style of given match.
+func (m *Model) MatchStyle(match Match) styles.Style {
+	if m.IsCurrent(match) {
+		return m.Styles.CurrentMatch
+	}
+	return m.Styles.Match
+}
+
+// MatchAt returns match containing given position, if there is one.
+func (m *Model) MatchAt(line, col int) (Match, bool) {
+	for _, match := range m.MatchesIn(line) {
+		if match.Start <= col && col < match.End {
+			return match, true
+		}
+	}
+	return Match{}, false
+}
+
+// View renders search bar.
+func (m *Model) View(vb tea.Viewbox) {
+	if !m.active {
+		return
+	}
+
+	flag := func(on bool) styles.Style {
+		if on {
+			return m.Styles.FlagOn
+		}
+		return m.Styles.FlagOff
+	}
+
+	var status string
+	var statusStyle styles.Style
+	switch {
+	case m.err != nil:
+		status, statusStyle = "invalid query", m.Styles.Error
+	case len(m.matches) == 0:
+		status, statusStyle = "no matches", m.Styles.Counter
+	default:
+		status, statusStyle = fmt.Sprintf("%d/%d", m.current+1, len(m.matches)), m.Styles.Counter
+	}
+
+	// query row: input, match counter, regexp and ignore case flags
+	vbQuery := vb.Row(0)
+	const flags = " .* Aa"
+	vbInput, vbStatus := vbQuery.SplitX2(tea.Flex(1), tea.Fixed(len(status)+len(flags)))
+	m.query.Width = max(0, vbInput.Width-len(m.query.Prompt)-1)
+	m.query.View(vbInput)
+	vbStatus = vbStatus.PaddingLeft(vbStatus.Styled(statusStyle).WriteLine(status) + 1)
+	vbStatus = vbStatus.PaddingLeft(vbStatus.Styled(flag(m.Regexp)).WriteLine(".*") + 1)
+	vbStatus.Styled(flag(m.IgnoreCase)).WriteLine("Aa")
+
+	if m.replacing {
+		m.replacement.Width = max(0, vb.Width-len(m.replacement.Prompt)-1)
+		m.replacement.View(vb.Row(1))
+	}
+}
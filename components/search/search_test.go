@@ -0,0 +1,78 @@
+package search
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/rprtr258/assert"
+
+	"github.com/rprtr258/tea"
+)
+
+func TestFind(t *testing.T) {
+	lines := []string{"foo bar", "нет foo", "", "foofoo"}
+
+	m := New()
+	m.SetQuery("foo")
+	m.Find(slices.All(lines))
+	assert.Equal(t, []Match{
+		{Line: 0, Start: 0, End: 3, X: 0, Width: 3},
+		{Line: 1, Start: 4, End: 7, X: 4, Width: 3},
+		{Line: 3, Start: 0, End: 3, X: 0, Width: 3},
+		{Line: 3, Start: 3, End: 6, X: 3, Width: 3},
+	}, m.Matches())
+	assert.Equal(t, 2, len(m.MatchesIn(3)))
+	assert.Equal(t, 0, len(m.MatchesIn(2)))
+
+	m.Nearest(1, 1)
+	current, _ := m.Current()
+	assert.Equal(t, 1, current.Line)
+	m.Previous()
+	m.Previous()
+	current, _ = m.Current()
+	assert.Equal(t, 3, current.Start)
+
+	m.IgnoreCase = true
+	m.SetQuery("FOO")
+	m.Find(slices.All(lines))
+	assert.Equal(t, 4, len(m.Matches()))
+
+	m.Regexp = true
+	m.SetQuery("(")
+	assert.True(t, m.Err() != nil)
+}
+
+func TestReplace(t *testing.T) {
+	m := New()
+	m.Regexp = true
+	m.SetQuery(`(\w+)@(\w+)`)
+	m.replacement.SetValue("$2 at $1")
+
+	line := "я a@b, c@d"
+	m.Find(slices.All([]string{line}))
+	assert.Equal(t, "я a@b, d at c", m.Replace(line, m.Matches()[1]))
+	assert.Equal(t, "я b at a, d at c", m.ReplaceAll(line))
+
+	// literal replacement is not expanded
+	m.Regexp = false
+	m.SetQuery("a@b")
+	assert.Equal(t, "я $2 at $1, c@d", m.ReplaceAll(line))
+}
+
+func TestUpdate(t *testing.T) {
+	f := func(...tea.Cmd) {}
+
+	m := New()
+	m.Open(true)
+	for range 2 {
+		// there are no suggestions to switch between
+		assert.Equal(t, ActionNone, m.Update(tea.MsgKey{Type: tea.KeyDown}, f))
+		assert.Equal(t, ActionNone, m.Update(tea.MsgKey{Type: tea.KeyUp}, f))
+		m.Update(tea.MsgKey{Type: tea.KeyTab}, f)
+	}
+
+	assert.Equal(t, ActionFind, m.Update(tea.MsgKey{Type: tea.KeyRunes, Runes: []rune("foo")}, f))
+	assert.Equal(t, "foo", m.Query())
+	assert.Equal(t, ActionClose, m.Update(tea.MsgKey{Type: tea.KeyEsc}, f))
+	assert.False(t, m.Active())
+}
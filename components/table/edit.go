@@ -0,0 +1,112 @@
+package table
+
+import (
+	"github.com/rprtr258/tea"
+	"github.com/rprtr258/tea/components/key"
+)
+
+// MsgCellEdited is sent when new value of edited cell is committed, so that
+// app can persist it.
+type MsgCellEdited[T any] struct {
+	// Row is index of row in rows table was created with, Column is index
+	// of column.
+	Row, Column int
+	Value       string
+	// Updated is the row with new value set.
+	Updated T
+}
+
+// cell is a cell of row with given index in rows table was created with.
+type cell struct {
+	row, col int
+}
+
+// Edit starts editing selected cell, returns false if its column is not
+// editable or there is no selected row.
+func (m *Model[T]) Edit(f func(...tea.Cmd)) bool {
+	col, ok := m.focusedColumn()
+	if !ok || m.columns[col].Set == nil || m.Table.Cursor() == -1 {
+		return false
+	}
+
+	c, row := m.columns[col], m.Table.Selected()
+	// current value is shown even if it is invalid
+	m.editor.Validate, m.editor.Check = nil, nil
+	m.editor.Reset()
+	m.editor.SetValue(c.Get(row))
+	m.editor.CursorEnd()
+	m.editor.Validate = c.Validate
+	m.editor.Check = func(value string) error {
+		_, err := c.Set(row, value)
+		return err
+	}
+	m.editing = true
+	f(m.editor.Focus()...)
+	return true
+}
+
+// Editing returns whether a cell is being edited.
+func (m *Model[T]) Editing() bool {
+	return m.editing
+}
+
+// IsDirty returns whether cell of shown row y and column with given index
+// was edited since ClearDirty.
+func (m *Model[T]) IsDirty(y, col int) bool {
+	return m.dirty[cell{row: m.Table.RowIndex(y), col: col}]
+}
+
+// ClearDirty stops highlighting edited cells, e.g. after they are saved.
+func (m *Model[T]) ClearDirty() {
+	clear(m.dirty)
+}
+
+// updateEdit handles keys while cell is being edited.
+func (m *Model[T]) updateEdit(msg tea.MsgKey, f func(...tea.Cmd)) {
+	switch {
+	case key.Matches(msg, m.KeyMap.AcceptEdit):
+		m.commitEdit(f)
+	case key.Matches(msg, m.KeyMap.CancelEdit):
+		m.stopEdit()
+	default:
+		m.editor.Update(msg, f)
+	}
+}
+
+// commitEdit sets value typed into edited cell. Invalid value keeps cell
+// being edited with error shown.
+func (m *Model[T]) commitEdit(f func(...tea.Cmd)) {
+	col, ok := m.focusedColumn()
+	if !ok || m.Table.Cursor() == -1 {
+		m.stopEdit()
+		return
+	}
+
+	c, y, value := m.columns[col], m.Table.Cursor(), m.editor.Value()
+	if value == c.Get(m.Table.Selected()) {
+		m.stopEdit()
+		return
+	}
+
+	row, err := c.Set(m.Table.Selected(), value)
+	if err != nil {
+		m.editor.Err = err
+		return
+	}
+
+	index := m.Table.RowIndex(y)
+	m.dirty[cell{row: index, col: col}] = true
+	m.Table.SetRow(y, row)
+	m.viewport.ScrollIntoView(m.Table.Cursor())
+	m.stopEdit()
+	f(func() tea.Msg {
+		return MsgCellEdited[T]{Row: index, Column: col, Value: value, Updated: row}
+	})
+}
+
+// stopEdit stops editing cell without changing it.
+func (m *Model[T]) stopEdit() {
+	m.editing = false
+	m.editor.Blur()
+	m.editor.Err = nil
+}
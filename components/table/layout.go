@@ -0,0 +1,176 @@
+package table
+
+import (
+	"github.com/mattn/go-runewidth"
+
+	"github.com/rprtr258/tea"
+)
+
+const (
+	// _gap is number of cells between columns.
+	_gap = 2
+	// _marker is drawn before marked rows.
+	_marker = '•'
+)
+
+// placed is a column placed in view.
+type placed struct {
+	col int // index in columns
+	pos int // index in shown columns
+	// x is offset from the left of table content
+	x, width int
+}
+
+// drag is a column being resized by dragging its header separator.
+type drag struct {
+	col int
+	// x and width are mouse position and column width when dragging started
+	x, width int
+}
+
+// naturalWidth returns width set for column, or width fitting its header and
+// content.
+func (m *Model[T]) naturalWidth(col int, header string, rows []T) int {
+	if width, ok := m.widths[col]; ok {
+		return width
+	}
+
+	c := m.columns[col]
+	width := runewidth.StringWidth(header)
+	for _, row := range rows {
+		width = max(width, runewidth.StringWidth(c.Get(row)))
+	}
+	if c.MaxWidth > 0 {
+		width = min(width, c.MaxWidth)
+	}
+	return max(width, c.MinWidth)
+}
+
+// place lays out shown columns on width cells. If all columns fit, flex
+// columns take the space left. Otherwise columns take their natural widths,
+// first Frozen columns are always shown and the rest are scrolled by column,
+// so that focused column is visible.
+func (m *Model[T]) place(width int, headers []string, rows []T) []placed {
+	natural := make([]int, len(m.shown))
+	total := -_gap
+	for i, col := range m.shown {
+		natural[i] = m.naturalWidth(col, headers[i], rows)
+		total += natural[i] + _gap
+	}
+
+	if total <= width {
+		m.left = 0
+		layouts := make([]tea.Layout, len(m.shown))
+		for i, col := range m.shown {
+			c := m.columns[col]
+			if _, ok := m.widths[col]; ok || c.Flex == 0 {
+				layouts[i] = tea.Fixed(natural[i])
+			} else {
+				layouts[i] = tea.Flex(c.Flex).Min(natural[i]).Max(c.MaxWidth)
+			}
+		}
+
+		offsets, widths := tea.Flow{Gap: _gap}.Eval(width, layouts...)
+		res := make([]placed, len(m.shown))
+		for i, col := range m.shown {
+			res[i] = placed{col: col, pos: i, x: offsets[i], width: widths[i]}
+		}
+		return res
+	}
+
+	frozen := min(m.Frozen, len(m.shown))
+	frozenWidth := 0
+	for _, w := range natural[:frozen] {
+		frozenWidth += w + _gap
+	}
+
+	// fits returns whether columns from left to last are shown fully
+	fits := func(left, last int) bool {
+		right := frozenWidth - _gap
+		for _, w := range natural[left : last+1] {
+			right += _gap + w
+		}
+		return right <= width
+	}
+
+	// scroll so that focused column is visible, without leaving space
+	// after the last column
+	m.left = max(m.left, frozen)
+	if m.column >= frozen {
+		m.left = min(m.left, m.column)
+		for m.left < m.column && !fits(m.left, m.column) {
+			m.left++
+		}
+	}
+	for m.left > frozen && fits(m.left-1, len(m.shown)-1) {
+		m.left--
+	}
+
+	var res []placed
+	x := 0
+	add := func(i int) bool {
+		if x >= width {
+			return false
+		}
+
+		w := min(natural[i], width-x)
+		res = append(res, placed{col: m.shown[i], pos: i, x: x, width: w})
+		x += w + _gap
+		return true
+	}
+	for i := range frozen {
+		add(i)
+	}
+	for i := m.left; i < len(m.shown); i++ {
+		if !add(i) {
+			break
+		}
+	}
+	return res
+}
+
+// SetColumnWidth sets width of column with given index, non positive width
+// makes column fit its content again.
+func (m *Model[T]) SetColumnWidth(col, width int) {
+	if width <= 0 {
+		delete(m.widths, col)
+		return
+	}
+
+	m.widths[col] = width
+}
+
+// columnWidth returns width of column as it was shown last time.
+func (m *Model[T]) columnWidth(col int) int {
+	if width, ok := m.widths[col]; ok {
+		return width
+	}
+
+	for _, p := range m.placed {
+		if p.col == col {
+			return p.width
+		}
+	}
+	return 0
+}
+
+// resize changes width of focused column by delta.
+func (m *Model[T]) resize(delta int) {
+	if col, ok := m.focusedColumn(); ok {
+		m.SetColumnWidth(col, max(m.columnWidth(col)+delta, 1))
+	}
+}
+
+// updateDrag resizes column while its header separator is dragged.
+func (m *Model[T]) updateDrag(msg tea.MsgMouse) {
+	if m.drag == nil {
+		return
+	}
+
+	switch msg.Type {
+	case tea.MouseLeft, tea.MouseMotion:
+		m.SetColumnWidth(m.drag.col, max(m.drag.width+msg.X-m.drag.x, 1))
+	case tea.MouseRelease:
+		m.drag = nil
+	}
+}
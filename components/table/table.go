@@ -1,23 +1,98 @@
 package table
 
 import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
 	"github.com/mattn/go-runewidth"
 
 	"github.com/rprtr258/tea"
 	"github.com/rprtr258/tea/components/box"
 	"github.com/rprtr258/tea/components/headless/table"
 	"github.com/rprtr258/tea/components/key"
+	"github.com/rprtr258/tea/components/textinput"
 	"github.com/rprtr258/tea/components/viewport"
 	"github.com/rprtr258/tea/styles"
 )
 
-// Column defines the table structure
-type Column = table.Column[[]string]
+// Align is horizontal alignment of cell content.
+type Align int
+
+const (
+	AlignLeft Align = iota
+	AlignRight
+	AlignCenter
+)
+
+// Column defines how values of row type T are shown in a column.
+type Column[T any] struct {
+	Title string
+	// Get returns cell text, it is used for rendering, sizing column and
+	// sorting if Cmp is not set.
+	Get func(T) string
+	Cmp func(T, T) int
+
+	Align Align
+	// Style of column cells, applied on top of table cell style.
+	Style styles.Style
+	// Render renders cell instead of writing text returned by Get, e.g. to
+	// show colored badge. Viewbox is the cell with column and row styles.
+	Render func(vb tea.Viewbox, row T)
+
+	// MinWidth and MaxWidth bound column width, zero MaxWidth means
+	// unbounded. Column width fits its content unless Flex is set.
+	MinWidth, MaxWidth int
+	// Flex makes column take space left after other columns, shared
+	// between flex columns proportionally to their Flex values.
+	Flex int
+
+	// Set parses and validates value typed into cell, returning row with
+	// the value set. Returned error keeps cell being edited. Set must not
+	// modify given row, columns without Set are not editable.
+	Set func(row T, value string) (T, error)
+	// Validate rejects input while cell is being typed, e.g. letters in
+	// numeric column.
+	Validate textinput.ValidateFunc
+}
+
+// _lastID is used to generate unique mouse zone ids of tables.
+var _lastID atomic.Int64
 
 // Model defines a state for the table widget
-type Model struct {
-	Table  *table.Table[[]string]
-	widths []int
+type Model[T any] struct {
+	Table   *table.Table[T]
+	columns []Column[T]
+	// shown is indexes of shown columns in order they are shown
+	shown []int
+	// column is index of focused column in shown, it is the column of
+	// selected cell
+	column int
+	// Frozen is number of first shown columns kept visible while scrolling
+	// horizontally, e.g. row identifiers.
+	Frozen int
+	// left is index in shown of the first column shown after frozen ones
+	left int
+	// widths are column widths set by user
+	widths map[int]int
+	// placed are columns shown last time
+	placed []placed
+	drag   *drag
+	// pressed is set while mouse button is held, dragging is set if button
+	// was held before current press, as dragging is reported as repeated
+	// presses
+	pressed, dragging bool
+
+	// id prefixes mouse zone ids of header cells and separators
+	id        string
+	filter    textinput.Model
+	filtering bool
+	editor    textinput.Model
+	editing   bool
+	// dirty are cells edited since ClearDirty
+	dirty map[cell]bool
 
 	styles   Styles
 	KeyMap   KeyMap
@@ -31,6 +106,24 @@ type KeyMap struct {
 	PageUp, PageDown         key.Binding
 	HalfPageUp, HalfPageDown key.Binding
 	GotoTop, GotoBottom      key.Binding
+
+	PrevColumn, NextColumn    key.Binding
+	FirstColumn, LastColumn   key.Binding
+	WidenColumn, NarrowColumn key.Binding
+	// Sort cycles sort of focused column between ascending, descending and
+	// none, SortAdd does the same keeping sort by other columns.
+	Sort, SortAdd                   key.Binding
+	HideColumn, ShowColumns         key.Binding
+	MoveColumnLeft, MoveColumnRight key.Binding
+
+	Filter, AcceptFilter, CancelFilter key.Binding
+
+	// MarkUp and MarkDown mark rows while moving cursor, MarkAll marks all
+	// shown rows or unmarks them if they are all marked already.
+	ToggleMark, MarkUp, MarkDown key.Binding
+	MarkAll, InvertMarks         key.Binding
+
+	Edit, AcceptEdit, CancelEdit key.Binding
 }
 
 // DefaultKeyMap returns a default set of keybindings
@@ -48,7 +141,7 @@ var DefaultKeyMap = KeyMap{
 		Help: key.Help{"b/pgup", "page up"},
 	},
 	PageDown: key.Binding{
-		Keys: []string{"f", "pgdown", " "},
+		Keys: []string{"f", "pgdown"},
 		Help: key.Help{"f/pgdn", "page down"},
 	},
 	HalfPageUp: key.Binding{
@@ -67,40 +160,166 @@ var DefaultKeyMap = KeyMap{
 		Keys: []string{"end", "G"},
 		Help: key.Help{"G/end", "go to end"},
 	},
+	PrevColumn: key.Binding{
+		Keys: []string{"left", "h"},
+		Help: key.Help{"←/h", "previous column"},
+	},
+	NextColumn: key.Binding{
+		Keys: []string{"right", "l"},
+		Help: key.Help{"→/l", "next column"},
+	},
+	FirstColumn: key.Binding{
+		Keys: []string{"0"},
+		Help: key.Help{"0", "first column"},
+	},
+	LastColumn: key.Binding{
+		Keys: []string{"$"},
+		Help: key.Help{"$", "last column"},
+	},
+	WidenColumn: key.Binding{
+		Keys: []string{"+"},
+		Help: key.Help{"+", "widen column"},
+	},
+	NarrowColumn: key.Binding{
+		Keys: []string{"-"},
+		Help: key.Help{"-", "narrow column"},
+	},
+	Sort: key.Binding{
+		Keys: []string{"s"},
+		Help: key.Help{"s", "sort"},
+	},
+	SortAdd: key.Binding{
+		Keys: []string{"S"},
+		Help: key.Help{"S", "add to sort"},
+	},
+	HideColumn: key.Binding{
+		Keys: []string{"x"},
+		Help: key.Help{"x", "hide column"},
+	},
+	ShowColumns: key.Binding{
+		Keys: []string{"X"},
+		Help: key.Help{"X", "show all columns"},
+	},
+	MoveColumnLeft: key.Binding{
+		Keys: []string{"<"},
+		Help: key.Help{"<", "move column left"},
+	},
+	MoveColumnRight: key.Binding{
+		Keys: []string{">"},
+		Help: key.Help{">", "move column right"},
+	},
+	Filter: key.Binding{
+		Keys: []string{"/"},
+		Help: key.Help{"/", "filter"},
+	},
+	AcceptFilter: key.Binding{
+		Keys: []string{"enter"},
+		Help: key.Help{"enter", "apply filter"},
+	},
+	CancelFilter: key.Binding{
+		Keys: []string{"esc"},
+		Help: key.Help{"esc", "cancel filter"},
+	},
+	ToggleMark: key.Binding{
+		Keys: []string{" "},
+		Help: key.Help{"space", "mark"},
+	},
+	MarkUp: key.Binding{
+		Keys: []string{"shift+up", "K"},
+		Help: key.Help{"shift+↑/K", "mark up"},
+	},
+	MarkDown: key.Binding{
+		Keys: []string{"shift+down", "J"},
+		Help: key.Help{"shift+↓/J", "mark down"},
+	},
+	MarkAll: key.Binding{
+		Keys: []string{"ctrl+a"},
+		Help: key.Help{"ctrl+a", "mark all"},
+	},
+	InvertMarks: key.Binding{
+		Keys: []string{"*"},
+		Help: key.Help{"*", "invert marks"},
+	},
+	Edit: key.Binding{
+		Keys: []string{"e", "f2"},
+		Help: key.Help{"e/f2", "edit cell"},
+	},
+	AcceptEdit: key.Binding{
+		Keys: []string{"enter"},
+		Help: key.Help{"enter", "save cell"},
+	},
+	CancelEdit: key.Binding{
+		Keys: []string{"esc"},
+		Help: key.Help{"esc", "cancel edit"},
+	},
 }
 
 // Styles contains style definitions for this list component.
 // By default, these values are generated by DefaultStyles.
 type Styles struct {
-	Header   styles.Style
-	Cell     styles.Style
-	Selected styles.Style
+	Header        styles.Style
+	FocusedHeader styles.Style
+	Cell          styles.Style
+	Selected      styles.Style
+	SelectedCell  styles.Style
+	// Marked is style of marked rows and their marker.
+	Marked styles.Style
+	// Dirty is style of cells edited since ClearDirty.
+	Dirty     styles.Style
+	EditError styles.Style
 }
 
 // DefaultStyles returns a set of default style definitions for this table
 var DefaultStyles = Styles{
-	Selected: styles.Style{}.Bold(true).Foreground(styles.FgColor("212")),
-	Header:   styles.Style{}.Bold(true), /*.Padding(0, 1)*/
-	Cell:     styles.Style{},            /*.Padding(0, 1)*/
+	Selected:      styles.Style{}.Bold(true).Foreground(styles.FgColor("212")),
+	Header:        styles.Style{}.Bold(true), /*.Padding(0, 1)*/
+	FocusedHeader: styles.Style{}.Underline(),
+	Cell:          styles.Style{}, /*.Padding(0, 1)*/
+	SelectedCell:  styles.Style{}.Reverse(true),
+	Marked:        styles.Style{}.Foreground(styles.FgColor("214")),
+	Dirty:         styles.Style{}.Italic().Foreground(styles.FgColor("11")),
+	EditError:     styles.Style{}.Foreground(styles.FgColor("9")),
 }
 
-// Option is used to set options in New. For example:
-//
-//	table := New(WithColumns([]Column{{Title: "ID", Width: 10}}))
-type Option = func(*Model)
-
 // New creates a new model for the table widget
-func New(
-	cols []Column,
-	widths []int,
-	rows [][]string,
+func New[T any](
+	cols []Column[T],
+	rows []T,
 	height, width int,
 	styles Styles,
 	keyMap KeyMap,
-) Model {
-	m := Model{
-		Table:    table.New(cols, rows),
-		widths:   widths,
+) Model[T] {
+	headless := make([]table.Column[T], len(cols))
+	for i, col := range cols {
+		cmp := col.Cmp
+		if cmp == nil {
+			cmp = func(a, b T) int {
+				return strings.Compare(col.Get(a), col.Get(b))
+			}
+		}
+		headless[i] = table.Column[T]{Title: col.Title, Get: col.Get, Cmp: cmp}
+	}
+
+	shown := make([]int, len(cols))
+	for i := range shown {
+		shown[i] = i
+	}
+
+	filter := textinput.New()
+	filter.Prompt = "/ "
+
+	editor := textinput.New()
+	editor.Prompt = ""
+
+	m := Model[T]{
+		Table:    table.New(headless, rows),
+		columns:  cols,
+		shown:    shown,
+		widths:   map[int]int{},
+		id:       fmt.Sprintf("table-%d", _lastID.Add(1)),
+		filter:   filter,
+		editor:   editor,
+		dirty:    map[cell]bool{},
 		viewport: viewport.New(0, 20),
 
 		KeyMap: keyMap, // DefaultKeyMap,
@@ -113,13 +332,35 @@ func New(
 }
 
 // SetStyles sets the table styles
-func (m *Model) SetStyles(s Styles) {
+func (m *Model[T]) SetStyles(s Styles) {
 	m.styles = s
 }
 
 // Update is the Tea update loop
-func (m *Model) Update(msg tea.Msg) {
-	if msg, ok := msg.(tea.MsgKey); ok {
+func (m *Model[T]) Update(msg tea.Msg, f func(...tea.Cmd)) {
+	switch msg := msg.(type) {
+	case tea.MsgMouse:
+		switch msg.Type {
+		case tea.MouseLeft:
+			m.dragging, m.pressed = m.pressed, true
+		case tea.MouseRelease:
+			m.dragging, m.pressed = false, false
+		}
+		m.updateDrag(msg)
+	case tea.MsgMouseZone:
+		if !m.editing {
+			m.updateMouse(msg)
+		}
+	case tea.MsgKey:
+		if m.filtering {
+			m.updateFilter(msg, f)
+			return
+		}
+		if m.editing {
+			m.updateEdit(msg, f)
+			return
+		}
+
 		switch {
 		case key.Matches(msg, m.KeyMap.LineUp):
 			m.MoveUp(1)
@@ -137,60 +378,328 @@ func (m *Model) Update(msg tea.Msg) {
 			m.GotoTop()
 		case key.Matches(msg, m.KeyMap.GotoBottom):
 			m.GotoBottom()
+		case key.Matches(msg, m.KeyMap.PrevColumn):
+			m.column = max(m.column-1, 0)
+		case key.Matches(msg, m.KeyMap.NextColumn):
+			m.column = max(min(m.column+1, len(m.shown)-1), 0)
+		case key.Matches(msg, m.KeyMap.FirstColumn):
+			m.column = 0
+		case key.Matches(msg, m.KeyMap.LastColumn):
+			m.column = max(len(m.shown)-1, 0)
+		case key.Matches(msg, m.KeyMap.WidenColumn):
+			m.resize(1)
+		case key.Matches(msg, m.KeyMap.NarrowColumn):
+			m.resize(-1)
+		case key.Matches(msg, m.KeyMap.Sort, m.KeyMap.SortAdd):
+			if col, ok := m.focusedColumn(); ok {
+				m.CycleSort(col, key.Matches(msg, m.KeyMap.SortAdd))
+			}
+		case key.Matches(msg, m.KeyMap.HideColumn):
+			if col, ok := m.focusedColumn(); ok {
+				m.HideColumn(col)
+			}
+		case key.Matches(msg, m.KeyMap.ShowColumns):
+			m.ShowColumns()
+		case key.Matches(msg, m.KeyMap.MoveColumnLeft):
+			m.moveColumn(-1)
+		case key.Matches(msg, m.KeyMap.MoveColumnRight):
+			m.moveColumn(1)
+		case key.Matches(msg, m.KeyMap.ToggleMark):
+			m.Table.ToggleMark()
+		case key.Matches(msg, m.KeyMap.MarkUp):
+			m.Table.MarkRange(m.Cursor(), m.Cursor()-1, true)
+			m.MoveUp(1)
+		case key.Matches(msg, m.KeyMap.MarkDown):
+			m.Table.MarkRange(m.Cursor(), m.Cursor()+1, true)
+			m.MoveDown(1)
+		case key.Matches(msg, m.KeyMap.MarkAll):
+			m.Table.MarkRange(0, m.Table.RowsCount()-1, len(m.Table.Marked()) < m.Table.RowsCount())
+		case key.Matches(msg, m.KeyMap.InvertMarks):
+			m.Table.InvertMarks()
+		case key.Matches(msg, m.KeyMap.Filter):
+			m.filtering = true
+			f(m.filter.Focus()...)
+		case key.Matches(msg, m.KeyMap.Edit):
+			m.Edit(f)
+		}
+	}
+}
+
+// updateMouse sorts by column which header is clicked, ctrl+click adds
+// column to sort. Dragging separator after header resizes column.
+func (m *Model[T]) updateMouse(msg tea.MsgMouseZone) {
+	id, ok := strings.CutPrefix(msg.ID, m.id+"-")
+	if !ok || msg.Event.Type != tea.MouseLeft || m.dragging || m.drag != nil {
+		// only press is a click, not dragging over headers
+		return
+	}
+
+	id, separator := strings.CutPrefix(id, "sep-")
+	col, err := strconv.Atoi(id)
+	if err != nil {
+		return
+	}
+
+	if separator {
+		if m.drag == nil {
+			m.drag = &drag{col: col, x: msg.Event.X, width: m.columnWidth(col)}
+		}
+		return
+	}
+
+	m.column = max(slices.Index(m.shown, col), 0)
+	m.CycleSort(col, msg.Event.Ctrl)
+}
+
+// updateFilter handles keys while filter is being typed.
+func (m *Model[T]) updateFilter(msg tea.MsgKey, f func(...tea.Cmd)) {
+	switch {
+	case key.Matches(msg, m.KeyMap.AcceptFilter):
+		m.filtering = false
+		m.filter.Blur()
+	case key.Matches(msg, m.KeyMap.CancelFilter):
+		m.filtering = false
+		m.filter.Blur()
+		m.SetFilter("")
+	default:
+		query := m.filter.Value()
+		m.filter.Update(msg, f)
+		if m.filter.Value() != query {
+			m.SetFilter(m.filter.Value())
+		}
+	}
+}
+
+// focusedColumn returns index of focused column.
+func (m *Model[T]) focusedColumn() (int, bool) {
+	if m.column >= len(m.shown) {
+		return 0, false
+	}
+	return m.shown[m.column], true
+}
+
+// CycleSort cycles sort by column between ascending, descending and none.
+// If add is set, sort by other columns is kept and column is added as the
+// least significant one, otherwise rows are sorted by that column only.
+// Cursor stays on the same row.
+func (m *Model[T]) CycleSort(col int, add bool) {
+	keys := m.Table.SortKeys()
+	i := slices.IndexFunc(keys, func(k table.SortKey) bool { return k.Column == col })
+	if !add {
+		if i != -1 {
+			keys = keys[i : i+1]
+			i = 0
+		} else {
+			keys = nil
+		}
+	}
+	keys = slices.Clone(keys)
+
+	switch {
+	case i == -1:
+		keys = append(keys, table.SortKey{Column: col})
+	case !keys[i].Desc:
+		keys[i].Desc = true
+	default:
+		keys = slices.Delete(keys, i, i+1)
+	}
+
+	m.Table.SortBy(keys...)
+	m.viewport.ScrollIntoView(m.Table.Cursor())
+}
+
+// ColumnOrder returns indexes of shown columns in order they are shown.
+func (m *Model[T]) ColumnOrder() []int {
+	return m.shown
+}
+
+// SetColumnOrder shows columns with given indexes in given order, other
+// columns are hidden.
+func (m *Model[T]) SetColumnOrder(order []int) {
+	m.shown = order
+	m.column = max(min(m.column, len(m.shown)-1), 0)
+}
+
+// HideColumn hides column with given index.
+func (m *Model[T]) HideColumn(col int) {
+	m.SetColumnOrder(slices.DeleteFunc(slices.Clone(m.shown), func(i int) bool { return i == col }))
+}
+
+// ShowColumns shows all columns in their original order.
+func (m *Model[T]) ShowColumns() {
+	order := make([]int, len(m.columns))
+	for i := range order {
+		order[i] = i
+	}
+	m.SetColumnOrder(order)
+}
+
+// moveColumn moves focused column by delta positions.
+func (m *Model[T]) moveColumn(delta int) {
+	to := m.column + delta
+	if to < 0 || to >= len(m.shown) {
+		return
+	}
+
+	m.shown[m.column], m.shown[to] = m.shown[to], m.shown[m.column]
+	m.column = to
+}
+
+// SetFilter shows only rows matching query. Query is space separated terms,
+// term matches row if any shown column contains it, or column with given
+// title if term is written as "title:text". Matching is case insensitive.
+func (m *Model[T]) SetFilter(query string) {
+	m.filter.SetValue(query)
+
+	type term struct {
+		col  int // -1 for any shown column
+		text string
+	}
+	var terms []term
+	for _, field := range strings.Fields(strings.ToLower(query)) {
+		t := term{col: -1, text: field}
+		if title, text, ok := strings.Cut(field, ":"); ok {
+			if col := slices.IndexFunc(m.columns, func(c Column[T]) bool {
+				return strings.ToLower(c.Title) == title
+			}); col != -1 {
+				t = term{col: col, text: text}
+			}
 		}
+		terms = append(terms, t)
+	}
+
+	if len(terms) == 0 {
+		m.Table.SetFilter(nil)
+	} else {
+		m.Table.SetFilter(func(row T) bool {
+			contains := func(col int, text string) bool {
+				return strings.Contains(strings.ToLower(m.columns[col].Get(row)), text)
+			}
+			for _, t := range terms {
+				if t.col != -1 && !contains(t.col, t.text) ||
+					t.col == -1 && !slices.ContainsFunc(m.shown, func(col int) bool { return contains(col, t.text) }) {
+					return false
+				}
+			}
+			return true
+		})
 	}
+	m.viewport.ScrollIntoView(m.Table.Cursor())
+}
+
+// FilterValue returns current filter query.
+func (m *Model[T]) FilterValue() string {
+	return m.filter.Value()
+}
+
+// Filtering returns whether filter query is being typed.
+func (m *Model[T]) Filtering() bool {
+	return m.filtering
 }
 
-// SelectedRow returns the selected row.
-// You can cast it to your own implementation.
-func (m *Model) SelectedRow() []string {
+// SelectedRow returns the selected row, false if there is none.
+func (m *Model[T]) SelectedRow() (T, bool) {
 	if m.Table.Cursor() == -1 {
-		return nil
+		var row T
+		return row, false
 	}
 
-	return m.Table.Selected()
+	return m.Table.Selected(), true
+}
+
+// MarkedRows returns marked rows in display order.
+func (m *Model[T]) MarkedRows() []T {
+	return m.Table.Marked()
 }
 
-func (m *Model) Height() int     { return m.viewport.Height } // Height returns the viewport height of the table
-func (m *Model) SetHeight(h int) { m.viewport.Height = h }    // SetHeight sets the height of the viewport of the table
+func (m *Model[T]) Height() int     { return m.viewport.Height } // Height returns the viewport height of the table
+func (m *Model[T]) SetHeight(h int) { m.viewport.Height = h }    // SetHeight sets the height of the viewport of the table
 
-func (m *Model) Width() int     { return m.viewport.Width } // Width returns the viewport width of the table
-func (m *Model) SetWidth(w int) { m.viewport.Width = w }    // SetWidth sets the width of the viewport of the table
+func (m *Model[T]) Width() int     { return m.viewport.Width } // Width returns the viewport width of the table
+func (m *Model[T]) SetWidth(w int) { m.viewport.Width = w }    // SetWidth sets the width of the viewport of the table
 
-func (m *Model) Cursor() int     { return m.Table.Cursor() } // Cursor returns the index of the selected row
-func (m *Model) SetCursor(n int) { m.Table.MoveTo(n) }       // SetCursor sets the cursor position in the table
+func (m *Model[T]) Cursor() int     { return m.Table.Cursor() } // Cursor returns the index of the selected row
+func (m *Model[T]) SetCursor(n int) { m.Table.MoveTo(n) }       // SetCursor sets the cursor position in the table
 
 // MoveUp moves the selection up by any number of rows.
 // It can not go above the first row.
-func (m *Model) MoveUp(n int) {
+func (m *Model[T]) MoveUp(n int) {
 	m.Table.MoveUp(n)
-	m.viewport.SetYOffset(m.Table.Cursor())
+	m.viewport.ScrollIntoView(m.Table.Cursor())
 }
 
 // MoveDown moves the selection down by any number of rows.
 // It can not go below the last row.
-func (m *Model) MoveDown(n int) {
+func (m *Model[T]) MoveDown(n int) {
 	m.Table.MoveDown(n)
-	m.viewport.SetYOffset(m.Table.Cursor())
+	m.viewport.ScrollIntoView(m.Table.Cursor())
 }
 
 // GotoTop moves the selection to the first row
-func (m *Model) GotoTop() {
+func (m *Model[T]) GotoTop() {
 	m.MoveUp(m.Table.Cursor())
 }
 
 // GotoBottom moves the selection to the last row
-func (m *Model) GotoBottom() {
+func (m *Model[T]) GotoBottom() {
 	m.MoveDown(m.Table.RowsCount())
 }
 
+// writeAligned writes text aligned in viewbox, truncating it if too long.
+func writeAligned(vb tea.Viewbox, text string, align Align) {
+	text = runewidth.Truncate(text, vb.Width, "…")
+	switch gap := vb.Width - runewidth.StringWidth(text); align {
+	case AlignLeft:
+	case AlignRight:
+		vb = vb.PaddingLeft(gap)
+	case AlignCenter:
+		vb = vb.PaddingLeft(gap / 2)
+	}
+	vb.WriteLine(text)
+}
+
+// header returns title of column with sort indicator. Order of sort is shown
+// if rows are sorted by several columns.
+func (m *Model[T]) header(col int) string {
+	keys := m.Table.SortKeys()
+	i := slices.IndexFunc(keys, func(k table.SortKey) bool { return k.Column == col })
+	if i == -1 {
+		return m.columns[col].Title
+	}
+
+	indicator := " ▲"
+	if keys[i].Desc {
+		indicator = " ▼"
+	}
+	if len(keys) > 1 {
+		indicator += strconv.Itoa(i + 1)
+	}
+	return m.columns[col].Title + indicator
+}
+
 // View renders the component
-func (m *Model) View(vb tea.Viewbox) {
-	const _gap = 2
+func (m *Model[T]) View(vb tea.Viewbox) {
+	// 2 for borders, 1 for header, 1 for split line
+	line := 2 + 2 + m.viewport.Height
+	if m.filtering || m.filter.Value() != "" {
+		m.filter.View(vb.Row(line).PaddingLeft(1))
+		line++
+	}
+	if m.editing && m.editor.Err != nil {
+		vb.Row(line).PaddingLeft(1).Styled(m.styles.EditError).WriteLine(m.editor.Err.Error())
+	}
+
+	rows := m.Table.Rows()
+	headers := make([]string, len(m.shown))
+	for i, col := range m.shown {
+		headers[i] = m.header(col)
+	}
+
 	// 2 for borders, 2 for margin
-	totalWidth := 2 + 2 + _gap*(len(m.widths)-1)
-	for _, width := range m.widths {
-		totalWidth += width
+	m.placed = m.place(vb.Width-2-2, headers, rows)
+	totalWidth := 2 + 2
+	if n := len(m.placed); n > 0 {
+		totalWidth += m.placed[n-1].x + m.placed[n-1].width
 	}
 
 	vb = vb.
@@ -199,27 +708,64 @@ func (m *Model) View(vb tea.Viewbox) {
 		MaxHeight(2 + 2 + m.viewport.Height)
 	box.Box(
 		vb,
-		func(vb tea.Viewbox) {
+		func(vbBox tea.Viewbox) {
+			vb := vbBox.Padding(tea.PaddingOptions{Left: 1, Right: 1})
+
 			// header
-			vbh := vb.Styled(m.styles.Header).PaddingLeft(1)
-			for i, col := range m.Table.Columns() {
-				vbh.WriteLine(runewidth.Truncate(col.Title, m.widths[i], "…"))
-				vbh = vbh.PaddingLeft(m.widths[i]).PaddingLeft(_gap)
+			vbh := vb.Row(0).Styled(m.styles.Header)
+			for _, p := range m.placed {
+				id := m.id + "-" + strconv.Itoa(p.col)
+				vbh.Sub(tea.Rectangle{Left: p.x + p.width, Width: _gap}).Zone(m.id + "-sep-" + strconv.Itoa(p.col))
+				vbCell := vbh.Sub(tea.Rectangle{Left: p.x, Width: p.width}).Zone(id)
+				if p.pos == m.column {
+					vbCell = vbCell.Styled(m.styles.FocusedHeader)
+				}
+				writeAligned(vbCell, headers[p.pos], m.columns[p.col].Align)
 			}
 
 			// split line
 			vb.Row(1).Styled(styles.Style{}.Foreground(styles.FgColor("240"))).Fill(box.NormalBorder.Top)
 
-			// rows
-			m.viewport.View(vb.PaddingTop(2), func(vbRow tea.Viewbox, i int) {
-				if i == m.Table.Cursor() {
+			// rows, marker of marked row is drawn on the left margin
+			m.viewport.View(vbBox.PaddingTop(2), func(vbRow tea.Viewbox, i int) {
+				if i >= len(rows) {
+					return
+				}
+
+				selected := i == m.Table.Cursor()
+				vbRow = vbRow.Styled(m.styles.Cell)
+				if m.Table.IsMarked(i) {
+					vbRow = vbRow.Styled(m.styles.Marked)
+					vbRow.Set(0, 0, _marker)
+				}
+				if selected {
 					vbRow = vbRow.Styled(m.styles.Selected)
 				}
+				vbRow = vbRow.Padding(tea.PaddingOptions{Left: 1, Right: 1})
 
-				vbRow = vbRow.PaddingLeft(1)
-				for i, value := range m.Table.Rows()[i] {
-					vbRow.WriteLine(m.styles.Cell.Render(runewidth.Truncate(value, m.widths[i], "…")))
-					vbRow = vbRow.PaddingLeft(m.widths[i]).PaddingLeft(_gap)
+				for _, p := range m.placed {
+					col := m.columns[p.col]
+					vbCell := vbRow.Sub(tea.Rectangle{Left: p.x, Width: p.width}).Styled(col.Style)
+					if m.IsDirty(i, p.col) {
+						vbCell = vbCell.Styled(m.styles.Dirty)
+					}
+					if selected {
+						// selection is not hidden by column style
+						vbCell = vbCell.Styled(m.styles.Selected)
+						if p.pos == m.column {
+							vbCell = vbCell.Styled(m.styles.SelectedCell)
+						}
+					}
+					switch {
+					case selected && m.editing && p.pos == m.column:
+						// 1 for cursor
+						m.editor.Width = max(p.width-1, 1)
+						m.editor.View(vbCell)
+					case col.Render != nil:
+						col.Render(vbCell, rows[i])
+					default:
+						writeAligned(vbCell, col.Get(rows[i]), col.Align)
+					}
 				}
 			})
 		},
@@ -1,13 +1,20 @@
 package table
 
 import (
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/rprtr258/assert"
+
+	"github.com/rprtr258/tea"
 )
 
 func TestFromValues(t *testing.T) {
-	table := New([]Column{{Title: "Foo"}, {Title: "Bar"}}, []int{0, 0},
+	table := New([]Column[[]string]{
+		{Title: "Foo", Get: func(row []string) string { return row[0] }},
+		{Title: "Bar", Get: func(row []string) string { return row[1] }},
+	},
 		[][]string{
 			{"foo1", "bar1"},
 			{"foo2", "bar2"},
@@ -22,14 +29,327 @@ func TestFromValues(t *testing.T) {
 }
 
 func TestFromValuesWithTabSeparator(t *testing.T) {
-	table := New([]Column{{Title: "Foo"}, {Title: "Bar"}}, []int{0, 0},
+	table := New([]Column[[]string]{
+		{Title: "Foo", Get: func(row []string) string { return row[0] }},
+		{Title: "Bar", Get: func(row []string) string { return row[1] }},
+	},
 		[][]string{
 			{"foo1.", "bar1"},
 			{"foo,bar,baz", "bar,2"},
-		}, 0, 0, DefaultStyles, DefaultKeyMap)
+		}, 2, 0, DefaultStyles, DefaultKeyMap)
 
 	assert.Equal(t, [][]string{
 		{"foo1.", "bar1"},
 		{"foo,bar,baz", "bar,2"},
 	}, table.Table.Rows())
+
+	vb := tea.NewViewbox(6, 22)
+	table.View(vb)
+	// separators in values are kept as is
+	assert.Equal(t, []string{
+		"┌────────────────────┐",
+		"│ Foo          Bar   │",
+		"│ ────────────────── │",
+		"│ foo1.        bar1  │",
+		"│ foo,bar,baz  bar,2 │",
+		"└────────────────────┘",
+	}, vb.Lines())
+}
+
+type city struct {
+	Name       string
+	Population int
+}
+
+func TestView(t *testing.T) {
+	table := New([]Column[city]{
+		{Title: "City", Get: func(c city) string { return c.Name }, Flex: 1, MinWidth: 8},
+		{Title: "Pop", Get: func(c city) string { return strconv.Itoa(c.Population) }, Align: AlignRight},
+		{Title: "Size", Get: func(city) string { return "" }, Render: func(vb tea.Viewbox, c city) {
+			if c.Population > 1000 {
+				vb.WriteLine("big")
+			}
+		}},
+	}, []city{
+		{"Tokyo", 37274},
+		{"Lima", 110},
+	}, 2, 0, DefaultStyles, DefaultKeyMap)
+
+	vb := tea.NewViewbox(6, 30)
+	table.View(vb)
+	assert.Equal(t, []string{
+		"┌────────────────────────────┐",
+		"│ City             Pop  Size │",
+		"│ ────────────────────────── │",
+		"│ Tokyo          37274  big  │",
+		"│ Lima             110       │",
+		"└────────────────────────────┘",
+	}, vb.Lines())
+}
+
+func TestSortFilter(t *testing.T) {
+	f := func(...tea.Cmd) {}
+	keys := func(m *Model[city], keys ...string) {
+		for _, k := range keys {
+			m.Update(tea.MsgKey{Type: tea.KeyRunes, Runes: []rune(k)}, f)
+		}
+	}
+	names := func(m *Model[city]) []string {
+		var res []string
+		for _, c := range m.Table.Rows() {
+			res = append(res, c.Name)
+		}
+		return res
+	}
+
+	table := New([]Column[city]{
+		{Title: "City", Get: func(c city) string { return c.Name }},
+		{Title: "Pop", Get: func(c city) string { return strconv.Itoa(c.Population) }, Cmp: func(a, b city) int {
+			return a.Population - b.Population
+		}},
+	}, []city{
+		{"Tokyo", 37},
+		{"Lima", 11},
+		{"Delhi", 32},
+		{"Lagos", 11},
+	}, 4, 0, DefaultStyles, DefaultKeyMap)
+
+	// cursor stays on the same row after sort
+	table.SetCursor(1)
+	keys(&table, "s")
+	assert.Equal(t, []string{"Delhi", "Lagos", "Lima", "Tokyo"}, names(&table))
+	assert.Equal(t, "City ▲", table.header(0))
+	selected, _ := table.SelectedRow()
+	assert.Equal(t, "Lima", selected.Name)
+
+	keys(&table, "s")
+	assert.Equal(t, []string{"Tokyo", "Lima", "Lagos", "Delhi"}, names(&table))
+
+	// multi-column sort
+	keys(&table, "l", "s", "h", "S")
+	assert.Equal(t, []string{"Lagos", "Lima", "Delhi", "Tokyo"}, names(&table))
+	assert.Equal(t, "Pop ▲1", table.header(1))
+	assert.Equal(t, "City ▲2", table.header(0))
+
+	keys(&table, "s", "s")
+	assert.Equal(t, 0, len(table.Table.SortKeys()))
+
+	// global and per-column filter
+	keys(&table, "/", "i")
+	assert.Equal(t, []string{"Lima", "Delhi"}, names(&table))
+	table.Update(tea.MsgKey{Type: tea.KeyEnter}, f)
+	assert.False(t, table.Filtering())
+	table.SetFilter("pop:3")
+	assert.Equal(t, []string{"Tokyo", "Delhi"}, names(&table))
+
+	// hidden columns are not matched by global terms
+	keys(&table, "x")
+	assert.Equal(t, []int{1}, table.ColumnOrder())
+	table.SetFilter("tokyo")
+	assert.Equal(t, []string(nil), names(&table))
+	_, ok := table.SelectedRow()
+	assert.False(t, ok)
+
+	keys(&table, "X", ">")
+	assert.Equal(t, []int{1, 0}, table.ColumnOrder())
+	table.SetFilter("")
+	assert.Equal(t, 4, table.Table.RowsCount())
+}
+
+func TestHorizontalScroll(t *testing.T) {
+	f := func(...tea.Cmd) {}
+	keys := func(m *Model[[]string], keys ...string) {
+		for _, k := range keys {
+			m.Update(tea.MsgKey{Type: tea.KeyRunes, Runes: []rune(k)}, f)
+		}
+	}
+	render := func(m *Model[[]string]) []string {
+		vb := tea.NewViewbox(5, 24)
+		m.View(vb)
+		return vb.Lines()[1:4]
+	}
+
+	var columns []Column[[]string]
+	for i, title := range []string{"ID", "Alpha", "Beta", "Gamma", "Delta"} {
+		columns = append(columns, Column[[]string]{Title: title, Get: func(row []string) string { return row[i] }})
+	}
+	table := New(columns, [][]string{
+		{"1", "a", "b", "c", "d"},
+	}, 1, 0, DefaultStyles, DefaultKeyMap)
+	table.Frozen = 1
+
+	assert.Equal(t, []string{
+		"│ ID  Alpha  Beta  Ga… │",
+		"│ ──────────────────── │",
+		"│ 1   a      b     c   │",
+	}, render(&table))
+
+	// frozen column stays while scrolling to the focused one
+	keys(&table, "$")
+	assert.Equal(t, []string{
+		"│ ID  Gamma  Delta │",
+		"│ ──────────────── │",
+		"│ 1   c      d     │",
+	}, render(&table))
+
+	// narrowing focused column makes room for previous one
+	keys(&table, "-", "-")
+	assert.Equal(t, []string{
+		"│ ID  Beta  Gamma  De… │",
+		"│ ──────────────────── │",
+		"│ 1   b     c      d   │",
+	}, render(&table))
+
+	// events are sent as program does: mouse event, then zone one, and
+	// dragging is reported as repeated presses
+	mouse := func(x int, typ tea.MouseEventType, zone string) {
+		table.Update(tea.MsgMouse{X: x, Type: typ}, f)
+		if zone != "" {
+			table.Update(tea.MsgMouseZone{ID: table.id + "-" + zone, Event: tea.MouseEvent{X: x, Type: typ}}, f)
+		}
+	}
+
+	// dragging header separator resizes column, headers dragged over are
+	// not clicked
+	mouse(5, tea.MouseLeft, "sep-0")
+	mouse(4, tea.MouseLeft, "0")
+	mouse(3, tea.MouseLeft, "0")
+	mouse(8, tea.MouseMotion, "")
+	mouse(8, tea.MouseRelease, "1")
+	assert.Equal(t, 5, table.columnWidth(0))
+	assert.Equal(t, 0, len(table.Table.SortKeys()))
+
+	// sort on press only
+	mouse(3, tea.MouseLeft, "0")
+	mouse(3, tea.MouseLeft, "0")
+	mouse(3, tea.MouseRelease, "0")
+	assert.Equal(t, 1, len(table.Table.SortKeys()))
+}
+
+func TestMarks(t *testing.T) {
+	f := func(...tea.Cmd) {}
+	press := func(m *Model[city], keys ...tea.MsgKey) {
+		for _, k := range keys {
+			m.Update(k, f)
+		}
+	}
+	names := func(cities []city) []string {
+		var res []string
+		for _, c := range cities {
+			res = append(res, c.Name)
+		}
+		return res
+	}
+	space := tea.MsgKey{Type: tea.KeySpace, Runes: []rune{' '}}
+
+	table := New([]Column[city]{
+		{Title: "City", Get: func(c city) string { return c.Name }},
+	}, []city{
+		{"Tokyo", 37},
+		{"Lima", 11},
+		{"Delhi", 32},
+		{"Lagos", 11},
+	}, 4, 0, DefaultStyles, DefaultKeyMap)
+
+	press(&table, space, tea.MsgKey{Type: tea.KeyDown}, tea.MsgKey{Type: tea.KeyShiftDown})
+	assert.Equal(t, []string{"Tokyo", "Lima", "Delhi"}, names(table.MarkedRows()))
+	assert.Equal(t, 2, table.Cursor())
+
+	// marks stay on rows after sort, returned in display order
+	table.CycleSort(0, false)
+	assert.Equal(t, []string{"Delhi", "Lima", "Tokyo"}, names(table.MarkedRows()))
+
+	press(&table, tea.MsgKey{Type: tea.KeyRunes, Runes: []rune("*")})
+	assert.Equal(t, []string{"Lagos"}, names(table.MarkedRows()))
+
+	// mark all rows matching filter
+	table.SetFilter("l")
+	press(&table, tea.MsgKey{Type: tea.KeyCtrlA})
+	assert.Equal(t, []string{"Delhi", "Lagos", "Lima"}, names(table.MarkedRows()))
+	press(&table, tea.MsgKey{Type: tea.KeyCtrlA})
+	assert.Equal(t, []string(nil), names(table.MarkedRows()))
+}
+
+func TestEdit(t *testing.T) {
+	// cursor blinking commands are skipped, commands made on enter are run
+	var msgs []tea.Msg
+	f := func(...tea.Cmd) {}
+	press := func(m *Model[city], keys ...string) {
+		for _, k := range keys {
+			f := f
+			if k == "enter" {
+				f = func(cmds ...tea.Cmd) {
+					for _, cmd := range cmds {
+						msgs = append(msgs, cmd())
+					}
+				}
+			}
+
+			switch k {
+			case "enter":
+				m.Update(tea.MsgKey{Type: tea.KeyEnter}, f)
+			case "esc":
+				m.Update(tea.MsgKey{Type: tea.KeyEsc}, f)
+			case "backspace":
+				m.Update(tea.MsgKey{Type: tea.KeyBackspace}, f)
+			default:
+				m.Update(tea.MsgKey{Type: tea.KeyRunes, Runes: []rune(k)}, f)
+			}
+		}
+	}
+
+	table := New([]Column[city]{
+		{Title: "City", Get: func(c city) string { return c.Name }},
+		{
+			Title: "Population",
+			Get:   func(c city) string { return strconv.Itoa(c.Population) },
+			Cmp:   func(a, b city) int { return a.Population - b.Population },
+			Set: func(c city, value string) (city, error) {
+				n, err := strconv.Atoi(value)
+				c.Population = n
+				return c, err
+			},
+			Validate: func(value string) error {
+				if strings.Trim(value, "0123456789") != "" {
+					return strconv.ErrSyntax
+				}
+				return nil
+			},
+		},
+	}, []city{
+		{"Tokyo", 37},
+		{"Lima", 11},
+	}, 4, 0, DefaultStyles, DefaultKeyMap)
+
+	// column without Set is not editable
+	press(&table, "e")
+	assert.False(t, table.Editing())
+
+	table.CycleSort(1, false)
+	press(&table, "k", "l", "e", "backspace", "backspace", "x")
+	assert.True(t, table.Editing())
+	assert.Equal(t, "", table.editor.Value())
+
+	// empty value is not committed
+	press(&table, "enter")
+	assert.True(t, table.Editing())
+	assert.Equal(t, 0, len(msgs))
+
+	// row is sorted again, cursor follows it
+	press(&table, "40", "enter")
+	assert.False(t, table.Editing())
+	assert.Equal(t, []tea.Msg{MsgCellEdited[city]{Row: 1, Column: 1, Value: "40", Updated: city{"Lima", 40}}}, msgs)
+	row, _ := table.SelectedRow()
+	assert.Equal(t, city{"Lima", 40}, row)
+	assert.Equal(t, 1, table.Cursor())
+	assert.True(t, table.IsDirty(1, 1))
+	assert.False(t, table.IsDirty(0, 1))
+
+	press(&table, "e", "1", "esc")
+	row, _ = table.SelectedRow()
+	assert.Equal(t, city{"Lima", 40}, row)
+	assert.Equal(t, 1, len(msgs))
+
+	table.ClearDirty()
+	assert.False(t, table.IsDirty(1, 1))
 }
@@ -22,7 +22,7 @@ func SpanTitle(title string, size tea.Layout, children ...node) node {
 }
 
 func Title(title string) node {
-	return node{title: title, size: 0, children: nil}
+	return node{title: title, size: tea.Flex(0), children: nil}
 }
 
 func Span(size tea.Layout, children ...node) node {
@@ -0,0 +1,412 @@
+package textarea
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/rprtr258/tea"
+	"github.com/rprtr258/tea/components/headless/history"
+	"github.com/rprtr258/tea/components/key"
+)
+
+// cursorState is position of a cursor along with its selection.
+type cursorState struct {
+	row, col       int
+	lastCharOffset int
+	anchor         position
+	selecting      bool
+}
+
+func (s cursorState) position() position {
+	return position{s.row, s.col}
+}
+
+// selectionRange returns ordered bounds of selection, if it is not empty.
+func (s cursorState) selectionRange() (start, end position, ok bool) {
+	cursor := s.position()
+	switch {
+	case !s.selecting || s.anchor == cursor:
+		return position{}, position{}, false
+	case s.anchor.less(cursor):
+		return s.anchor, cursor, true
+	default:
+		return cursor, s.anchor, true
+	}
+}
+
+// state returns main cursor state.
+func (m *Model) state() cursorState {
+	return cursorState{
+		row:            m.row,
+		col:            m.col,
+		lastCharOffset: m.lastCharOffset,
+		anchor:         m.anchor,
+		selecting:      m.selecting,
+	}
+}
+
+// setState sets main cursor state.
+func (m *Model) setState(s cursorState) {
+	m.row, m.col = s.row, s.col
+	m.lastCharOffset = s.lastCharOffset
+	m.anchor, m.selecting = s.anchor, s.selecting
+}
+
+// CursorCount returns number of cursors, including the main one.
+func (m *Model) CursorCount() int {
+	return 1 + len(m.cursors)
+}
+
+// ClearCursors removes all cursors except the main one.
+func (m *Model) ClearCursors() {
+	m.cursors = nil
+}
+
+// hasCursor checks whether there is a cursor, main or additional one, at
+// given position.
+func (m *Model) hasCursor(p position) bool {
+	return p == position{m.row, m.col} || m.hasExtraCursor(p)
+}
+
+// hasExtraCursor checks whether there is an additional cursor at given
+// position.
+func (m *Model) hasExtraCursor(p position) bool {
+	for _, c := range m.cursors {
+		if c.position() == p {
+			return true
+		}
+	}
+	return false
+}
+
+// addCursor adds cursor unless there is one at the same position already.
+func (m *Model) addCursor(s cursorState) {
+	if !m.hasCursor(s.position()) {
+		m.cursors = append(m.cursors, s)
+	}
+}
+
+// addCursorVertical adds cursor on the line above or below the outermost
+// cursor in given direction.
+func (m *Model) addCursorVertical(dir int) {
+	from := m.state()
+	for _, c := range m.cursors {
+		if dir < 0 && c.row < from.row || dir > 0 && c.row > from.row {
+			from = c
+		}
+	}
+
+	row := from.row + dir
+	if row < 0 || row >= m.value.Len() {
+		return
+	}
+
+	m.addCursor(cursorState{
+		row: row,
+		col: min(from.col, len(m.value.Line(row))),
+	})
+}
+
+// isWordRune checks whether rune is part of word for occurrences search.
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+// addCursorNextOccurrence selects word under the main cursor if nothing is
+// selected, otherwise adds cursor selecting next occurrence of selected
+// text after the last cursor.
+func (m *Model) addCursorNextOccurrence() {
+	start, end, ok := m.selectionRange()
+	if !ok {
+		line := m.currentLine()
+		from, to := min(m.col, len(line)), min(m.col, len(line))
+		for from > 0 && isWordRune(line[from-1]) {
+			from--
+		}
+		for to < len(line) && isWordRune(line[to]) {
+			to++
+		}
+		if from == to {
+			return
+		}
+
+		m.anchor, m.selecting = position{m.row, from}, true
+		m.col = to
+		return
+	}
+	if start.row != end.row {
+		return
+	}
+
+	needle := m.value.Line(start.row)[start.col:end.col]
+
+	// search starts after the last cursor and wraps around
+	last := end
+	for _, c := range m.cursors {
+		if p := c.position(); last.less(p) {
+			last = p
+		}
+	}
+
+	for i := range m.value.Len() + 1 {
+		row := (last.row + i) % m.value.Len()
+		line := m.value.Line(row)
+		from := 0
+		if i == 0 {
+			from = last.col
+		}
+
+		for col := from; col+len(needle) <= len(line); col++ {
+			if !slices.Equal(line[col:col+len(needle)], needle) {
+				continue
+			}
+
+			p := position{row, col + len(needle)}
+			if m.hasCursor(p) {
+				// all occurrences have cursors
+				return
+			}
+
+			m.addCursor(cursorState{
+				row:       row,
+				col:       p.col,
+				anchor:    position{row, col},
+				selecting: true,
+			})
+			return
+		}
+	}
+}
+
+// span returns range of text covered by cursor: its selection or its
+// position.
+func (s cursorState) span() (start, end position) {
+	if start, end, ok := s.selectionRange(); ok {
+		return start, end
+	}
+	return s.position(), s.position()
+}
+
+// cursorEntry is a cursor state along with whether it is the main cursor.
+type cursorEntry struct {
+	state cursorState
+	main  bool
+}
+
+// mergeCursors clamps cursors to text and merges ones with overlapping
+// selections, or at the same position, or at a bound of other's selection,
+// so that edit at one cursor does not touch text of another. Merged cursor
+// selects union of selections, it is the main one if any of merged is.
+// Returns cursors ordered from the end of text.
+func (m *Model) mergeCursors() []cursorEntry {
+	clampPosition := func(p position) position {
+		row := clamp(p.row, 0, m.value.Len()-1)
+		return position{row, clamp(p.col, 0, len(m.value.Line(row)))}
+	}
+
+	entries := []cursorEntry{{m.state(), true}}
+	for _, c := range m.cursors {
+		entries = append(entries, cursorEntry{c, false})
+	}
+	for i, e := range entries {
+		p := clampPosition(e.state.position())
+		entries[i].state.row, entries[i].state.col = p.row, p.col
+		entries[i].state.anchor = clampPosition(e.state.anchor)
+	}
+	slices.SortFunc(entries, func(a, b cursorEntry) int {
+		aStart, _ := a.state.span()
+		bStart, _ := b.state.span()
+		switch {
+		case aStart == bStart:
+			return 0
+		case aStart.less(bStart):
+			return -1
+		default:
+			return 1
+		}
+	})
+
+	merged := entries[:1]
+	for _, e := range entries[1:] {
+		last := &merged[len(merged)-1]
+		lastStart, lastEnd := last.state.span()
+		start, end := e.state.span()
+		if lastEnd.less(start) || lastEnd == start && lastStart != lastEnd && start != end {
+			// disjoint, adjacent selections are kept apart too
+			merged = append(merged, e)
+			continue
+		}
+
+		if lastEnd.less(end) {
+			lastEnd = end
+		}
+		s := last.state
+		if e.main {
+			s = e.state
+		}
+		if lastStart != lastEnd {
+			// cursor stays at the same side of the selection
+			p, anchor := lastEnd, lastStart
+			if begin, _ := s.span(); s.position() == begin && s.selecting {
+				p, anchor = lastStart, lastEnd
+			}
+			s.row, s.col = p.row, p.col
+			s.anchor, s.selecting = anchor, true
+		}
+		last.state, last.main = s, last.main || e.main
+	}
+
+	slices.Reverse(merged)
+	m.cursors = nil
+	for _, e := range merged {
+		if e.main {
+			m.setState(e.state)
+		} else {
+			m.cursors = append(m.cursors, e.state)
+		}
+	}
+	return merged
+}
+
+// forEachCursor calls edit for each cursor, set as the main one. Cursors are
+// merged first, then visited from the end of text, so that edit does not
+// move text before cursors not visited yet. Cursors visited already are kept
+// as positions relative to the end of text, which edits before them do not
+// change.
+func (m *Model) forEachCursor(edit func()) {
+	entries := m.mergeCursors()
+
+	fromEnd := func(p position) position {
+		return position{m.value.Len() - 1 - p.row, len(m.value.Line(p.row)) - p.col}
+	}
+	fromStart := func(p position) position {
+		row := clamp(m.value.Len()-1-p.row, 0, m.value.Len()-1)
+		length := len(m.value.Line(row))
+		return position{row, clamp(length-p.col, 0, length)}
+	}
+
+	for i, e := range entries {
+		m.setState(e.state)
+		edit()
+
+		s := m.state()
+		p := fromEnd(s.position())
+		s.row, s.col = p.row, p.col
+		s.anchor = fromEnd(s.anchor)
+		entries[i].state = s
+	}
+
+	m.cursors = nil
+	for _, e := range entries {
+		s := e.state
+		p := fromStart(s.position())
+		s.row, s.col = p.row, p.col
+		s.anchor = fromStart(s.anchor)
+
+		if e.main {
+			m.setState(s)
+		} else {
+			m.cursors = append(m.cursors, s)
+		}
+	}
+	m.mergeCursors()
+}
+
+// cursorsInOrder returns all cursors states in order of their positions.
+func (m *Model) cursorsInOrder() []cursorState {
+	res := append([]cursorState{m.state()}, m.cursors...)
+	slices.SortFunc(res, func(a, b cursorState) int {
+		switch {
+		case a.position() == b.position():
+			return 0
+		case a.position().less(b.position()):
+			return -1
+		default:
+			return 1
+		}
+	})
+	return res
+}
+
+// selections returns text selected by each cursor joined with newlines.
+func (m *Model) selections() string {
+	texts := []string{}
+	for _, c := range m.cursorsInOrder() {
+		if start, end, ok := c.selectionRange(); ok {
+			texts = append(texts, m.textBetween(start, end))
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// updateCursors handles key when there are several cursors, returns kind of
+// edit made.
+func (m *Model) updateCursors(msg tea.MsgKey, f func(...tea.Cmd)) history.EditKind {
+	switch {
+	case key.Matches(msg, m.KeyMap.ClearCursors):
+		m.ClearCursors()
+	case key.Matches(msg, m.KeyMap.Undo, m.KeyMap.Redo, m.KeyMap.Paste):
+		// pasted text is inserted at each cursor once it arrives
+		kind, _ := m.updateKey(msg, f)
+		return kind
+	case key.Matches(msg, m.KeyMap.Copy, m.KeyMap.Cut):
+		text := m.selections()
+		if text == "" {
+			break
+		}
+
+		f(copyToClipboard(text))
+		if key.Matches(msg, m.KeyMap.Cut) {
+			m.forEachCursor(m.deleteSelection)
+		}
+	default:
+		kind := history.EditOther
+		m.forEachCursor(func() {
+			if !m.updateSelection(msg, f) {
+				kind, _ = m.updateKey(msg, f)
+			}
+		})
+		return kind
+	}
+	return history.EditOther
+}
+
+// paste inserts text at each cursor. If there are as many lines in text as
+// cursors, each cursor gets its own line.
+func (m *Model) paste(text string) {
+	lines := strings.Split(text, "\n")
+	distribute := len(m.cursors) > 0 && len(lines) == m.CursorCount()
+
+	// cursors are visited from the last one
+	i := len(lines)
+	m.forEachCursor(func() {
+		m.deleteSelection()
+		if distribute {
+			i--
+			m.insertRunesFromUserInput([]rune(lines[i]))
+		} else {
+			m.insertRunesFromUserInput([]rune(text))
+		}
+	})
+}
+
+// blockSelect selects block of text between anchor and given position, with
+// a cursor on each line.
+func (m *Model) blockSelect(anchor, p position) {
+	m.cursors = nil
+	for row := min(anchor.row, p.row); row <= max(anchor.row, p.row); row++ {
+		length := len(m.value.Line(row))
+		s := cursorState{
+			row:       row,
+			col:       min(p.col, length),
+			anchor:    position{row, min(anchor.col, length)},
+			selecting: true,
+		}
+		if row == p.row {
+			m.setState(s)
+		} else {
+			m.cursors = append(m.cursors, s)
+		}
+	}
+}
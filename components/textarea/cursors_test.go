@@ -0,0 +1,101 @@
+package textarea
+
+import (
+	"testing"
+
+	"github.com/rprtr258/assert"
+
+	"github.com/rprtr258/tea"
+)
+
+func TestMultipleCursors(t *testing.T) {
+	f := func(...tea.Cmd) {}
+	runes := func(s string) tea.MsgKey {
+		return tea.MsgKey{Type: tea.KeyRunes, Runes: []rune(s)}
+	}
+
+	m := New()
+	m.Focus()
+	m.SetValue("a = 1\nbb = 2\nc = 3")
+	m.row, m.col = 0, 1
+
+	m.Update(tea.MsgKey{Type: tea.KeyCtrlShiftDown}, f)
+	m.Update(tea.MsgKey{Type: tea.KeyCtrlShiftDown}, f)
+	assert.Equal(t, 3, m.CursorCount())
+
+	// typing and deleting is done at each cursor
+	m.Update(runes(":"), f)
+	m.Update(runes("x"), f)
+	assert.Equal(t, "a:x = 1\nb:xb = 2\nc:x = 3", m.Value())
+	m.Update(tea.MsgKey{Type: tea.KeyBackspace}, f)
+	assert.Equal(t, "a: = 1\nb:b = 2\nc: = 3", m.Value())
+
+	// each cursor gets its own line of pasted text
+	m.Update(msgPaste("1\n2\n3"), f)
+	assert.Equal(t, "a:1 = 1\nb:2b = 2\nc:3 = 3", m.Value())
+
+	m.Update(tea.MsgKey{Type: tea.KeyEsc}, f)
+	assert.Equal(t, 1, m.CursorCount())
+	m.Undo()
+	assert.Equal(t, "a: = 1\nb:b = 2\nc: = 3", m.Value())
+}
+
+func TestCursorsNextOccurrence(t *testing.T) {
+	f := func(...tea.Cmd) {}
+	next := tea.MsgKey{Type: tea.KeyRunes, Runes: []rune("n"), Alt: true}
+
+	m := New()
+	m.Focus()
+	m.SetValue("foo bar\nfoo foo")
+	m.row, m.col = 0, 1
+
+	// word under cursor is selected first, then its occurrences
+	m.Update(next, f)
+	assert.Equal(t, "foo", m.Selection())
+	m.Update(next, f)
+	m.Update(next, f)
+	m.Update(next, f)
+	assert.Equal(t, 3, m.CursorCount())
+
+	m.Update(tea.MsgKey{Type: tea.KeyRunes, Runes: []rune("qux")}, f)
+	assert.Equal(t, "qux bar\nqux qux", m.Value())
+}
+
+func TestBlockSelection(t *testing.T) {
+	m := New()
+	m.Focus()
+	m.SetValue("abcd\nef\nghij")
+
+	m.blockSelect(position{0, 1}, position{2, 3})
+	assert.Equal(t, 3, m.CursorCount())
+	assert.Equal(t, "bc\nf\nhi", m.selections())
+
+	m.Update(tea.MsgKey{Type: tea.KeyDelete}, func(...tea.Cmd) {})
+	assert.Equal(t, "ad\ne\ngj", m.Value())
+}
+
+func TestCursorsOverlappingSelection(t *testing.T) {
+	f := func(...tea.Cmd) {}
+	next := tea.MsgKey{Type: tea.KeyRunes, Runes: []rune("n"), Alt: true}
+
+	// cursor below main selection is at its end
+	m := New()
+	m.Focus()
+	m.SetValue("ab ab\nab")
+	m.Update(tea.MsgKey{Type: tea.KeyShiftUp}, f)
+	m.Update(tea.MsgKey{Type: tea.KeyCtrlDown, Alt: true}, f)
+	m.Update(tea.MsgKey{Type: tea.KeyBackspace}, f)
+	assert.Equal(t, "ab", m.Value())
+	assert.Equal(t, 1, m.CursorCount())
+
+	// selections of cursors are extended over each other
+	m = New()
+	m.Focus()
+	m.SetValue("ab ab\nab")
+	m.Update(next, f)
+	m.Update(next, f)
+	m.Update(tea.MsgKey{Type: tea.KeyShiftRight}, f)
+	m.Update(tea.MsgKey{Type: tea.KeyShiftUp}, f)
+	m.Update(tea.MsgKey{Type: tea.KeyEnter}, f)
+	assert.Equal(t, 1, m.CursorCount())
+}
@@ -0,0 +1,133 @@
+package textarea
+
+import (
+	"cmp"
+	"strings"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/lexers"
+
+	"github.com/rprtr258/tea/components/markdown/ansi"
+	"github.com/rprtr258/tea/styles"
+)
+
+// DefaultHighlightStyles are styles of tokens used by highlighter by default,
+// the same as of code blocks in markdown.
+var DefaultHighlightStyles = ansi.HighlightStyles(ansi.ChromaStyle("textarea", ansi.DarkChroma))
+
+// Highlighter colors text of text area according to syntax of a language.
+// Only lines around visible ones are tokenized, tokens are kept until these
+// lines are changed, so editing large buffers stays cheap.
+type Highlighter struct {
+	// Styles of tokens. Tokens missing from map are styled as their
+	// subcategory or category, e.g. LiteralStringDouble as LiteralString.
+	Styles map[chroma.TokenType]styles.Style
+
+	// Context is number of lines before first visible line which are
+	// tokenized too, so that lexer state is right for visible lines, e.g.
+	// inside multiline comment started above them.
+	Context int
+
+	lexer chroma.Lexer
+
+	// tokenized lines, starting from line from, and type of token for each
+	// rune of them
+	from   int
+	lines  [][]rune
+	tokens [][]chroma.TokenType
+}
+
+// NewHighlighter creates highlighter for language given by name, alias or
+// file name, e.g. "go", "python" or "main.rs". Unknown languages are not
+// highlighted.
+func NewHighlighter(language string) *Highlighter {
+	return &Highlighter{
+		Styles:  DefaultHighlightStyles,
+		Context: 100,
+		lexer:   chroma.Coalesce(cmp.Or(lexers.Get(language), lexers.Fallback)),
+	}
+}
+
+// sameLine checks whether lines are the same slice. Rope never modifies
+// lines, so same slice means same text and is much cheaper to check.
+func sameLine(a, b []rune) bool {
+	return len(a) == len(b) && (len(a) == 0 || &a[0] == &b[0])
+}
+
+// tokenize lines from first to last inclusive unless they are tokenized
+// already.
+func (h *Highlighter) tokenize(value rope, first, last int) {
+	from := max(0, first-h.Context)
+
+	lines := make([][]rune, 0, last-from+1)
+	value.Each(from, func(i int, line []rune) bool {
+		lines = append(lines, line)
+		return i < last
+	})
+
+	if h.from == from && len(h.lines) >= len(lines) {
+		cached := true
+		for i, line := range lines {
+			if !sameLine(h.lines[i], line) {
+				cached = false
+				break
+			}
+		}
+		if cached {
+			return
+		}
+	}
+
+	h.from, h.lines = from, lines
+	h.tokens = make([][]chroma.TokenType, len(lines))
+	for i, line := range lines {
+		h.tokens[i] = make([]chroma.TokenType, len(line))
+	}
+
+	text := make([]string, len(lines))
+	for i, line := range lines {
+		text[i] = string(line)
+	}
+
+	it, err := h.lexer.Tokenise(nil, strings.Join(text, "\n"))
+	if err != nil {
+		return
+	}
+
+	row, col := 0, 0
+	for token := it(); token != chroma.EOF && row < len(lines); token = it() {
+		for _, r := range token.Value {
+			if r == '\n' {
+				row, col = row+1, 0
+				if row == len(lines) {
+					break
+				}
+				continue
+			}
+
+			if col < len(h.tokens[row]) {
+				h.tokens[row][col] = token.Type
+			}
+			col++
+		}
+	}
+}
+
+// line returns token types of runes of given line, or nil if line was not
+// tokenized.
+func (h *Highlighter) line(i int) []chroma.TokenType {
+	if i < h.from || i >= h.from+len(h.tokens) {
+		return nil
+	}
+	return h.tokens[i-h.from]
+}
+
+// style of token of given type.
+func (h *Highlighter) style(t chroma.TokenType) styles.Style {
+	for _, t := range []chroma.TokenType{t, t.SubCategory(), t.Category()} {
+		if st, ok := h.Styles[t]; ok {
+			return st
+		}
+	}
+	return styles.Style{}
+}
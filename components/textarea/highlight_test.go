@@ -0,0 +1,41 @@
+package textarea
+
+import (
+	"testing"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/rprtr258/assert"
+)
+
+func TestHighlighter(t *testing.T) {
+	h := NewHighlighter("go")
+	h.Context = 1
+	r := newRope(80,
+		[]rune("/* a"),
+		[]rune("b */"),
+		[]rune("x := 1"),
+		[]rune(`y := "s"`),
+	)
+
+	// first line is tokenized as context, so second one is known to be in comment
+	h.tokenize(r, 1, 2)
+	assert.Equal(t, chroma.CommentMultiline, h.line(0)[0])
+	assert.Equal(t, chroma.CommentMultiline, h.line(1)[0])
+	assert.Equal(t, chroma.LiteralNumberInteger, h.line(2)[5])
+	assert.Equal(t, true, h.line(3) == nil)
+
+	// nothing changed, tokens are reused
+	tokens := h.line(2)
+	h.tokenize(r, 1, 2)
+	assert.Equal(t, &tokens[0], &h.line(2)[0])
+
+	// line changed, lines are tokenized again
+	r = r.Set(0, []rune("// a"))
+	h.tokenize(r, 1, 2)
+	assert.Equal(t, chroma.NameOther, h.line(1)[0])
+	assert.Equal(t, chroma.Operator, h.line(1)[3])
+	assert.Equal(t, chroma.CommentSingle, h.line(0)[0])
+
+	assert.Equal(t, DefaultHighlightStyles[chroma.LiteralString], h.style(chroma.LiteralStringDouble))
+	assert.Equal(t, DefaultHighlightStyles[chroma.Comment], h.style(chroma.CommentMultiline))
+}
@@ -0,0 +1,296 @@
+package textarea
+
+import (
+	"math/rand/v2"
+	"slices"
+	"strings"
+
+	rw "github.com/mattn/go-runewidth"
+)
+
+// node of rope, which is a treap keyed implicitly by line index. Nodes are
+// never changed after construction, so ropes share them freely.
+type node struct {
+	left, right *node
+	priority    uint32
+
+	line      []rune
+	lineWidth int
+	// rows is number of rows line takes when soft-wrapped
+	rows int
+
+	// totals over subtree
+	count  int // lines
+	width  int // display width of lines
+	height int // soft-wrapped rows
+}
+
+func (n *node) getCount() int {
+	if n == nil {
+		return 0
+	}
+	return n.count
+}
+
+func (n *node) getWidth() int {
+	if n == nil {
+		return 0
+	}
+	return n.width
+}
+
+func (n *node) getHeight() int {
+	if n == nil {
+		return 0
+	}
+	return n.height
+}
+
+// update recomputes subtree totals, must be called on new nodes only
+func (n *node) update() *node {
+	n.count = n.left.getCount() + 1 + n.right.getCount()
+	n.width = n.left.getWidth() + n.lineWidth + n.right.getWidth()
+	n.height = n.left.getHeight() + n.rows + n.right.getHeight()
+	return n
+}
+
+// with returns copy of node with given children
+func (n *node) with(left, right *node) *node {
+	c := *n
+	c.left, c.right = left, right
+	return c.update()
+}
+
+func merge(a, b *node) *node {
+	switch {
+	case a == nil:
+		return b
+	case b == nil:
+		return a
+	case a.priority > b.priority:
+		return a.with(a.left, merge(a.right, b))
+	default:
+		return b.with(merge(a, b.left), b.right)
+	}
+}
+
+// split tree into first k lines and the rest
+func split(n *node, k int) (*node, *node) {
+	if n == nil {
+		return nil, nil
+	}
+
+	if k <= n.left.getCount() {
+		a, b := split(n.left, k)
+		return a, n.with(b, n.right)
+	}
+
+	a, b := split(n.right, k-n.left.getCount()-1)
+	return n.with(n.left, a), b
+}
+
+// rope is a persistent sequence of text lines. Lines are stored in balanced
+// tree along with number of rows they take when soft-wrapped, so that
+// editing, looking up line by index or by wrapped row takes O(log n).
+// Changing rope returns new one, old one stays valid, making it cheap to
+// keep for undo. Lines returned from rope must not be modified.
+type rope struct {
+	root *node
+	// wrap width, used to compute rows of lines
+	width int
+}
+
+// newRope creates rope of given lines, soft-wrapped to width.
+func newRope(width int, lines ...[]rune) rope {
+	r := rope{width: width}
+	r.root = r.build(lines)
+	return r
+}
+
+func (r rope) newNode(line []rune) *node {
+	return &node{
+		priority:  rand.Uint32(), //nolint:gosec // not used for security
+		line:      line,
+		lineWidth: runesWidth(line),
+		rows:      len(wrap(line, r.width)),
+	}
+}
+
+// build tree of lines in linear time, as cartesian tree of priorities
+func (r rope) build(lines [][]rune) *node {
+	var stack []*node
+	for _, line := range lines {
+		n := r.newNode(line)
+		var last *node
+		for len(stack) > 0 && stack[len(stack)-1].priority < n.priority {
+			last = stack[len(stack)-1].update()
+			stack = stack[:len(stack)-1]
+		}
+		n.left = last
+		if len(stack) > 0 {
+			stack[len(stack)-1].right = n
+		}
+		stack = append(stack, n)
+	}
+
+	for i := len(stack) - 1; i > 0; i-- {
+		stack[i].update()
+	}
+	if len(stack) == 0 {
+		return nil
+	}
+	return stack[0].update()
+}
+
+// Len returns number of lines.
+func (r rope) Len() int {
+	return r.root.getCount()
+}
+
+// Width returns total display width of all lines.
+func (r rope) Width() int {
+	return r.root.getWidth()
+}
+
+// Rows returns total number of soft-wrapped rows.
+func (r rope) Rows() int {
+	return r.root.getHeight()
+}
+
+// Line returns line by index.
+func (r rope) Line(i int) []rune {
+	n := r.root
+	for n != nil {
+		switch left := n.left.getCount(); {
+		case i < left:
+			n = n.left
+		case i == left:
+			return n.line
+		default:
+			i -= left + 1
+			n = n.right
+		}
+	}
+	return nil
+}
+
+// RowOf returns number of soft-wrapped rows before line i.
+func (r rope) RowOf(i int) int {
+	row := 0
+	n := r.root
+	for n != nil {
+		switch left := n.left.getCount(); {
+		case i < left:
+			n = n.left
+		case i == left:
+			return row + n.left.getHeight()
+		default:
+			i -= left + 1
+			row += n.left.getHeight() + n.rows
+			n = n.right
+		}
+	}
+	return row
+}
+
+// LineAtRow returns index of line shown on soft-wrapped row and offset of
+// the row from the first row of the line. If row is past the end, number of
+// lines is returned.
+func (r rope) LineAtRow(row int) (int, int) {
+	i := 0
+	n := r.root
+	for n != nil {
+		switch left := n.left.getHeight(); {
+		case row < left:
+			n = n.left
+		case row < left+n.rows:
+			return i + n.left.getCount(), row - left
+		default:
+			row -= left + n.rows
+			i += n.left.getCount() + 1
+			n = n.right
+		}
+	}
+	return i, 0
+}
+
+// Splice replaces lines from i to j exclusive with given lines.
+func (r rope) Splice(i, j int, lines ...[]rune) rope {
+	head, rest := split(r.root, i)
+	_, tail := split(rest, j-i)
+	r.root = merge(merge(head, r.build(lines)), tail)
+	return r
+}
+
+// Set replaces line i. Rope is returned as is if line does not change.
+func (r rope) Set(i int, line []rune) rope {
+	if slices.Equal(r.Line(i), line) {
+		return r
+	}
+	return r.Splice(i, i+1, line)
+}
+
+// Rewrap returns rope with lines soft-wrapped to another width.
+func (r rope) Rewrap(width int) rope {
+	if width == r.width {
+		return r
+	}
+
+	r.width = width
+	var rewrap func(*node) *node
+	rewrap = func(n *node) *node {
+		if n == nil {
+			return nil
+		}
+
+		c := *n
+		c.left, c.right = rewrap(n.left), rewrap(n.right)
+		c.rows = len(wrap(c.line, width))
+		return c.update()
+	}
+	r.root = rewrap(r.root)
+	return r
+}
+
+// Each calls fn on lines starting from line i, until fn returns false.
+func (r rope) Each(i int, fn func(i int, line []rune) bool) {
+	var each func(n *node, offset int) bool
+	each = func(n *node, offset int) bool {
+		if n == nil {
+			return true
+		}
+
+		idx := offset + n.left.getCount()
+		if i < idx && !each(n.left, offset) {
+			return false
+		}
+		if i <= idx && !fn(idx, n.line) {
+			return false
+		}
+		return each(n.right, idx+1)
+	}
+	each(r.root, 0)
+}
+
+// runesWidth returns display width of runes. Unlike rw.StringWidth, it does
+// not segment graphemes, which is much faster and same for most texts.
+func runesWidth(runes []rune) int {
+	width := 0
+	for _, r := range runes {
+		width += rw.RuneWidth(r)
+	}
+	return width
+}
+
+// String returns lines joined with newlines.
+func (r rope) String() string {
+	var sb strings.Builder
+	r.Each(0, func(i int, line []rune) bool {
+		if i > 0 {
+			sb.WriteByte('\n')
+		}
+		sb.WriteString(string(line))
+		return true
+	})
+	return sb.String()
+}
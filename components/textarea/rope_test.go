@@ -0,0 +1,202 @@
+package textarea
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/rprtr258/assert"
+
+	"github.com/rprtr258/tea"
+)
+
+func lines(r rope) []string {
+	res := []string{}
+	r.Each(0, func(_ int, line []rune) bool {
+		res = append(res, string(line))
+		return true
+	})
+	return res
+}
+
+func TestRope(t *testing.T) {
+	// "ccc ddd" is wrapped into two rows
+	r := newRope(4, []rune("a"), []rune("b"), []rune("ccc ddd"), []rune("e"))
+	assert.Equal(t, []string{"a", "b", "ccc ddd", "e"}, lines(r))
+	assert.Equal(t, 5, r.Rows())
+	assert.Equal(t, 10, r.Width())
+
+	r2 := r.Splice(1, 3, []rune("x"), []rune("y"), []rune("z"))
+	assert.Equal(t, []string{"a", "x", "y", "z", "e"}, lines(r2))
+	assert.Equal(t, "a\nx\ny\nz\ne", r2.String())
+	// old rope is left untouched
+	assert.Equal(t, []string{"a", "b", "ccc ddd", "e"}, lines(r))
+
+	for row, want := range [][2]int{{0, 0}, {1, 0}, {2, 0}, {2, 1}, {3, 0}, {4, 0}} {
+		i, offset := r.LineAtRow(row)
+		assert.Equal(t, want, [2]int{i, offset})
+	}
+	for i, want := range []int{0, 1, 2, 4, 5} {
+		assert.Equal(t, want, r.RowOf(i))
+	}
+
+	assert.Equal(t, 4, r.Rewrap(10).Rows())
+	assert.Equal(t, r, r.Set(0, []rune("a")))
+}
+
+func TestRopeRandomEdits(t *testing.T) {
+	want := []string{}
+	r := newRope(80)
+	for i := 0; i < 1000; i++ {
+		at := (i * 7919) % (len(want) + 1)
+		switch {
+		case i%3 == 2 && len(want) > 0:
+			at %= len(want)
+			want = append(want[:at], want[at+1:]...)
+			r = r.Splice(at, at+1)
+		default:
+			line := fmt.Sprint(i)
+			want = append(want[:at], append([]string{line}, want[at:]...)...)
+			r = r.Splice(at, at, []rune(line))
+		}
+	}
+	assert.Equal(t, want, lines(r))
+	for i, line := range want {
+		assert.Equal(t, line, string(r.Line(i)))
+	}
+}
+
+func newBenchmarkModel(lines int) Model {
+	var sb strings.Builder
+	for i := range lines {
+		fmt.Fprintf(&sb, "%d: lorem ipsum dolor sit amet, consectetur adipiscing elit, sed do eiusmod tempor\n", i)
+	}
+
+	m := New()
+	m.CharLimit = 0
+	m.MaxHeight = 0
+	m.SetWidth(80)
+	m.SetHeight(40)
+	m.Focus()
+	m.SetValue(sb.String())
+	m.row, m.col = lines/2, 0
+	return m
+}
+
+func BenchmarkSetValue100k(b *testing.B) {
+	for range b.N {
+		newBenchmarkModel(100_000)
+	}
+}
+
+func BenchmarkTyping100k(b *testing.B) {
+	m := newBenchmarkModel(100_000)
+	vb := tea.NewViewbox(40, 80)
+	f := func(...tea.Cmd) {}
+
+	b.ResetTimer()
+	for i := range b.N {
+		if i%80 == 79 {
+			m.Update(tea.MsgKey{Type: tea.KeyEnter}, f)
+		} else {
+			m.Update(tea.MsgKey{Type: tea.KeyRunes, Runes: []rune{'a'}}, f)
+		}
+		m.View(vb)
+	}
+}
+
+// sliceLines is lines storage used before rope, kept to compare performance:
+// lines are shifted on each edit and rows are counted by wrapping each line.
+type sliceLines struct {
+	lines [][]rune
+	width int
+}
+
+func (s *sliceLines) Splice(i, j int, lines ...[]rune) {
+	s.lines = slices.Replace(s.lines, i, j, lines...)
+}
+
+func (s *sliceLines) Line(i int) []rune {
+	return s.lines[i]
+}
+
+func (s *sliceLines) RowOf(i int) int {
+	row := 0
+	for _, line := range s.lines[:i] {
+		row += len(wrap(line, s.width))
+	}
+	return row
+}
+
+// storage is lines storage under benchmark.
+type storage interface {
+	Splice(i, j int, lines ...[]rune)
+	Line(i int) []rune
+	RowOf(i int) int
+}
+
+// ropeLines adapts persistent rope to storage.
+type ropeLines struct{ rope }
+
+func (r *ropeLines) Splice(i, j int, lines ...[]rune) {
+	r.rope = r.rope.Splice(i, j, lines...)
+}
+
+// benchmarkStorage runs benchmark for both rope and slice of lines of given
+// size.
+func benchmarkStorage(b *testing.B, size int, bench func(b *testing.B, s storage)) {
+	lines := make([][]rune, size)
+	for i := range lines {
+		lines[i] = []rune(fmt.Sprintf("%d: lorem ipsum dolor sit amet, consectetur adipiscing elit, sed do eiusmod tempor", i))
+	}
+
+	b.Run("rope", func(b *testing.B) {
+		s := &ropeLines{newRope(80, lines...)}
+		b.ResetTimer()
+		bench(b, s)
+	})
+	b.Run("slice", func(b *testing.B) {
+		s := &sliceLines{lines: slices.Clone(lines), width: 80}
+		b.ResetTimer()
+		bench(b, s)
+	})
+}
+
+func BenchmarkStorageInsert100k(b *testing.B) {
+	benchmarkStorage(b, 100_000, func(b *testing.B, s storage) {
+		line := []rune("inserted line")
+		for range b.N {
+			s.Splice(50_000, 50_000, line)
+		}
+	})
+}
+
+func BenchmarkStorageDelete100k(b *testing.B) {
+	benchmarkStorage(b, 100_000, func(b *testing.B, s storage) {
+		// line is put back, so that size stays the same
+		for range b.N {
+			line := s.Line(50_000)
+			s.Splice(50_000, 50_001)
+			b.StopTimer()
+			s.Splice(50_000, 50_000, line)
+			b.StartTimer()
+		}
+	})
+}
+
+func BenchmarkStorageLine100k(b *testing.B) {
+	benchmarkStorage(b, 100_000, func(b *testing.B, s storage) {
+		for i := range b.N {
+			s.Line(i * 7919 % 100_000)
+		}
+	})
+}
+
+func BenchmarkStorageRowOf100k(b *testing.B) {
+	benchmarkStorage(b, 100_000, func(b *testing.B, s storage) {
+		for i := range b.N {
+			s.RowOf(i * 7919 % 100_000)
+		}
+	})
+}
@@ -0,0 +1,52 @@
+package textarea
+
+import (
+	"testing"
+
+	"github.com/rprtr258/assert"
+
+	"github.com/rprtr258/tea"
+)
+
+func TestSearchReplace(t *testing.T) {
+	f := func(...tea.Cmd) {}
+	typeKeys := func(m *Model, keys ...tea.MsgKey) {
+		for _, k := range keys {
+			m.Update(k, f)
+		}
+	}
+	runes := func(s string) tea.MsgKey {
+		return tea.MsgKey{Type: tea.KeyRunes, Runes: []rune(s)}
+	}
+
+	m := New()
+	m.Focus()
+	m.SetValue("a cat\ncat cat\ndog")
+	m.row, m.col = 0, 0
+
+	typeKeys(&m, tea.MsgKey{Type: tea.KeyCtrlR}, runes("c"), runes("a"), runes("t"))
+	assert.Equal(t, 3, len(m.Search.Matches()))
+	assert.Equal(t, [2]int{0, 2}, [2]int{m.row, m.col})
+
+	// next match
+	typeKeys(&m, tea.MsgKey{Type: tea.KeyEnter})
+	assert.Equal(t, [2]int{1, 0}, [2]int{m.row, m.col})
+
+	// replace one, cursor moves to the next match
+	typeKeys(&m, tea.MsgKey{Type: tea.KeyTab}, runes("cow"), tea.MsgKey{Type: tea.KeyEnter})
+	assert.Equal(t, "a cat\ncow cat\ndog", m.Value())
+	assert.Equal(t, [2]int{1, 4}, [2]int{m.row, m.col})
+
+	// replace all
+	typeKeys(&m, tea.MsgKey{Type: tea.KeyEnter, Alt: true})
+	assert.Equal(t, "a cow\ncow cow\ndog", m.Value())
+	assert.Equal(t, 0, len(m.Search.Matches()))
+
+	// each replace is undone in single step
+	typeKeys(&m, tea.MsgKey{Type: tea.KeyEsc})
+	assert.Equal(t, false, m.Search.Active())
+	m.Undo()
+	assert.Equal(t, "a cat\ncow cat\ndog", m.Value())
+	m.Undo()
+	assert.Equal(t, "a cat\ncat cat\ndog", m.Value())
+}
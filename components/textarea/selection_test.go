@@ -0,0 +1,61 @@
+package textarea
+
+import (
+	"testing"
+
+	"github.com/rprtr258/assert"
+
+	"github.com/rprtr258/tea"
+)
+
+func TestSelection(t *testing.T) {
+	var cmds []tea.Cmd
+	press := func(m *Model, msgs ...tea.MsgKey) {
+		cmds = nil
+		for _, msg := range msgs {
+			m.Update(msg, func(c ...tea.Cmd) { cmds = append(cmds, c...) })
+		}
+	}
+	runes := func(s string) tea.MsgKey {
+		return tea.MsgKey{Type: tea.KeyRunes, Runes: []rune(s)}
+	}
+
+	m := New()
+	m.Focus()
+	m.SetValue("hello\nworld")
+	m.row, m.col = 0, 3
+
+	// selection is extended across lines
+	press(&m, tea.MsgKey{Type: tea.KeyShiftRight}, tea.MsgKey{Type: tea.KeyShiftDown})
+	assert.Equal(t, "lo\nworl", m.Selection())
+
+	// copy keeps text and selection
+	press(&m, tea.MsgKey{Type: tea.KeyCtrlC})
+	assert.Equal(t, 1, len(cmds))
+	assert.Equal(t, "hello\nworld", m.Value())
+	assert.Equal(t, "lo\nworl", m.Selection())
+
+	// cut deletes selected text
+	press(&m, tea.MsgKey{Type: tea.KeyCtrlX})
+	assert.Equal(t, "held", m.Value())
+	assert.Equal(t, [2]int{0, 3}, [2]int{m.row, m.col})
+	assert.Equal(t, "", m.Selection())
+
+	// typing replaces selection
+	press(&m, tea.MsgKey{Type: tea.KeyShiftLeft}, tea.MsgKey{Type: tea.KeyShiftLeft})
+	assert.Equal(t, "el", m.Selection())
+	press(&m, runes("a"))
+	assert.Equal(t, "had", m.Value())
+	assert.Equal(t, [2]int{0, 2}, [2]int{m.row, m.col})
+
+	// backspace deletes selection only
+	press(&m, tea.MsgKey{Type: tea.KeyShiftEnd})
+	assert.Equal(t, "d", m.Selection())
+	press(&m, tea.MsgKey{Type: tea.KeyBackspace})
+	assert.Equal(t, "ha", m.Value())
+	assert.Equal(t, "", m.Selection())
+
+	// moving cursor without shift drops selection
+	press(&m, tea.MsgKey{Type: tea.KeyShiftLeft}, tea.MsgKey{Type: tea.KeyLeft})
+	assert.Equal(t, "", m.Selection())
+}
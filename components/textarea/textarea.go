@@ -2,9 +2,12 @@ package textarea
 
 import (
 	"fmt"
+	"slices"
 	"strings"
+	"sync/atomic"
 	"unicode"
 
+	"github.com/alecthomas/chroma/v2"
 	"github.com/atotto/clipboard"
 	rw "github.com/mattn/go-runewidth"
 	"github.com/rprtr258/fun"
@@ -12,8 +15,10 @@ import (
 	"github.com/rprtr258/tea"
 	"github.com/rprtr258/tea/components/box"
 	"github.com/rprtr258/tea/components/cursor"
+	"github.com/rprtr258/tea/components/headless/history"
 	"github.com/rprtr258/tea/components/key"
 	"github.com/rprtr258/tea/components/runeutil"
+	"github.com/rprtr258/tea/components/search"
 	"github.com/rprtr258/tea/components/viewport"
 	"github.com/rprtr258/tea/styles"
 )
@@ -34,6 +39,9 @@ type (
 	msgPasteErr error
 )
 
+// _lastID is used to generate unique mouse zone ids of text areas.
+var _lastID atomic.Int64
+
 // KeyMap is the key bindings for different actions within the textarea.
 type KeyMap struct {
 	CharacterBackward       key.Binding
@@ -60,6 +68,28 @@ type KeyMap struct {
 	CapitalizeWordForward key.Binding
 
 	TransposeCharacterBackward key.Binding
+
+	Undo key.Binding
+	Redo key.Binding
+
+	SelectCharacterBackward key.Binding
+	SelectCharacterForward  key.Binding
+	SelectWordBackward      key.Binding
+	SelectWordForward       key.Binding
+	SelectLinePrevious      key.Binding
+	SelectLineNext          key.Binding
+	SelectLineStart         key.Binding
+	SelectLineEnd           key.Binding
+	Copy                    key.Binding
+	Cut                     key.Binding
+
+	Search  key.Binding
+	Replace key.Binding
+
+	AddCursorAbove          key.Binding
+	AddCursorBelow          key.Binding
+	AddCursorNextOccurrence key.Binding
+	ClearCursors            key.Binding
 }
 
 // DefaultKeyMap is the default set of key bindings for navigating and acting
@@ -89,6 +119,28 @@ var DefaultKeyMap = KeyMap{
 	UppercaseWordForward:  key.Binding{Keys: []string{"alt+u"}},
 
 	TransposeCharacterBackward: key.Binding{Keys: []string{"ctrl+t"}},
+
+	Undo: key.Binding{Keys: []string{"ctrl+z"}},
+	Redo: key.Binding{Keys: []string{"ctrl+y"}},
+
+	SelectCharacterBackward: key.Binding{Keys: []string{"shift+left"}},
+	SelectCharacterForward:  key.Binding{Keys: []string{"shift+right"}},
+	SelectWordBackward:      key.Binding{Keys: []string{"ctrl+shift+left"}},
+	SelectWordForward:       key.Binding{Keys: []string{"ctrl+shift+right"}},
+	SelectLinePrevious:      key.Binding{Keys: []string{"shift+up"}},
+	SelectLineNext:          key.Binding{Keys: []string{"shift+down"}},
+	SelectLineStart:         key.Binding{Keys: []string{"shift+home"}},
+	SelectLineEnd:           key.Binding{Keys: []string{"shift+end"}},
+	Copy:                    key.Binding{Keys: []string{"ctrl+c"}},
+	Cut:                     key.Binding{Keys: []string{"ctrl+x"}},
+
+	Search:  key.Binding{Keys: []string{"ctrl+s"}},
+	Replace: key.Binding{Keys: []string{"ctrl+r", "alt+%"}},
+
+	AddCursorAbove:          key.Binding{Keys: []string{"ctrl+shift+up", "alt+ctrl+up"}},
+	AddCursorBelow:          key.Binding{Keys: []string{"ctrl+shift+down", "alt+ctrl+down"}},
+	AddCursorNextOccurrence: key.Binding{Keys: []string{"alt+n"}},
+	ClearCursors:            key.Binding{Keys: []string{"esc"}},
 }
 
 var (
@@ -101,6 +153,7 @@ var (
 		Placeholder:      styles.Style{}.Foreground(styles.FgColor("240")),
 		Prompt:           styles.Style{}.Foreground(styles.FgColor("7")),
 		Text:             styles.Style{},
+		Selection:        styles.Style{}.Background(styles.BgAdaptiveColor("153", "24")),
 	}
 	_styleBlurredDefault = Style{
 		Base:             styles.Style{},
@@ -111,6 +164,7 @@ var (
 		Placeholder:      styles.Style{}.Foreground(styles.FgColor("240")),
 		Prompt:           styles.Style{}.Foreground(styles.FgColor("7")),
 		Text:             styles.Style{}.Foreground(styles.FgAdaptiveColor("245", "7")),
+		Selection:        styles.Style{}.Background(styles.BgAdaptiveColor("254", "237")),
 	}
 )
 
@@ -155,6 +209,7 @@ type Style struct {
 	Placeholder      styles.Style
 	Prompt           styles.Style
 	Text             styles.Style
+	Selection        styles.Style
 }
 
 // Model is the Tea model for this text area element.
@@ -185,6 +240,9 @@ type Model struct {
 	// KeyMap encodes the keybindings recognized by the widget.
 	KeyMap KeyMap
 
+	// Highlighter, if set, colors text according to syntax of a language.
+	Highlighter *Highlighter
+
 	// Styling. FocusedStyle and BlurredStyle are used to style the textarea in
 	// focused and blurred states.
 	FocusedStyle Style
@@ -198,6 +256,9 @@ type Model struct {
 	// Cursor is the text area cursor.
 	Cursor cursor.Model
 
+	// Search is search bar shown at the bottom of the text area.
+	Search search.Model
+
 	// CharLimit is the maximum number of characters this input element will
 	// accept. If 0 or less, there's no limit.
 	CharLimit int
@@ -227,7 +288,7 @@ type Model struct {
 	height int
 
 	// Underlying text value.
-	value [][]rune
+	value rope
 
 	// focus indicates whether user input focus should be on this input
 	// component. When false, ignore keyboard input and hide the cursor.
@@ -251,6 +312,47 @@ type Model struct {
 
 	// rune sanitizer for input.
 	rsan runeutil.Sanitizer
+
+	// history of edits for undo and redo.
+	history *history.Undo[snapshot]
+
+	// Selection spans from anchor to the cursor, if selecting is set.
+	anchor    position
+	selecting bool
+
+	// dragging is set while mouse button is held after click on text area.
+	// If alt was held on click, block of text is selected.
+	dragging      bool
+	draggingBlock bool
+
+	// cursors are additional cursors, edits are made at each of them along
+	// with the main one.
+	cursors []cursorState
+
+	// vim is state of vim-like modal editing, nil if it is disabled.
+	vim *vimState
+
+	// id is mouse zone id of the text area.
+	id string
+
+	// searched is text matches were found in, used to find them again once
+	// text changes.
+	searched *node
+}
+
+// position of character in text
+type position struct {
+	row, col int
+}
+
+func (p position) less(q position) bool {
+	return p.row < q.row || p.row == q.row && p.col < q.col
+}
+
+// snapshot is the text area state saved in undo history.
+type snapshot struct {
+	value    rope
+	row, col int
 }
 
 // New creates a new model with default settings.
@@ -270,9 +372,9 @@ func New() Model {
 		EndOfBufferCharacter: '~',
 		ShowLineNumbers:      true,
 		Cursor:               cur,
+		Search:               search.New(),
 		KeyMap:               DefaultKeyMap,
 
-		value:            make([][]rune, _minHeight, _defaultMaxHeight),
 		focus:            false,
 		col:              0,
 		row:              0,
@@ -283,10 +385,15 @@ func New() Model {
 		// Textinput has all its input on a single line so collapse
 		// newlines/tabs to single spaces.
 		rsan: runeutil.NewSanitizer(),
+
+		history: history.NewUndo(snapshot{}),
+
+		id: fmt.Sprintf("textarea-%d", _lastID.Add(1)),
 	}
 
 	m.SetHeight(defaultHeight)
 	m.SetWidth(defaultWidth)
+	m.Reset()
 
 	return m
 }
@@ -313,6 +420,9 @@ func (m *Model) insertRunesFromUserInput(runes []rune) {
 	// clipboard. This avoids bugs due to e.g. tab characters and
 	// whatnot.
 	runes = m.san().Sanitize(runes)
+	if len(runes) == 0 {
+		return
+	}
 
 	var availSpace int
 	if m.CharLimit > 0 {
@@ -334,9 +444,6 @@ func (m *Model) insertRunesFromUserInput(runes []rune) {
 	for i := 0; i < len(runes); i++ {
 		if runes[i] == '\n' {
 			// Queue a line to become a new row in the text area below.
-			// Beware to clamp the max capacity of the slice, to ensure no
-			// data from different rows get overwritten when later edits
-			// will modify this line.
 			lines = append(lines, runes[lstart:i:i])
 			lstart = i + 1
 		}
@@ -348,8 +455,8 @@ func (m *Model) insertRunesFromUserInput(runes []rune) {
 	}
 
 	// Obey the maximum height limit.
-	if m.MaxHeight > 0 && len(m.value)+len(lines)-1 > m.MaxHeight {
-		allowedHeight := max(0, m.MaxHeight-len(m.value)+1)
+	if m.MaxHeight > 0 && m.value.Len()+len(lines)-1 > m.MaxHeight {
+		allowedHeight := max(0, m.MaxHeight-m.value.Len()+1)
 		lines = lines[:allowedHeight]
 	}
 
@@ -358,73 +465,44 @@ func (m *Model) insertRunesFromUserInput(runes []rune) {
 		return
 	}
 
-	// Save the remainder of the original line at the current
-	// cursor position.
-	tail := make([]rune, len(m.value[m.row][m.col:]))
-	copy(tail, m.value[m.row][m.col:])
-
-	// Paste the first line at the current cursor position.
-	m.value[m.row] = append(m.value[m.row][:m.col], lines[0]...)
-	m.col += len(lines[0])
+	// Paste the first line at the current cursor position and add the
+	// remainder of the original line at the end of the last line inserted.
+	line := m.value.Line(m.row)
+	last := len(lines) - 1
+	lines[0] = slices.Concat(line[:m.col], lines[0])
+	col := len(lines[last])
+	lines[last] = slices.Concat(lines[last], line[m.col:])
 
-	if numExtraLines := len(lines) - 1; numExtraLines > 0 {
-		// Add the new lines.
-		// We try to reuse the slice if there's already space.
-		var newGrid [][]rune
-		if cap(m.value) >= len(m.value)+numExtraLines {
-			// Can reuse the extra space.
-			newGrid = m.value[:len(m.value)+numExtraLines]
-		} else {
-			// No space left; need a new slice.
-			newGrid = make([][]rune, len(m.value)+numExtraLines)
-			copy(newGrid, m.value[:m.row+1])
-		}
-		// Add all the rows that were after the cursor in the original
-		// grid at the end of the new grid.
-		copy(newGrid[m.row+1+numExtraLines:], m.value[m.row+1:])
-		m.value = newGrid
-		// Insert all the new lines in the middle.
-		for _, l := range lines[1:] {
-			m.row++
-			m.value[m.row] = l
-			m.col = len(l)
-		}
-	}
-
-	// Finally add the tail at the end of the last line inserted.
-	m.value[m.row] = append(m.value[m.row], tail...)
-
-	m.SetCursor(m.col)
+	m.value = m.value.Splice(m.row, m.row+1, lines...)
+	m.row += last
+	m.SetCursor(col)
 }
 
 // Value returns the value of the text input.
 func (m *Model) Value() string {
-	if m.value == nil {
-		return ""
-	}
-
-	var v strings.Builder
-	for _, l := range m.value {
-		v.WriteString(string(l))
-		v.WriteByte('\n')
-	}
-
-	return strings.TrimSuffix(v.String(), "\n")
+	return m.value.String()
 }
 
 // Length returns the number of characters currently in the text input.
 func (m *Model) Length() int {
-	var l int
-	for _, row := range m.value {
-		l += rw.StringWidth(string(row))
-	}
-	// We add len(m.value) to include the newline characters.
-	return l + len(m.value) - 1
+	// We add number of lines to include the newline characters.
+	return m.value.Width() + m.value.Len() - 1
 }
 
 // LineCount returns the number of lines that are currently in the text input.
 func (m *Model) LineCount() int {
-	return len(m.value)
+	return m.value.Len()
+}
+
+// currentLine returns the line the cursor is on. It must not be modified,
+// use setLine instead.
+func (m *Model) currentLine() []rune {
+	return m.value.Line(m.row)
+}
+
+// setLine replaces the line the cursor is on.
+func (m *Model) setLine(line []rune) {
+	m.value = m.value.Set(m.row, line)
 }
 
 // Line returns the line position.
@@ -439,14 +517,14 @@ func (m *Model) CursorDown() {
 	charOffset := max(m.lastCharOffset, li.CharOffset)
 	m.lastCharOffset = charOffset
 
-	if li.RowOffset+1 >= li.Height && m.row < len(m.value)-1 {
+	if li.RowOffset+1 >= li.Height && m.row < m.value.Len()-1 {
 		m.row++
 		m.col = 0
 	} else {
 		// Move the cursor to the start of the next line so that we can get
 		// the line information. We need to add 2 columns to account for the
 		// trailing space wrapping.
-		m.col = min(li.StartColumn+li.Width+2, len(m.value[m.row])-1)
+		m.col = min(li.StartColumn+li.Width+2, len(m.currentLine())-1)
 	}
 
 	nli := m.LineInfo()
@@ -458,10 +536,10 @@ func (m *Model) CursorDown() {
 
 	offset := 0
 	for offset < charOffset {
-		if m.col > len(m.value[m.row]) || offset >= nli.CharWidth-1 {
+		if m.col > len(m.currentLine()) || offset >= nli.CharWidth-1 {
 			break
 		}
-		offset += rw.RuneWidth(m.value[m.row][m.col])
+		offset += rw.RuneWidth(m.currentLine()[m.col])
 		m.col++
 	}
 }
@@ -474,7 +552,7 @@ func (m *Model) CursorUp() {
 
 	if li.RowOffset <= 0 && m.row > 0 {
 		m.row--
-		m.col = len(m.value[m.row])
+		m.col = len(m.currentLine())
 	} else {
 		// Move the cursor to the end of the previous line.
 		// This can be done by moving the cursor to the start of the line and
@@ -492,10 +570,10 @@ func (m *Model) CursorUp() {
 
 	offset := 0
 	for offset < charOffset {
-		if m.col >= len(m.value[m.row]) || offset >= nli.CharWidth-1 {
+		if m.col >= len(m.currentLine()) || offset >= nli.CharWidth-1 {
 			break
 		}
-		offset += rw.RuneWidth(m.value[m.row][m.col])
+		offset += rw.RuneWidth(m.currentLine()[m.col])
 		m.col++
 	}
 }
@@ -503,7 +581,7 @@ func (m *Model) CursorUp() {
 // SetCursor moves the cursor to the given position. If the position is
 // out of bounds the cursor will be moved to the start or end accordingly.
 func (m *Model) SetCursor(col int) {
-	m.col = clamp(col, 0, len(m.value[m.row]))
+	m.col = clamp(col, 0, len(m.currentLine()))
 	// Any time that we move the cursor horizontally we need to reset the last
 	// offset so that the horizontal position when navigating is adjusted.
 	m.lastCharOffset = 0
@@ -516,7 +594,7 @@ func (m *Model) CursorStart() {
 
 // CursorEnd moves the cursor to the end of the input field.
 func (m *Model) CursorEnd() {
-	m.SetCursor(len(m.value[m.row]))
+	m.SetCursor(len(m.currentLine()))
 }
 
 // Focused returns the focus state on the model.
@@ -542,15 +620,360 @@ func (m *Model) Blur() {
 
 // Reset sets the input to its default state with no input.
 func (m *Model) Reset() {
-	startCap := m.MaxHeight
-	if startCap <= 0 {
-		startCap = _defaultMaxHeight
-	}
-	m.value = make([][]rune, _minHeight, startCap)
+	m.value = newRope(m.width, []rune{})
 	m.col = 0
 	m.row = 0
 	m.viewport.GotoTop()
 	m.SetCursor(0)
+	m.selecting = false
+	m.cursors = nil
+	m.history.Reset(m.snapshot())
+}
+
+func (m *Model) snapshot() snapshot {
+	return snapshot{
+		value: m.value,
+		row:   m.row,
+		col:   m.col,
+	}
+}
+
+func (m *Model) restore(s snapshot, ok bool) {
+	if !ok {
+		return
+	}
+
+	m.value = s.value.Rewrap(m.width)
+	m.row = clamp(s.row, 0, m.value.Len()-1)
+	m.SetCursor(s.col)
+	m.selecting = false
+	m.cursors = nil
+}
+
+// recordEdit saves edit made since before state to undo history, if the
+// text has changed.
+func (m *Model) recordEdit(kind history.EditKind, before snapshot) {
+	after := m.snapshot()
+	if after.value.root == before.value.root {
+		m.history.Break()
+		return
+	}
+
+	m.history.Edit(kind, before, after)
+}
+
+// Undo reverts the last edit, restoring the cursor position before it.
+// Consecutive typing or deleting of characters is reverted at once.
+func (m *Model) Undo() {
+	m.restore(m.history.Undo())
+}
+
+// Redo reapplies the last undone edit.
+func (m *Model) Redo() {
+	m.restore(m.history.Redo())
+}
+
+// selectionRange returns ordered bounds of main cursor selection, if it is
+// not empty.
+func (m *Model) selectionRange() (start, end position, ok bool) {
+	return m.state().selectionRange()
+}
+
+// selected returns whether character at given position is selected by any
+// cursor.
+func (m *Model) selected(p position) bool {
+	inRange := func(s cursorState) bool {
+		start, end, ok := s.selectionRange()
+		return ok && !p.less(start) && p.less(end)
+	}
+
+	if inRange(m.state()) || m.vimVisualLineSelected(p.row) {
+		return true
+	}
+	for _, c := range m.cursors {
+		if inRange(c) {
+			return true
+		}
+	}
+	return false
+}
+
+// Selection returns text selected by the main cursor, or empty string if
+// nothing is selected.
+func (m *Model) Selection() string {
+	start, end, ok := m.selectionRange()
+	if !ok {
+		return ""
+	}
+	return m.textBetween(start, end)
+}
+
+// textBetween returns text from start to end position.
+func (m *Model) textBetween(start, end position) string {
+	if start.row == end.row {
+		return string(m.value.Line(start.row)[start.col:end.col])
+	}
+
+	var sb strings.Builder
+	sb.WriteString(string(m.value.Line(start.row)[start.col:]))
+	for row := start.row + 1; row < end.row; row++ {
+		sb.WriteByte('\n')
+		sb.WriteString(string(m.value.Line(row)))
+	}
+	sb.WriteByte('\n')
+	sb.WriteString(string(m.value.Line(end.row)[:end.col]))
+	return sb.String()
+}
+
+// SelectAll selects the whole text, leaving the cursor at its end.
+func (m *Model) SelectAll() {
+	m.anchor = position{0, 0}
+	m.selecting = true
+	m.moveToEnd()
+}
+
+// ClearSelection deselects text, leaving the cursor where it is.
+func (m *Model) ClearSelection() {
+	m.selecting = false
+}
+
+// extendSelection moves the cursor using move, selecting text it passes.
+func (m *Model) extendSelection(move func()) {
+	if !m.selecting {
+		m.anchor = position{m.row, m.col}
+		m.selecting = true
+	}
+	move()
+}
+
+// deleteSelection deletes selected text and moves the cursor to its start.
+func (m *Model) deleteSelection() {
+	start, end, ok := m.selectionRange()
+	m.selecting = false
+	if !ok {
+		return
+	}
+
+	line := slices.Concat(m.value.Line(start.row)[:start.col], m.value.Line(end.row)[end.col:])
+	m.value = m.value.Splice(start.row, end.row+1, line)
+	m.row = start.row
+	m.SetCursor(start.col)
+}
+
+// copyToClipboard is a command for writing text to the clipboard.
+func copyToClipboard(s string) tea.Cmd {
+	return func() tea.Msg {
+		if err := clipboard.WriteAll(s); err != nil {
+			return msgPasteErr(err)
+		}
+		return nil
+	}
+}
+
+// updateSelection handles selection and clipboard keys. Returns whether the
+// key has been handled, otherwise it must be handled as usual, e.g. typed
+// characters replace selection.
+func (m *Model) updateSelection(msg tea.MsgKey, f func(...tea.Cmd)) bool {
+	switch {
+	case key.Matches(msg, m.KeyMap.SelectCharacterBackward):
+		m.extendSelection(func() { m.characterLeft(false /* insideLine */) })
+	case key.Matches(msg, m.KeyMap.SelectCharacterForward):
+		m.extendSelection(m.characterRight)
+	case key.Matches(msg, m.KeyMap.SelectWordBackward):
+		m.extendSelection(m.wordLeft)
+	case key.Matches(msg, m.KeyMap.SelectWordForward):
+		m.extendSelection(m.wordRight)
+	case key.Matches(msg, m.KeyMap.SelectLinePrevious):
+		m.extendSelection(m.CursorUp)
+	case key.Matches(msg, m.KeyMap.SelectLineNext):
+		m.extendSelection(m.CursorDown)
+	case key.Matches(msg, m.KeyMap.SelectLineStart):
+		m.extendSelection(m.CursorStart)
+	case key.Matches(msg, m.KeyMap.SelectLineEnd):
+		m.extendSelection(m.CursorEnd)
+	case key.Matches(msg, m.KeyMap.Copy):
+		if text := m.Selection(); text != "" {
+			f(copyToClipboard(text))
+		}
+	case key.Matches(msg, m.KeyMap.Cut):
+		if text := m.Selection(); text != "" {
+			f(copyToClipboard(text))
+			m.deleteSelection()
+		}
+	case !m.selecting:
+		return false
+	case key.Matches(msg, m.KeyMap.DeleteCharacterBackward, m.KeyMap.DeleteCharacterForward):
+		m.deleteSelection()
+	case key.Matches(msg, m.KeyMap.Paste):
+		// selection is replaced when pasted text arrives
+		return false
+	case key.Matches(msg, m.KeyMap.InsertNewline),
+		(msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace) && !msg.Alt:
+		m.deleteSelection()
+		return false
+	default:
+		m.selecting = false
+		return false
+	}
+	return true
+}
+
+// positionAt returns position of character rendered at given cell of the
+// text area, or closest one if there is no character.
+func (m *Model) positionAt(y, x int) position {
+	x -= m.promptWidth
+	if m.ShowLineNumbers {
+		x -= rw.StringWidth(fmt.Sprintf(m.lineNumberFormat, 0))
+	}
+
+	row, offset := m.value.LineAtRow(m.viewport.YOffset + y)
+	if row >= m.value.Len() {
+		row = m.value.Len() - 1
+		return position{row, len(m.value.Line(row))}
+	}
+
+	line := m.value.Line(row)
+	wrappedLines := wrap(line, m.width)
+	start := 0
+	for _, wrappedLine := range wrappedLines[:offset] {
+		start += len(wrappedLine)
+	}
+
+	col, width := start, 0
+	for _, r := range wrappedLines[offset] {
+		width += rw.RuneWidth(r)
+		if width > x {
+			break
+		}
+		col++
+	}
+	if offset < len(wrappedLines)-1 {
+		// keep cursor before trailing space of soft-wrapped line
+		col = min(col, start+len(wrappedLines[offset])-1)
+	}
+	return position{row, min(col, len(line))}
+}
+
+// updateMouse handles mouse clicks and dragging over the text area.
+func (m *Model) updateMouse(msg tea.MsgMouseZone) {
+	if msg.ID != m.id {
+		return
+	}
+
+	switch msg.Event.Type {
+	case tea.MouseLeft, tea.MouseMotion:
+		// dragging is reported as repeated left button presses or motion
+		if !m.dragging && msg.Event.Type == tea.MouseMotion {
+			return
+		}
+
+		p := m.positionAt(msg.Y, msg.X)
+		if !m.dragging {
+			m.cursors = nil
+			m.anchor = p
+			m.selecting = true
+			m.dragging = true
+			m.draggingBlock = msg.Event.Alt
+			m.history.Break()
+		}
+		if m.draggingBlock {
+			m.blockSelect(m.anchor, p)
+			break
+		}
+		m.row = p.row
+		m.SetCursor(p.col)
+	case tea.MouseRelease:
+		m.dragging = false
+	}
+}
+
+// openSearch opens search bar, query is taken from selection if it is on
+// single line.
+func (m *Model) openSearch(replace bool, f func(...tea.Cmd)) {
+	f(m.Search.Open(replace)...)
+	if selection := m.Selection(); selection != "" && !strings.Contains(selection, "\n") {
+		m.Search.SetQuery(selection)
+	}
+	m.ClearSelection()
+	m.SetHeight(m.height)
+
+	m.findMatches()
+	m.gotoMatch()
+}
+
+// searchLines enumerates lines for search.
+func (m *Model) searchLines(yield func(int, string) bool) {
+	m.value.Each(0, func(i int, line []rune) bool {
+		return yield(i, string(line))
+	})
+}
+
+// findMatches finds matches in text, match nearest to cursor becomes current.
+func (m *Model) findMatches() {
+	m.Search.Find(m.searchLines)
+	m.Search.Nearest(m.row, m.col)
+	m.searched = m.value.root
+}
+
+// gotoMatch moves cursor to the start of current match.
+func (m *Model) gotoMatch() {
+	if match, ok := m.Search.Current(); ok {
+		m.row, m.col = match.Line, match.Start
+	}
+}
+
+// replaceMatch replaces current match and moves to the next one.
+func (m *Model) replaceMatch() {
+	match, ok := m.Search.Current()
+	if !ok {
+		return
+	}
+
+	before := m.snapshot()
+	line := m.value.Line(match.Line)
+	replaced := []rune(m.Search.Replace(string(line), match))
+	m.value = m.value.Set(match.Line, replaced)
+	m.recordEdit(history.EditOther, before)
+
+	// continue after replaced text, so that it is not matched again
+	m.row, m.col = match.Line, match.End+len(replaced)-len(line)
+	m.findMatches()
+	m.gotoMatch()
+}
+
+// replaceAllMatches replaces all matches as single undoable edit.
+func (m *Model) replaceAllMatches() {
+	before := m.snapshot()
+	last := -1
+	for _, match := range m.Search.Matches() {
+		if match.Line == last {
+			continue
+		}
+
+		last = match.Line
+		m.value = m.value.Set(last, []rune(m.Search.ReplaceAll(string(m.value.Line(last)))))
+	}
+	m.col = min(m.col, len(m.currentLine()))
+	m.recordEdit(history.EditOther, before)
+	m.findMatches()
+}
+
+// updateSearch handles keys while search bar is open.
+func (m *Model) updateSearch(msg tea.MsgKey, f func(...tea.Cmd)) {
+	switch m.Search.Update(msg, f) {
+	case search.ActionNone:
+	case search.ActionFind:
+		m.findMatches()
+		m.gotoMatch()
+	case search.ActionMove:
+		m.gotoMatch()
+	case search.ActionReplace:
+		m.replaceMatch()
+	case search.ActionReplaceAll:
+		m.replaceAllMatches()
+	case search.ActionClose:
+		m.SetHeight(m.height)
+	}
 }
 
 // san initializes or retrieves the rune sanitizer.
@@ -561,7 +984,7 @@ func (m *Model) san() runeutil.Sanitizer {
 // deleteBeforeCursor deletes all text before the cursor. Returns whether or
 // not the cursor blink should be reset.
 func (m *Model) deleteBeforeCursor() {
-	m.value[m.row] = m.value[m.row][m.col:]
+	m.setLine(m.currentLine()[m.col:])
 	m.SetCursor(0)
 }
 
@@ -569,8 +992,8 @@ func (m *Model) deleteBeforeCursor() {
 // the cursor blink should be reset. If input is masked delete everything after
 // the cursor so as not to reveal word breaks in the masked input.
 func (m *Model) deleteAfterCursor() {
-	m.value[m.row] = m.value[m.row][:m.col]
-	m.SetCursor(len(m.value[m.row]))
+	m.setLine(m.currentLine()[:m.col])
+	m.SetCursor(len(m.currentLine()))
 }
 
 // transposeLeft exchanges the runes at the cursor and immediately
@@ -578,14 +1001,16 @@ func (m *Model) deleteAfterCursor() {
 // the cursor is not at the end of the line yet, moves the cursor to
 // the right.
 func (m *Model) transposeLeft() {
-	if m.col == 0 || len(m.value[m.row]) < 2 {
+	if m.col == 0 || len(m.currentLine()) < 2 {
 		return
 	}
-	if m.col >= len(m.value[m.row]) {
+	if m.col >= len(m.currentLine()) {
 		m.SetCursor(m.col - 1)
 	}
-	m.value[m.row][m.col-1], m.value[m.row][m.col] = m.value[m.row][m.col], m.value[m.row][m.col-1]
-	if m.col < len(m.value[m.row]) {
+	line := slices.Clone(m.currentLine())
+	line[m.col-1], line[m.col] = line[m.col], line[m.col-1]
+	m.setLine(line)
+	if m.col < len(m.currentLine()) {
 		m.SetCursor(m.col + 1)
 	}
 }
@@ -593,7 +1018,7 @@ func (m *Model) transposeLeft() {
 // deleteWordLeft deletes the word left to the cursor. Returns whether or not
 // the cursor blink should be reset.
 func (m *Model) deleteWordLeft() {
-	if m.col == 0 || len(m.value[m.row]) == 0 {
+	if m.col == 0 || len(m.currentLine()) == 0 {
 		return
 	}
 
@@ -603,7 +1028,7 @@ func (m *Model) deleteWordLeft() {
 	oldCol := m.col //nolint:ifshort
 
 	m.SetCursor(m.col - 1)
-	for unicode.IsSpace(m.value[m.row][m.col]) {
+	for unicode.IsSpace(m.currentLine()[m.col]) {
 		if m.col <= 0 {
 			break
 		}
@@ -612,7 +1037,7 @@ func (m *Model) deleteWordLeft() {
 	}
 
 	for m.col > 0 {
-		if !unicode.IsSpace(m.value[m.row][m.col]) {
+		if !unicode.IsSpace(m.currentLine()[m.col]) {
 			m.SetCursor(m.col - 1)
 		} else {
 			if m.col > 0 {
@@ -623,38 +1048,40 @@ func (m *Model) deleteWordLeft() {
 		}
 	}
 
-	if oldCol > len(m.value[m.row]) {
-		m.value[m.row] = m.value[m.row][:m.col]
+	line := m.currentLine()
+	if oldCol > len(line) {
+		m.setLine(line[:m.col])
 	} else {
-		m.value[m.row] = append(m.value[m.row][:m.col], m.value[m.row][oldCol:]...)
+		m.setLine(slices.Concat(line[:m.col], line[oldCol:]))
 	}
 }
 
 // deleteWordRight deletes the word right to the cursor.
 func (m *Model) deleteWordRight() {
-	if m.col >= len(m.value[m.row]) || len(m.value[m.row]) == 0 {
+	if m.col >= len(m.currentLine()) || len(m.currentLine()) == 0 {
 		return
 	}
 
 	oldCol := m.col
 
-	for m.col < len(m.value[m.row]) && unicode.IsSpace(m.value[m.row][m.col]) {
+	for m.col < len(m.currentLine()) && unicode.IsSpace(m.currentLine()[m.col]) {
 		// ignore series of whitespace after cursor
 		m.SetCursor(m.col + 1)
 	}
 
-	for m.col < len(m.value[m.row]) {
-		if !unicode.IsSpace(m.value[m.row][m.col]) {
+	for m.col < len(m.currentLine()) {
+		if !unicode.IsSpace(m.currentLine()[m.col]) {
 			m.SetCursor(m.col + 1)
 		} else {
 			break
 		}
 	}
 
-	if m.col > len(m.value[m.row]) {
-		m.value[m.row] = m.value[m.row][:oldCol]
+	line := m.currentLine()
+	if m.col > len(line) {
+		m.setLine(line[:oldCol])
 	} else {
-		m.value[m.row] = append(m.value[m.row][:oldCol], m.value[m.row][m.col:]...)
+		m.setLine(slices.Concat(line[:oldCol], line[m.col:]))
 	}
 
 	m.SetCursor(oldCol)
@@ -662,9 +1089,9 @@ func (m *Model) deleteWordRight() {
 
 // characterRight moves the cursor one character to the right.
 func (m *Model) characterRight() {
-	if m.col < len(m.value[m.row]) {
+	if m.col < len(m.currentLine()) {
 		m.SetCursor(m.col + 1)
-	} else if m.row < len(m.value)-1 {
+	} else if m.row < m.value.Len()-1 {
 		m.row++
 		m.CursorStart()
 	}
@@ -692,13 +1119,13 @@ func (m *Model) characterLeft(insideLine bool) {
 func (m *Model) wordLeft() {
 	for {
 		m.characterLeft(true /* insideLine */)
-		if m.col < len(m.value[m.row]) && !unicode.IsSpace(m.value[m.row][m.col]) {
+		if m.col < len(m.currentLine()) && !unicode.IsSpace(m.currentLine()[m.col]) {
 			break
 		}
 	}
 
 	for m.col > 0 {
-		if unicode.IsSpace(m.value[m.row][m.col-1]) {
+		if unicode.IsSpace(m.currentLine()[m.col-1]) {
 			break
 		}
 		m.SetCursor(m.col - 1)
@@ -715,10 +1142,10 @@ func (m *Model) wordRight() {
 func (m *Model) doWordRight(fn func(charIdx int, pos int)) {
 	// Skip spaces forward.
 	for {
-		if m.col < len(m.value[m.row]) && !unicode.IsSpace(m.value[m.row][m.col]) {
+		if m.col < len(m.currentLine()) && !unicode.IsSpace(m.currentLine()[m.col]) {
 			break
 		}
-		if m.row == len(m.value)-1 && m.col == len(m.value[m.row]) {
+		if m.row == m.value.Len()-1 && m.col == len(m.currentLine()) {
 			// End of text.
 			break
 		}
@@ -726,8 +1153,8 @@ func (m *Model) doWordRight(fn func(charIdx int, pos int)) {
 	}
 
 	charIdx := 0
-	for m.col < len(m.value[m.row]) {
-		if unicode.IsSpace(m.value[m.row][m.col]) {
+	for m.col < len(m.currentLine()) {
+		if unicode.IsSpace(m.currentLine()[m.col]) {
 			break
 		}
 		fn(charIdx, m.col)
@@ -738,31 +1165,46 @@ func (m *Model) doWordRight(fn func(charIdx int, pos int)) {
 
 // uppercaseRight changes the word to the right to uppercase.
 func (m *Model) uppercaseRight() {
-	m.doWordRight(func(_ int, i int) {
-		m.value[m.row][i] = unicode.ToUpper(m.value[m.row][i])
+	m.mapWordRight(func(_ int, r rune) rune {
+		return unicode.ToUpper(r)
 	})
 }
 
 // lowercaseRight changes the word to the right to lowercase.
 func (m *Model) lowercaseRight() {
-	m.doWordRight(func(_ int, i int) {
-		m.value[m.row][i] = unicode.ToLower(m.value[m.row][i])
+	m.mapWordRight(func(_ int, r rune) rune {
+		return unicode.ToLower(r)
 	})
 }
 
 // capitalizeRight changes the word to the right to title case.
 func (m *Model) capitalizeRight() {
-	m.doWordRight(func(charIdx int, i int) {
+	m.mapWordRight(func(charIdx int, r rune) rune {
 		if charIdx == 0 {
-			m.value[m.row][i] = unicode.ToTitle(m.value[m.row][i])
+			return unicode.ToTitle(r)
 		}
+		return r
 	})
 }
 
+// mapWordRight replaces characters of the word to the right using fn.
+func (m *Model) mapWordRight(fn func(charIdx int, r rune) rune) {
+	var line []rune
+	m.doWordRight(func(charIdx int, i int) {
+		if line == nil {
+			line = slices.Clone(m.currentLine())
+		}
+		line[i] = fn(charIdx, line[i])
+	})
+	if line != nil {
+		m.setLine(line)
+	}
+}
+
 // LineInfo returns the number of characters from the start of the
 // (soft-wrapped) line and the (soft-wrapped) line width.
 func (m *Model) LineInfo() LineInfo {
-	grid := wrap(m.value[m.row], m.width)
+	grid := wrap(m.currentLine(), m.width)
 
 	// Find out which line we are currently on. This can be determined by the
 	// m.col and counting the number of runes that we need to skip.
@@ -826,8 +1268,8 @@ func (m *Model) moveToBegin() {
 
 // moveToEnd moves the cursor to the end of the input.
 func (m *Model) moveToEnd() {
-	m.row = len(m.value) - 1
-	m.SetCursor(len(m.value[m.row]))
+	m.row = m.value.Len() - 1
+	m.SetCursor(len(m.currentLine()))
 }
 
 // SetWidth sets the width of the textarea to fit exactly within the given width.
@@ -864,6 +1306,7 @@ func (m *Model) SetWidth(w int) {
 	} else {
 		m.width = max(inputWidth, minWidth)
 	}
+	m.value = m.value.Rewrap(m.width)
 }
 
 // SetPromptFunc supersedes the Prompt field and sets a dynamic prompt
@@ -886,11 +1329,144 @@ func (m *Model) Height() int {
 // SetHeight sets the height of the textarea.
 func (m *Model) SetHeight(h int) {
 	m.height = max(h, _minHeight)
-	m.viewport.Height = max(h, _minHeight)
 	if m.MaxHeight > 0 {
 		m.height = min(h, m.MaxHeight)
-		m.viewport.Height = min(h, m.MaxHeight)
 	}
+	// search bar takes rows at the bottom
+	m.viewport.Height = max(_minHeight, m.height-m.Search.Height())
+}
+
+// updateEditor handles key as editor without modes does, recording edits
+// in undo history. Returns false if the rest of update must be skipped.
+func (m *Model) updateEditor(msg tea.MsgKey, f func(...tea.Cmd)) bool {
+	before, kind, ok := m.snapshot(), history.EditOther, true
+	switch {
+	case key.Matches(msg, m.KeyMap.Search):
+		m.openSearch(false, f)
+	case key.Matches(msg, m.KeyMap.Replace):
+		m.openSearch(true, f)
+	case key.Matches(msg, m.KeyMap.AddCursorAbove):
+		m.addCursorVertical(-1)
+	case key.Matches(msg, m.KeyMap.AddCursorBelow):
+		m.addCursorVertical(1)
+	case key.Matches(msg, m.KeyMap.AddCursorNextOccurrence):
+		m.addCursorNextOccurrence()
+	case len(m.cursors) > 0:
+		kind = m.updateCursors(msg, f)
+	case m.updateSelection(msg, f):
+	default:
+		if kind, ok = m.updateKey(msg, f); !ok {
+			return false
+		}
+	}
+
+	if !key.Matches(msg, m.KeyMap.Undo, m.KeyMap.Redo) {
+		m.recordEdit(kind, before)
+	}
+	return true
+}
+
+// updateKey handles editing and navigation key at the cursor, returns kind
+// of edit made and false if the rest of update must be skipped.
+func (m *Model) updateKey(msg tea.MsgKey, f func(...tea.Cmd)) (history.EditKind, bool) {
+	kind := history.EditOther
+	switch {
+	case key.Matches(msg, m.KeyMap.Undo):
+		m.Undo()
+	case key.Matches(msg, m.KeyMap.Redo):
+		m.Redo()
+	case key.Matches(msg, m.KeyMap.DeleteAfterCursor):
+		m.col = clamp(m.col, 0, len(m.currentLine()))
+		if m.col >= len(m.currentLine()) {
+			m.mergeLineBelow(m.row)
+			break
+		}
+		m.deleteAfterCursor()
+	case key.Matches(msg, m.KeyMap.DeleteBeforeCursor):
+		m.col = clamp(m.col, 0, len(m.currentLine()))
+		if m.col <= 0 {
+			m.mergeLineAbove(m.row)
+			break
+		}
+		m.deleteBeforeCursor()
+	case key.Matches(msg, m.KeyMap.DeleteCharacterBackward):
+		kind = history.EditDelete
+		m.col = clamp(m.col, 0, len(m.currentLine()))
+		if m.col <= 0 {
+			m.mergeLineAbove(m.row)
+			break
+		}
+		if line := m.currentLine(); len(line) > 0 {
+			m.setLine(slices.Concat(line[:max(0, m.col-1)], line[m.col:]))
+			if m.col > 0 {
+				m.SetCursor(m.col - 1)
+			}
+		}
+	case key.Matches(msg, m.KeyMap.DeleteCharacterForward):
+		kind = history.EditDelete
+		if line := m.currentLine(); len(line) > 0 && m.col < len(line) {
+			m.setLine(slices.Concat(line[:m.col], line[m.col+1:]))
+		}
+		if m.col >= len(m.currentLine()) {
+			m.mergeLineBelow(m.row)
+			break
+		}
+	case key.Matches(msg, m.KeyMap.DeleteWordBackward):
+		if m.col <= 0 {
+			m.mergeLineAbove(m.row)
+			break
+		}
+		m.deleteWordLeft()
+	case key.Matches(msg, m.KeyMap.DeleteWordForward):
+		m.col = clamp(m.col, 0, len(m.currentLine()))
+		if m.col >= len(m.currentLine()) {
+			m.mergeLineBelow(m.row)
+			break
+		}
+		m.deleteWordRight()
+	case key.Matches(msg, m.KeyMap.InsertNewline):
+		if m.MaxHeight > 0 && m.value.Len() >= m.MaxHeight {
+			return kind, false
+		}
+		m.col = clamp(m.col, 0, len(m.currentLine()))
+		m.splitLine(m.row, m.col)
+	case key.Matches(msg, m.KeyMap.LineEnd):
+		m.CursorEnd()
+	case key.Matches(msg, m.KeyMap.LineStart):
+		m.CursorStart()
+	case key.Matches(msg, m.KeyMap.CharacterForward):
+		m.characterRight()
+	case key.Matches(msg, m.KeyMap.LineNext):
+		m.CursorDown()
+	case key.Matches(msg, m.KeyMap.WordForward):
+		m.wordRight()
+	case key.Matches(msg, m.KeyMap.Paste):
+		f(Paste)
+		return kind, false
+	case key.Matches(msg, m.KeyMap.CharacterBackward):
+		m.characterLeft(false /* insideLine */)
+	case key.Matches(msg, m.KeyMap.LinePrevious):
+		m.CursorUp()
+	case key.Matches(msg, m.KeyMap.WordBackward):
+		m.wordLeft()
+	case key.Matches(msg, m.KeyMap.InputBegin):
+		m.moveToBegin()
+	case key.Matches(msg, m.KeyMap.InputEnd):
+		m.moveToEnd()
+	case key.Matches(msg, m.KeyMap.LowercaseWordForward):
+		m.lowercaseRight()
+	case key.Matches(msg, m.KeyMap.UppercaseWordForward):
+		m.uppercaseRight()
+	case key.Matches(msg, m.KeyMap.CapitalizeWordForward):
+		m.capitalizeRight()
+	case key.Matches(msg, m.KeyMap.TransposeCharacterBackward):
+		m.transposeLeft()
+
+	default:
+		kind = history.EditInsert
+		m.insertRunesFromUserInput(msg.Runes)
+	}
+	return kind, true
 }
 
 // Update is the Tea update loop.
@@ -903,104 +1479,31 @@ func (m *Model) Update(msg tea.Msg, f func(...tea.Cmd)) {
 	// Used to determine if the cursor should blink.
 	oldRow, oldCol := m.cursorLineNumber(), m.col
 
-	if m.value[m.row] == nil {
-		m.value[m.row] = make([]rune, 0)
-	}
-
 	switch msg := msg.(type) {
 	case tea.MsgKey:
 		switch {
-		case key.Matches(msg, m.KeyMap.DeleteAfterCursor):
-			m.col = clamp(m.col, 0, len(m.value[m.row]))
-			if m.col >= len(m.value[m.row]) {
-				m.mergeLineBelow(m.row)
-				break
-			}
-			m.deleteAfterCursor()
-		case key.Matches(msg, m.KeyMap.DeleteBeforeCursor):
-			m.col = clamp(m.col, 0, len(m.value[m.row]))
-			if m.col <= 0 {
-				m.mergeLineAbove(m.row)
-				break
-			}
-			m.deleteBeforeCursor()
-		case key.Matches(msg, m.KeyMap.DeleteCharacterBackward):
-			m.col = clamp(m.col, 0, len(m.value[m.row]))
-			if m.col <= 0 {
-				m.mergeLineAbove(m.row)
-				break
-			}
-			if len(m.value[m.row]) > 0 {
-				m.value[m.row] = append(m.value[m.row][:max(0, m.col-1)], m.value[m.row][m.col:]...)
-				if m.col > 0 {
-					m.SetCursor(m.col - 1)
-				}
-			}
-		case key.Matches(msg, m.KeyMap.DeleteCharacterForward):
-			if len(m.value[m.row]) > 0 && m.col < len(m.value[m.row]) {
-				m.value[m.row] = append(m.value[m.row][:m.col], m.value[m.row][m.col+1:]...)
-			}
-			if m.col >= len(m.value[m.row]) {
-				m.mergeLineBelow(m.row)
-				break
-			}
-		case key.Matches(msg, m.KeyMap.DeleteWordBackward):
-			if m.col <= 0 {
-				m.mergeLineAbove(m.row)
-				break
-			}
-			m.deleteWordLeft()
-		case key.Matches(msg, m.KeyMap.DeleteWordForward):
-			m.col = clamp(m.col, 0, len(m.value[m.row]))
-			if m.col >= len(m.value[m.row]) {
-				m.mergeLineBelow(m.row)
-				break
-			}
-			m.deleteWordRight()
-		case key.Matches(msg, m.KeyMap.InsertNewline):
-			if m.MaxHeight > 0 && len(m.value) >= m.MaxHeight {
+		case m.Search.Active():
+			m.updateSearch(msg, f)
+		case m.vim != nil:
+			m.updateVim(msg, f)
+		default:
+			if !m.updateEditor(msg, f) {
 				return
 			}
-			m.col = clamp(m.col, 0, len(m.value[m.row]))
-			m.splitLine(m.row, m.col)
-		case key.Matches(msg, m.KeyMap.LineEnd):
-			m.CursorEnd()
-		case key.Matches(msg, m.KeyMap.LineStart):
-			m.CursorStart()
-		case key.Matches(msg, m.KeyMap.CharacterForward):
-			m.characterRight()
-		case key.Matches(msg, m.KeyMap.LineNext):
-			m.CursorDown()
-		case key.Matches(msg, m.KeyMap.WordForward):
-			m.wordRight()
-		case key.Matches(msg, m.KeyMap.Paste):
-			f(Paste)
-			return
-		case key.Matches(msg, m.KeyMap.CharacterBackward):
-			m.characterLeft(false /* insideLine */)
-		case key.Matches(msg, m.KeyMap.LinePrevious):
-			m.CursorUp()
-		case key.Matches(msg, m.KeyMap.WordBackward):
-			m.wordLeft()
-		case key.Matches(msg, m.KeyMap.InputBegin):
-			m.moveToBegin()
-		case key.Matches(msg, m.KeyMap.InputEnd):
-			m.moveToEnd()
-		case key.Matches(msg, m.KeyMap.LowercaseWordForward):
-			m.lowercaseRight()
-		case key.Matches(msg, m.KeyMap.UppercaseWordForward):
-			m.uppercaseRight()
-		case key.Matches(msg, m.KeyMap.CapitalizeWordForward):
-			m.capitalizeRight()
-		case key.Matches(msg, m.KeyMap.TransposeCharacterBackward):
-			m.transposeLeft()
-
-		default:
-			m.insertRunesFromUserInput(msg.Runes)
 		}
 
 	case msgPaste:
-		m.insertRunesFromUserInput([]rune(msg))
+		before := m.snapshot()
+		m.paste(string(msg))
+		m.recordEdit(history.EditOther, before)
+
+	case tea.MsgMouse:
+		if msg.Type == tea.MouseRelease {
+			m.dragging = false
+		}
+
+	case tea.MsgMouseZone:
+		m.updateMouse(msg)
 
 	case msgPasteErr:
 		m.Err = msg
@@ -1008,6 +1511,11 @@ func (m *Model) Update(msg tea.Msg, f func(...tea.Cmd)) {
 
 	m.viewport.Update(msg)
 
+	// text changed while searching, e.g. by paste
+	if m.Search.Active() && m.searched != m.value.root {
+		m.findMatches()
+	}
+
 	newRow, newCol := m.cursorLineNumber(), m.col
 	m.Cursor.Update(msg, f)
 	if (newRow != oldRow || newCol != oldCol) && m.Cursor.Mode() == cursor.ModeBlink {
@@ -1025,9 +1533,16 @@ func (m *Model) View(vb tea.Viewbox) {
 	vb = vb.
 		MaxHeight(m.height).
 		MaxWidth(m.width).
-		Styled(m.style.Base)
+		Styled(m.style.Base).
+		Zone(m.id)
+
+	if m.Search.Active() {
+		vbText, vbSearch := vb.SplitY2(tea.Flex(1), tea.Fixed(m.Search.Height()))
+		m.Search.View(vbSearch)
+		vb = vbText
+	}
 
-	if m.Value() == "" && m.row == 0 && m.col == 0 && m.Placeholder != "" {
+	if m.value.Len() == 1 && len(m.value.Line(0)) == 0 && m.Placeholder != "" {
 		m.placeholderView(vb)
 		return
 	}
@@ -1036,9 +1551,20 @@ func (m *Model) View(vb tea.Viewbox) {
 
 	lineInfo := m.LineInfo()
 
-	displayLine := 0
-	for l, line := range m.value {
+	// render only visible lines, starting from the first visible row
+	first, skip := m.value.LineAtRow(m.viewport.YOffset)
+	if m.Highlighter != nil {
+		last, _ := m.value.LineAtRow(m.viewport.YOffset + m.viewport.Height - 1)
+		m.Highlighter.tokenize(m.value, first, min(last, m.value.Len()-1))
+	}
+
+	displayLine := m.viewport.YOffset
+	m.value.Each(first, func(l int, line []rune) bool {
 		wrappedLines := wrap(line, m.width)
+		var tokens []chroma.TokenType
+		if m.Highlighter != nil {
+			tokens = m.Highlighter.line(l)
+		}
 
 		style := fun.IF(
 			m.row == l,
@@ -1046,7 +1572,16 @@ func (m *Model) View(vb tea.Viewbox) {
 			m.style.Text,
 		)
 
+		start := 0 // column of first character of wrapped line
 		for y, wrappedLine := range wrappedLines {
+			if l == first && y < skip {
+				start += len(wrappedLine)
+				continue
+			}
+			if displayLine >= m.viewport.YOffset+m.viewport.Height {
+				return false
+			}
+
 			vbLine := vb.
 				Styled(style).
 				Styled(m.style.Prompt).
@@ -1087,7 +1622,7 @@ func (m *Model) View(vb tea.Viewbox) {
 
 			vbLine = vbLine.Styled(style)
 			if m.row == l && lineInfo.RowOffset == y {
-				vbLine = vbLine.WriteLineX(string(wrappedLine[:lineInfo.ColumnOffset]))
+				vbLine = m.writeSpan(vbLine, l, start, wrappedLine[:lineInfo.ColumnOffset], tokens)
 				if m.col >= len(line) && lineInfo.CharOffset >= m.width {
 					m.Cursor.SetChar(" ")
 					st, cursor := m.Cursor.View()
@@ -1095,23 +1630,23 @@ func (m *Model) View(vb tea.Viewbox) {
 				} else {
 					m.Cursor.SetChar(string(wrappedLine[lineInfo.ColumnOffset]))
 					st, cursor := m.Cursor.View()
-					vbLine = vbLine.
-						Styled(st).
-						WriteLineX(cursor).
-						WriteLineX(string(wrappedLine[lineInfo.ColumnOffset+1:]))
+					vbLine = vbLine.PaddingLeft(vbLine.Styled(st).WriteLine(cursor))
+					vbLine = m.writeSpan(vbLine, l, m.col+1, wrappedLine[lineInfo.ColumnOffset+1:], tokens)
 				}
 			} else {
-				vbLine = vbLine.WriteLineX(string(wrappedLine))
+				vbLine = m.writeSpan(vbLine, l, start, wrappedLine, tokens)
 			}
 			vbLine = vbLine.WriteLineX(strings.Repeat(" ", max(0, padding)))
 
+			start += len(wrappedLines[y])
 			vb = vb.PaddingTop(1)
 		}
-	}
+		return true
+	})
 
 	// Always show at least `m.Height` lines at all times.
 	// To do this we can simply pad out a few extra new lines in the view.
-	for y := 0; y < m.height; y++ {
+	for displayLine < m.viewport.YOffset+m.viewport.Height {
 		vbLine := vb.
 			Styled(m.style.Prompt).
 			WriteLineX(m.getPromptString(displayLine))
@@ -1129,6 +1664,72 @@ func (m *Model) View(vb tea.Viewbox) {
 	// m.viewport.View(vb.Styled(m.style.Base))
 }
 
+// writeSpan writes characters of given row starting at column col,
+// highlighting syntax tokens of the row, if any, search matches and
+// selected characters, and returns viewbox after them.
+func (m *Model) writeSpan(vb tea.Viewbox, row, col int, runes []rune, tokens []chroma.TokenType) tea.Viewbox {
+	// span is characters styled same way
+	type span struct {
+		selected bool
+		token    chroma.TokenType
+		match    search.Match
+		matched  bool
+		cursor   bool
+	}
+	spanAt := func(col int) span {
+		s := span{
+			selected: m.selected(position{row, col}),
+			token:    chroma.Text,
+		}
+		if col < len(tokens) {
+			s.token = tokens[col]
+		}
+		if m.Search.Active() {
+			s.match, s.matched = m.Search.MatchAt(row, col)
+		}
+		if len(m.cursors) > 0 {
+			s.cursor = m.hasExtraCursor(position{row, col})
+		}
+		return s
+	}
+
+	for len(runes) > 0 {
+		s := spanAt(col)
+		n := 1
+		for n < len(runes) && spanAt(col+n) == s {
+			n++
+		}
+
+		text := string(runes[:n])
+		vbSpan := vb.MaxHeight(1).MaxWidth(rw.StringWidth(text))
+		if tokens != nil {
+			// keep background of line, e.g. of cursor line
+			st := m.Highlighter.style(s.token)
+			if st.GetBackground() == nil {
+				st = st.Background(fun.IF(m.row == row, m.style.CursorLine, m.style.Text).GetBackground())
+			}
+			vbSpan = vbSpan.Styled(st)
+		}
+		if s.matched {
+			vbSpan = vbSpan.Styled(m.Search.MatchStyle(s.match))
+		}
+		if s.selected {
+			vbSpan = vbSpan.Styled(m.style.Selection)
+		}
+		if s.cursor {
+			// additional cursors are rendered same way as the main one
+			m.Cursor.SetChar(text)
+			st, cursor := m.Cursor.View()
+			vbSpan.Styled(st).WriteLine(cursor)
+		} else {
+			vbSpan.WriteLine(text)
+		}
+		vb = vb.PaddingLeft(rw.StringWidth(text))
+		runes, col = runes[n:], col+n
+	}
+	return vb
+}
+
 func (m *Model) getPromptString(displayLine int) string {
 	if m.promptFunc == nil {
 		return m.Prompt
@@ -1195,34 +1796,16 @@ func Blink() tea.Msg {
 // cursorLineNumber returns the line number that the cursor is on.
 // This accounts for soft wrapped lines.
 func (m *Model) cursorLineNumber() int {
-	line := 0
-	for i := 0; i < m.row; i++ {
-		// Calculate the number of lines that the current line will be split
-		// into.
-		line += len(wrap(m.value[i], m.width))
-	}
-	line += m.LineInfo().RowOffset
-	return line
+	return m.value.RowOf(m.row) + m.LineInfo().RowOffset
 }
 
 // mergeLineBelow merges the current line the cursor is on with the line below.
 func (m *Model) mergeLineBelow(row int) {
-	if row >= len(m.value)-1 {
+	if row >= m.value.Len()-1 {
 		return
 	}
 
-	// To perform a merge, we will need to combine the two lines and then
-	m.value[row] = append(m.value[row], m.value[row+1]...)
-
-	// Shift all lines up by one
-	for i := row + 1; i < len(m.value)-1; i++ {
-		m.value[i] = m.value[i+1]
-	}
-
-	// And, remove the last line
-	if len(m.value) > 0 {
-		m.value = m.value[:len(m.value)-1]
-	}
+	m.value = m.value.Splice(row, row+2, slices.Concat(m.value.Line(row), m.value.Line(row+1)))
 }
 
 // mergeLineAbove merges the current line the cursor is on with the line above.
@@ -1231,35 +1814,18 @@ func (m *Model) mergeLineAbove(row int) {
 		return
 	}
 
-	m.col = len(m.value[row-1])
+	m.col = len(m.value.Line(row - 1))
 	m.row--
 
-	// To perform a merge, we will need to combine the two lines and then
-	m.value[row-1] = append(m.value[row-1], m.value[row]...)
-
-	// Shift all lines up by one
-	for i := row; i < len(m.value)-1; i++ {
-		m.value[i] = m.value[i+1]
-	}
-
-	// And, remove the last line
-	if len(m.value) > 0 {
-		m.value = m.value[:len(m.value)-1]
-	}
+	m.value = m.value.Splice(row-1, row+1, slices.Concat(m.value.Line(row-1), m.value.Line(row)))
 }
 
 func (m *Model) splitLine(row, col int) {
 	// To perform a split, take the current line and keep the content before
 	// the cursor, take the content after the cursor and make it the content of
 	// the line underneath, and shift the remaining lines down by one
-	head, tailSrc := m.value[row][:col], m.value[row][col:]
-	tail := make([]rune, len(tailSrc))
-	copy(tail, tailSrc)
-
-	m.value = append(m.value[:row+1], m.value[row:]...)
-
-	m.value[row] = head
-	m.value[row+1] = tail
+	line := m.value.Line(row)
+	m.value = m.value.Splice(row, row+1, line[:col:col], line[col:])
 
 	m.col = 0
 	m.row++
@@ -1274,73 +1840,61 @@ func Paste() tea.Msg {
 	return msgPaste(str)
 }
 
+// wrap soft-wraps runes to given width. Wrapped lines are slices of runes,
+// except the last one, which is copied to add trailing space.
 func wrap(runes []rune, width int) [][]rune {
 	var (
-		lines  = [][]rune{{}}
-		word   = []rune{}
-		row    int
+		lines  [][]rune
+		start  int // first rune of current line
+		word   int // first rune of current word
 		spaces int
+		// display widths of current line and word
+		lineWidth, wordWidth int
 	)
 
 	// Word wrap the runes
-	for _, r := range runes {
+	for i, r := range runes {
 		if unicode.IsSpace(r) {
 			spaces++
 		} else {
-			word = append(word, r)
+			wordWidth += rw.RuneWidth(r)
 		}
 
 		if spaces > 0 {
-			if rw.StringWidth(string(lines[row]))+rw.StringWidth(string(word))+spaces > width {
-				row++
-				lines = append(lines, []rune{})
-				lines[row] = append(lines[row], word...)
-				lines[row] = append(lines[row], repeatSpaces(spaces)...)
-				spaces = 0
-				word = nil
-			} else {
-				lines[row] = append(lines[row], word...)
-				lines[row] = append(lines[row], repeatSpaces(spaces)...)
-				spaces = 0
-				word = nil
+			if lineWidth+wordWidth+spaces > width {
+				lines = append(lines, runes[start:word])
+				start, lineWidth = word, 0
 			}
+			lineWidth += wordWidth + spaces
+			word, wordWidth, spaces = i+1, 0, 0
 		} else {
 			// If the last character is a double-width rune, then we may not be able to add it to this line
 			// as it might cause us to go past the width.
-			lastCharLen := rw.RuneWidth(word[len(word)-1])
-			if rw.StringWidth(string(word))+lastCharLen > width {
+			if wordWidth+rw.RuneWidth(r) > width {
 				// If the current line has any content, let's move to the next
 				// line because the current word fills up the entire line.
-				if len(lines[row]) > 0 {
-					row++
-					lines = append(lines, []rune{})
+				if word > start {
+					lines = append(lines, runes[start:word])
+					start, lineWidth = word, 0
 				}
-				lines[row] = append(lines[row], word...)
-				word = nil
+				lineWidth += wordWidth
+				word, wordWidth = i+1, 0
 			}
 		}
 	}
 
-	if rw.StringWidth(string(lines[row]))+rw.StringWidth(string(word))+spaces >= width {
-		lines = append(lines, []rune{})
-		lines[row+1] = append(lines[row+1], word...)
-		// We add an extra space at the end of the line to account for the
-		// trailing space at the end of the previous soft-wrapped lines so that
-		// behaviour when navigating is consistent and so that we don't need to
-		// continually add edges to handle the last line of the wrapped input.
-		spaces++
-		lines[row+1] = append(lines[row+1], repeatSpaces(spaces)...)
-	} else {
-		lines[row] = append(lines[row], word...)
-		spaces++
-		lines[row] = append(lines[row], repeatSpaces(spaces)...)
+	if lineWidth+wordWidth+spaces >= width {
+		lines = append(lines, runes[start:word])
+		start = word
 	}
 
-	return lines
-}
-
-func repeatSpaces(n int) []rune {
-	return []rune(strings.Repeat(string(' '), n))
+	// We add an extra space at the end of the line to account for the
+	// trailing space at the end of the previous soft-wrapped lines so that
+	// behaviour when navigating is consistent and so that we don't need to
+	// continually add edges to handle the last line of the wrapped input.
+	last := make([]rune, 0, len(runes)-start+1)
+	last = append(last, runes[start:]...)
+	return append(lines, append(last, ' '))
 }
 
 func clamp(v, low, high int) int {
@@ -0,0 +1,1126 @@
+package textarea
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/rprtr258/tea"
+	"github.com/rprtr258/tea/components/headless/history"
+)
+
+// Mode is editing mode of text area with vim keys enabled.
+type Mode int
+
+const (
+	// ModeInsert is mode in which keys edit text as usual. Text area without
+	// vim keys is always in this mode.
+	ModeInsert Mode = iota
+	// ModeNormal is mode in which keys are commands, such as motions and
+	// operators.
+	ModeNormal
+	// ModeVisual is mode in which motions select characters.
+	ModeVisual
+	// ModeVisualLine is mode in which motions select whole lines.
+	ModeVisualLine
+)
+
+func (m Mode) String() string {
+	switch m {
+	case ModeInsert:
+		return "INSERT"
+	case ModeNormal:
+		return "NORMAL"
+	case ModeVisual:
+		return "VISUAL"
+	case ModeVisualLine:
+		return "VISUAL LINE"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// register is text yanked or deleted into vim register.
+type register struct {
+	text     string
+	linewise bool
+}
+
+// vimState is state of vim-like modal editing.
+type vimState struct {
+	mode Mode
+	// keys are keys of command being typed.
+	keys []tea.MsgKey
+	// registers are named registers, unnamed one is '"'.
+	registers map[rune]register
+	// visual is position where visual selection started.
+	visual position
+	// want is column vertical motions try to keep.
+	want int
+
+	// change is keys of change being recorded, including keys typed in
+	// insert mode it started.
+	change []tea.MsgKey
+	// lastChange is keys of last change, repeated by ".".
+	lastChange []tea.MsgKey
+	replaying  bool
+}
+
+// vimCommand is parsed vim command, e.g. `"a3dw`.
+type vimCommand struct {
+	register rune
+	// count is count of command, 1 if not given.
+	count   int
+	counted bool
+	// name is operator, motion, text object or other command name.
+	name string
+	// target is motion or text object operator is applied to, or the same
+	// operator for whole lines.
+	target string
+	// char is character argument of f, F, t, T and r.
+	char rune
+}
+
+var (
+	vimOperators = []string{"d", "c", "y"}
+	vimMotions   = []string{
+		"h", "j", "k", "l", "w", "b", "e", "0", "^", "$", "gg", "G", "f", "F", "t", "T",
+		"left", "right", "up", "down", "home", "end", "backspace", " ",
+	}
+	vimCommands = []string{
+		"x", "X", "D", "C", "s", "S", "Y", "p", "P", "J", "r", "u", "ctrl+r", ".",
+		"i", "a", "I", "A", "o", "O", "v", "V", "/", "n", "N",
+	}
+	vimVisualCommands = []string{"x", "X", "D", "C", "s", "S", "Y", "J", "o", "v", "V"}
+	// vimObjects are text objects kinds, following "i" or "a".
+	vimObjects = []string{"w", "W", `"`, "'", "`", "(", ")", "b", "[", "]", "{", "}", "B", "<", ">"}
+	// vimShortcuts are commands which are operators applied to motion.
+	vimShortcuts = map[string][2]string{
+		"x": {"d", "l"},
+		"X": {"d", "h"},
+		"D": {"d", "$"},
+		"C": {"c", "$"},
+		"s": {"c", "l"},
+		"S": {"c", "c"},
+		"Y": {"y", "y"},
+	}
+)
+
+type vimParse int
+
+const (
+	vimIncomplete vimParse = iota
+	vimInvalid
+	vimComplete
+)
+
+// parseVim parses keys of command: ["x][count]name[count][target][char].
+func parseVim(keys []string, visual bool) (vimCommand, vimParse) {
+	i := 0
+	next := func() (string, bool) {
+		if i == len(keys) {
+			return "", false
+		}
+		i++
+		return keys[i-1], true
+	}
+	char := func(k string) (rune, bool) {
+		runes := []rune(k)
+		return runes[0], len(runes) == 1
+	}
+	count := func(k string, ok bool) (int, string, bool) {
+		n := 0
+		for ok && len(k) == 1 && '0' <= k[0] && k[0] <= '9' && (k != "0" || n > 0) {
+			n = n*10 + int(k[0]-'0')
+			k, ok = next()
+		}
+		return n, k, ok
+	}
+	// name reads command name starting with k and its character argument
+	name := func(k string, objects bool) (string, rune, vimParse) {
+		switch {
+		case k == "g":
+			k2, ok := next()
+			if !ok {
+				return "", 0, vimIncomplete
+			}
+			k += k2
+		case objects && (k == "i" || k == "a"):
+			k2, ok := next()
+			if !ok {
+				return "", 0, vimIncomplete
+			}
+			if !slices.Contains(vimObjects, k2) {
+				return "", 0, vimInvalid
+			}
+			return k + k2, 0, vimComplete
+		}
+
+		if !slices.Contains([]string{"f", "F", "t", "T", "r"}, k) {
+			return k, 0, vimComplete
+		}
+
+		arg, ok := next()
+		if !ok {
+			return "", 0, vimIncomplete
+		}
+		c, ok := char(arg)
+		if !ok {
+			return "", 0, vimInvalid
+		}
+		return k, c, vimComplete
+	}
+
+	var cmd vimCommand
+	k, ok := next()
+	if ok && k == `"` {
+		if k, ok = next(); !ok {
+			return cmd, vimIncomplete
+		}
+		if cmd.register, ok = char(k); !ok {
+			return cmd, vimInvalid
+		}
+		k, ok = next()
+	}
+
+	count1, k, ok := count(k, ok)
+	if !ok {
+		return cmd, vimIncomplete
+	}
+
+	var res vimParse
+	if cmd.name, cmd.char, res = name(k, visual); res != vimComplete {
+		return cmd, res
+	}
+
+	count2 := 0
+	switch {
+	case slices.Contains(vimOperators, cmd.name) && !visual:
+		k, ok = next()
+		if count2, k, ok = count(k, ok); !ok {
+			return cmd, vimIncomplete
+		}
+
+		if k == cmd.name {
+			cmd.target = k
+			break
+		}
+
+		if cmd.target, cmd.char, res = name(k, true); res != vimComplete {
+			return cmd, res
+		}
+		if !slices.Contains(vimMotions, cmd.target) && !isVimObject(cmd.target) {
+			return cmd, vimInvalid
+		}
+	case slices.Contains(vimMotions, cmd.name):
+	case visual && (isVimObject(cmd.name) ||
+		slices.Contains(vimOperators, cmd.name) ||
+		slices.Contains(vimVisualCommands, cmd.name)):
+	case !visual && slices.Contains(vimCommands, cmd.name):
+	default:
+		return cmd, vimInvalid
+	}
+
+	cmd.counted = count1 > 0 || count2 > 0
+	cmd.count = max(count1, 1) * max(count2, 1)
+	return cmd, vimComplete
+}
+
+// withCount returns keys of command with its count replaced by given one.
+func withCount(keys []tea.MsgKey, count int) []tea.MsgKey {
+	isDigit := func(k tea.MsgKey) bool {
+		return k.Type == tea.KeyRunes && len(k.Runes) == 1 && unicode.IsDigit(k.Runes[0])
+	}
+
+	i := 0
+	if len(keys) > 1 && keys[0].String() == `"` {
+		i = 2
+	}
+	j := i
+	for j < len(keys) && isDigit(keys[j]) && (j > i || keys[j].Runes[0] != '0') {
+		j++
+	}
+
+	digits := []tea.MsgKey{}
+	for _, r := range strconv.Itoa(count) {
+		digits = append(digits, tea.MsgKey{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	return slices.Concat(keys[:i], digits, keys[j:])
+}
+
+// isVimObject checks whether name is text object, e.g. "iw".
+func isVimObject(name string) bool {
+	return len(name) > 1 && (name[0] == 'i' || name[0] == 'a') && slices.Contains(vimObjects, name[1:])
+}
+
+// SetVim enables or disables vim-like modal editing. Once enabled, text area
+// starts in normal mode.
+func (m *Model) SetVim(enabled bool) {
+	m.selecting = false
+	if !enabled {
+		m.vim = nil
+		return
+	}
+
+	m.vim = &vimState{
+		mode:      ModeNormal,
+		registers: map[rune]register{},
+	}
+	m.vimClamp()
+}
+
+// Mode returns current editing mode, e.g. for status line. It is always
+// ModeInsert if vim keys are disabled.
+func (m *Model) Mode() Mode {
+	if m.vim == nil {
+		return ModeInsert
+	}
+	return m.vim.mode
+}
+
+// PendingKeys returns keys of vim command being typed, e.g. `"a2d`.
+func (m *Model) PendingKeys() string {
+	if m.vim == nil {
+		return ""
+	}
+
+	var sb strings.Builder
+	for _, k := range m.vim.keys {
+		sb.WriteString(k.String())
+	}
+	return sb.String()
+}
+
+// Register returns text in vim register, '"' is unnamed one.
+func (m *Model) Register(name rune) (string, bool) {
+	if m.vim == nil {
+		return "", false
+	}
+
+	r, ok := m.vim.registers[name]
+	return r.text, ok
+}
+
+// updateVim handles key with vim keys enabled.
+func (m *Model) updateVim(msg tea.MsgKey, f func(...tea.Cmd)) {
+	v := m.vim
+	if v.mode == ModeInsert {
+		if msg.Type != tea.KeyEsc {
+			if v.change != nil && !v.replaying {
+				v.change = append(v.change, msg)
+			}
+			m.updateEditor(msg, f)
+			return
+		}
+
+		if v.change != nil && !v.replaying {
+			v.lastChange = append(v.change, msg)
+		}
+		v.change = nil
+		v.mode = ModeNormal
+		// text typed in next insert mode makes its own undo step
+		m.history.Break()
+		m.SetCursor(m.col - 1)
+		m.vimClamp()
+		v.want = m.col
+		return
+	}
+
+	// typed or pasted runes are commands one by one
+	if msg.Type == tea.KeyRunes && len(msg.Runes) > 1 && !msg.Alt {
+		for _, r := range msg.Runes {
+			m.updateVim(tea.MsgKey{Type: tea.KeyRunes, Runes: []rune{r}}, f)
+		}
+		return
+	}
+
+	if msg.Type == tea.KeyEsc {
+		if len(v.keys) == 0 {
+			v.mode = ModeNormal
+			m.selecting = false
+		}
+		v.keys = nil
+		return
+	}
+
+	v.keys = append(v.keys, msg)
+	keys := make([]string, len(v.keys))
+	for i, k := range v.keys {
+		keys[i] = k.String()
+	}
+
+	cmd, res := parseVim(keys, v.mode != ModeNormal)
+	switch res {
+	case vimIncomplete:
+		return
+	case vimInvalid:
+		v.keys = nil
+		return
+	}
+
+	typed := v.keys
+	v.keys = nil
+
+	before := m.snapshot()
+	var change bool
+	if v.mode == ModeNormal {
+		change = m.execVim(cmd, f)
+	} else {
+		m.execVisual(cmd, f)
+	}
+	kind := history.EditOther
+	if v.mode == ModeInsert {
+		// insert mode starts without selection left by mouse, shift keys
+		// or visual mode
+		m.anchor, m.selecting = position{m.row, m.col}, false
+		// change made entering insert mode, e.g. by "cw", and text typed
+		// after it are single undo step
+		kind = history.EditInsert
+	}
+	if cmd.name != "u" && cmd.name != "ctrl+r" && cmd.name != "." {
+		m.recordEdit(kind, before)
+	}
+
+	if change && !v.replaying {
+		if v.mode == ModeInsert {
+			v.change = typed
+		} else {
+			v.lastChange = typed
+		}
+	}
+
+	if v.mode == ModeInsert {
+		return
+	}
+
+	m.vimClamp()
+	switch cmd.name {
+	case "j", "k", "up", "down":
+	case "$", "end":
+		v.want = math.MaxInt
+	default:
+		v.want = m.col
+	}
+	m.vimSyncVisual()
+}
+
+// vimClamp keeps cursor on character, as it can not be past line end outside
+// of insert mode.
+func (m *Model) vimClamp() {
+	if col := min(m.col, len(m.currentLine())-1); col != m.col {
+		m.SetCursor(col)
+	}
+}
+
+// vimSyncVisual sets selection to the one of visual mode, so it is rendered.
+// Character under cursor is selected too in visual mode.
+func (m *Model) vimSyncVisual() {
+	v := m.vim
+	if v.mode != ModeVisual {
+		m.selecting = false
+		return
+	}
+
+	m.selecting = true
+	m.anchor = v.visual
+	if (position{m.row, m.col}).less(v.visual) {
+		m.anchor = m.vimAfter(v.visual)
+	}
+}
+
+// vimVisualLineSelected checks whether row is selected in visual line mode.
+func (m *Model) vimVisualLineSelected(row int) bool {
+	if m.vim == nil || m.vim.mode != ModeVisualLine {
+		return false
+	}
+	return min(m.vim.visual.row, m.row) <= row && row <= max(m.vim.visual.row, m.row)
+}
+
+// execVim executes command in normal mode. Returns whether command is a
+// change to be repeated by ".".
+func (m *Model) execVim(cmd vimCommand, f func(...tea.Cmd)) bool {
+	v := m.vim
+	if s, ok := vimShortcuts[cmd.name]; ok {
+		cmd.name, cmd.target = s[0], s[1]
+	}
+
+	switch {
+	case slices.Contains(vimOperators, cmd.name):
+		return m.vimOperator(cmd, f)
+	case slices.Contains(vimMotions, cmd.name):
+		m.vimMotion(cmd, false)
+		return false
+	}
+
+	line := m.currentLine()
+	switch cmd.name {
+	case "p", "P":
+		m.vimPut(cmd, f)
+	case "J":
+		m.vimJoin(cmd.count)
+	case "r":
+		if m.col+cmd.count > len(line) {
+			return false
+		}
+
+		replaced := slices.Clone(line)
+		for i := range cmd.count {
+			replaced[m.col+i] = cmd.char
+		}
+		m.setLine(replaced)
+		m.SetCursor(m.col + cmd.count - 1)
+	case "u":
+		for range cmd.count {
+			m.Undo()
+		}
+		return false
+	case "ctrl+r":
+		for range cmd.count {
+			m.Redo()
+		}
+		return false
+	case ".":
+		keys := v.lastChange
+		if cmd.counted {
+			keys = withCount(keys, cmd.count)
+		}
+
+		v.replaying = true
+		for _, k := range keys {
+			m.updateVim(k, f)
+		}
+		v.replaying = false
+		return false
+	case "i", "a", "I", "A", "o", "O":
+		switch cmd.name {
+		case "a":
+			m.SetCursor(m.col + 1)
+		case "I":
+			m.SetCursor(firstNonBlank(line))
+		case "A":
+			m.CursorEnd()
+		case "o":
+			m.CursorEnd()
+			m.insertRunesFromUserInput([]rune{'\n'})
+		case "O":
+			row := m.row
+			m.CursorStart()
+			m.insertRunesFromUserInput([]rune{'\n'})
+			m.row = row
+			m.CursorStart()
+		}
+		v.mode = ModeInsert
+	case "v":
+		v.mode, v.visual = ModeVisual, position{m.row, m.col}
+		return false
+	case "V":
+		v.mode, v.visual = ModeVisualLine, position{m.row, m.col}
+		return false
+	case "/":
+		m.openSearch(false, f)
+		return false
+	case "n", "N":
+		if m.Search.Query() == "" {
+			return false
+		}
+
+		m.Search.Find(m.searchLines)
+		if cmd.name == "n" {
+			m.Search.Nearest(m.row, m.col+1)
+		} else {
+			m.Search.Nearest(m.row, m.col)
+			m.Search.Previous()
+		}
+		m.gotoMatch()
+		return false
+	}
+	return true
+}
+
+// execVisual executes command in visual mode. Changes made in visual mode are
+// not repeated by ".".
+func (m *Model) execVisual(cmd vimCommand, f func(...tea.Cmd)) {
+	v := m.vim
+	switch {
+	case slices.Contains(vimMotions, cmd.name):
+		m.vimMotion(cmd, false)
+		return
+	case isVimObject(cmd.name):
+		start, end, ok := m.vimTextObject(cmd.name)
+		if ok && start != end {
+			v.mode, v.visual = ModeVisual, start
+			m.row, m.col = end.row, end.col
+			m.characterLeft(true /* insideLine */)
+		}
+		return
+	}
+
+	start, end := v.visual, position{m.row, m.col}
+	if end.less(start) {
+		start, end = end, start
+	}
+
+	op, linewise := cmd.name, v.mode == ModeVisualLine
+	switch op {
+	case "o":
+		v.visual, m.row, m.col = position{m.row, m.col}, v.visual.row, v.visual.col
+		return
+	case "v", "V":
+		mode := ModeVisual
+		if op == "V" {
+			mode = ModeVisualLine
+		}
+
+		if v.mode == mode {
+			mode = ModeNormal
+		}
+		v.mode = mode
+		return
+	case "J":
+		v.mode = ModeNormal
+		m.row = start.row
+		m.vimJoin(end.row - start.row + 1)
+		return
+	case "x":
+		op = "d"
+	case "s":
+		op = "c"
+	case "X", "D":
+		op, linewise = "d", true
+	case "Y":
+		op, linewise = "y", true
+	case "C", "S":
+		op, linewise = "c", true
+	}
+
+	v.mode = ModeNormal
+	if linewise {
+		m.vimLines(op, cmd.register, start.row, end.row, f)
+	} else {
+		m.vimRange(op, cmd.register, start, m.vimAfter(end), f)
+	}
+}
+
+// vimOperator applies operator to text from cursor to where motion moves it,
+// to text object or to whole lines. Returns whether text is changed.
+func (m *Model) vimOperator(cmd vimCommand, f func(...tea.Cmd)) bool {
+	op := cmd.name
+	if cmd.target == op {
+		m.vimLines(op, cmd.register, m.row, min(m.row+cmd.count-1, m.value.Len()-1), f)
+		return op != "y"
+	}
+
+	start := position{m.row, m.col}
+	end := start
+	line := m.currentLine()
+	switch {
+	case isVimObject(cmd.target):
+		var ok bool
+		if start, end, ok = m.vimTextObject(cmd.target); !ok {
+			return false
+		}
+	case op == "c" && cmd.target == "w" && cmd.count == 1 && m.col < len(line) && !unicode.IsSpace(line[m.col]):
+		// cw changes to the end of word, leaving spaces after it
+		m.wordRight()
+		end = position{m.row, m.col}
+	default:
+		motion := cmd
+		motion.name = cmd.target
+		linewise, inclusive, ok := m.vimMotion(motion, true)
+		end = position{m.row, m.col}
+		m.row, m.col = start.row, start.col
+		if !ok {
+			if op == "c" {
+				m.vim.mode = ModeInsert
+				return true
+			}
+			return false
+		}
+
+		if end.less(start) {
+			start, end = end, start
+		}
+		if linewise {
+			m.vimLines(op, cmd.register, start.row, end.row, f)
+			return op != "y"
+		}
+		if inclusive {
+			end = m.vimAfter(end)
+		}
+	}
+
+	m.vimRange(op, cmd.register, start, end, f)
+	return op != "y"
+}
+
+// vimRange applies operator to text from start to end.
+func (m *Model) vimRange(op string, name rune, start, end position, f func(...tea.Cmd)) {
+	m.vimStore(name, register{text: m.textBetween(start, end)}, op == "y", f)
+	m.row, m.col = start.row, start.col
+	if op == "y" {
+		return
+	}
+
+	m.anchor, m.selecting = start, true
+	m.row, m.col = end.row, end.col
+	m.deleteSelection()
+	if op == "c" {
+		m.vim.mode = ModeInsert
+	}
+}
+
+// vimLines applies operator to lines from first to last inclusive.
+func (m *Model) vimLines(op string, name rune, first, last int, f func(...tea.Cmd)) {
+	lines := make([]string, 0, last-first+1)
+	for row := first; row <= last; row++ {
+		lines = append(lines, string(m.value.Line(row)))
+	}
+	m.vimStore(name, register{text: strings.Join(lines, "\n"), linewise: true}, op == "y", f)
+
+	switch op {
+	case "y":
+		m.row = first
+	case "d":
+		if first == 0 && last == m.value.Len()-1 {
+			m.value = m.value.Splice(first, last+1, []rune{})
+		} else {
+			m.value = m.value.Splice(first, last+1)
+		}
+		m.row = min(first, m.value.Len()-1)
+		m.SetCursor(firstNonBlank(m.currentLine()))
+	case "c":
+		line := m.value.Line(first)
+		indent := slices.Clone(line[:firstNonBlank(line)])
+		m.value = m.value.Splice(first, last+1, indent)
+		m.row = first
+		m.SetCursor(len(indent))
+		m.vim.mode = ModeInsert
+	}
+}
+
+// vimStore saves text to register. Uppercase register name appends to the
+// lowercase one, "_" discards text and "+" copies it to the clipboard. Unnamed
+// register always gets the text, register "0" gets the last yanked text.
+func (m *Model) vimStore(name rune, r register, yank bool, f func(...tea.Cmd)) {
+	registers := m.vim.registers
+	switch {
+	case name == '_':
+		return
+	case name == '+' || name == '*':
+		f(copyToClipboard(r.text))
+	case unicode.IsUpper(name):
+		name = unicode.ToLower(name)
+		if old, ok := registers[name]; ok {
+			sep := ""
+			if old.linewise || r.linewise {
+				sep = "\n"
+			}
+			r = register{old.text + sep + r.text, old.linewise || r.linewise}
+		}
+	}
+
+	if name != 0 && name != '"' {
+		registers[name] = r
+	}
+	registers['"'] = r
+	if yank && name == 0 {
+		registers['0'] = r
+	}
+}
+
+// vimPut pastes register text after cursor, or before it for "P". Linewise
+// text is pasted on lines below or above.
+func (m *Model) vimPut(cmd vimCommand, f func(...tea.Cmd)) {
+	if cmd.register == '+' || cmd.register == '*' {
+		f(Paste)
+		return
+	}
+
+	r, ok := m.vim.registers[cmp.Or(cmd.register, '"')]
+	if !ok || r.text == "" {
+		return
+	}
+
+	if !r.linewise {
+		if cmd.name == "p" && len(m.currentLine()) > 0 {
+			m.SetCursor(m.col + 1)
+		}
+		m.insertRunesFromUserInput([]rune(strings.Repeat(r.text, cmd.count)))
+		m.characterLeft(true /* insideLine */)
+		return
+	}
+
+	text := strings.Repeat("\n"+r.text, cmd.count)
+	row := m.row
+	if cmd.name == "p" {
+		m.CursorEnd()
+		m.insertRunesFromUserInput([]rune(text))
+		row++
+	} else {
+		m.CursorStart()
+		m.insertRunesFromUserInput([]rune(text[1:] + "\n"))
+	}
+	m.row = min(row, m.value.Len()-1)
+	m.SetCursor(firstNonBlank(m.currentLine()))
+}
+
+// vimJoin joins count lines starting with the current one, separating them
+// with space.
+func (m *Model) vimJoin(count int) {
+	for range max(count-1, 1) {
+		if m.row >= m.value.Len()-1 {
+			return
+		}
+
+		line := m.currentLine()
+		next := m.value.Line(m.row + 1)
+		next = next[firstNonBlank(next):]
+
+		sep := []rune{}
+		if len(line) > 0 && len(next) > 0 && next[0] != ')' {
+			sep = []rune{' '}
+		}
+		m.value = m.value.Splice(m.row, m.row+2, slices.Concat(line, sep, next))
+		m.SetCursor(len(line))
+	}
+}
+
+// firstNonBlank returns column of the first non-space character in line.
+func firstNonBlank(line []rune) int {
+	col := 0
+	for col < len(line) && unicode.IsSpace(line[col]) {
+		col++
+	}
+	return col
+}
+
+// vimAfter returns position of next character, line end counts as character.
+func (m *Model) vimAfter(p position) position {
+	switch {
+	case p.col < len(m.value.Line(p.row)):
+		return position{p.row, p.col + 1}
+	case p.row < m.value.Len()-1:
+		return position{p.row + 1, 0}
+	default:
+		return p
+	}
+}
+
+// vimBefore returns position of previous character, line end counts as
+// character.
+func (m *Model) vimBefore(p position) (position, bool) {
+	switch {
+	case p.col > 0:
+		return position{p.row, p.col - 1}, true
+	case p.row > 0:
+		return position{p.row - 1, len(m.value.Line(p.row - 1))}, true
+	default:
+		return p, false
+	}
+}
+
+// runeAt returns character at position, '\n' at line end.
+func (m *Model) runeAt(p position) rune {
+	if line := m.value.Line(p.row); p.col < len(line) {
+		return line[p.col]
+	}
+	return '\n'
+}
+
+// vimMotion moves cursor by motion. Motions moving past line end are allowed
+// for operators only. Returns whether motion is linewise or includes the
+// character it ends on, and whether cursor moved.
+func (m *Model) vimMotion(cmd vimCommand, operator bool) (linewise, inclusive, ok bool) {
+	start := position{m.row, m.col}
+	line := m.currentLine()
+	lastCol := max(len(line)-1, 0)
+	if operator {
+		lastCol = len(line)
+	}
+
+	vertical := func(row int) {
+		m.row = clamp(row, 0, m.value.Len()-1)
+		m.col = min(m.vim.want, max(len(m.currentLine())-1, 0))
+	}
+
+	switch cmd.name {
+	case "h", "left", "backspace":
+		m.SetCursor(max(m.col-cmd.count, 0))
+	case "l", "right", " ":
+		m.SetCursor(min(m.col+cmd.count, lastCol))
+	case "j", "down":
+		vertical(m.row + cmd.count)
+		linewise = true
+	case "k", "up":
+		vertical(m.row - cmd.count)
+		linewise = true
+	case "0", "home":
+		m.CursorStart()
+	case "^":
+		m.SetCursor(firstNonBlank(line))
+	case "$", "end":
+		m.row = min(m.row+cmd.count-1, m.value.Len()-1)
+		m.SetCursor(len(m.currentLine()) - 1)
+		inclusive = len(m.currentLine()) > 0
+	case "gg", "G":
+		row := m.value.Len() - 1
+		switch {
+		case cmd.counted:
+			row = cmd.count - 1
+		case cmd.name == "gg":
+			row = 0
+		}
+		m.row = clamp(row, 0, m.value.Len()-1)
+		m.SetCursor(firstNonBlank(m.currentLine()))
+		linewise = true
+	case "w":
+		for i := range cmd.count {
+			// operators do not join lines with last word
+			m.vimWordForward(operator && i == cmd.count-1)
+		}
+	case "b":
+		for range cmd.count {
+			if !m.hasWordBefore() {
+				break
+			}
+			m.wordLeft()
+		}
+	case "e":
+		for range cmd.count {
+			m.characterRight()
+			m.wordRight()
+			m.characterLeft(false /* insideLine */)
+		}
+		inclusive = true
+	case "f", "t":
+		col := m.col
+		for i := range cmd.count {
+			from := col + 1
+			if cmd.name == "t" && i == 0 {
+				// do not get stuck right before the character
+				from++
+			}
+			if from > len(line) {
+				return false, false, false
+			}
+
+			idx := slices.Index(line[from:], cmd.char)
+			if idx == -1 {
+				return false, false, false
+			}
+			col = from + idx
+		}
+		if cmd.name == "t" {
+			col--
+		}
+		m.SetCursor(col)
+		inclusive = true
+	case "F", "T":
+		col := m.col
+		for i := range cmd.count {
+			to := col
+			if cmd.name == "T" && i == 0 {
+				to--
+			}
+			if to < 0 {
+				return false, false, false
+			}
+
+			idx := -1
+			for j := to - 1; j >= 0 && idx == -1; j-- {
+				if line[j] == cmd.char {
+					idx = j
+				}
+			}
+			if idx == -1 {
+				return false, false, false
+			}
+			col = idx
+		}
+		if cmd.name == "T" {
+			col++
+		}
+		m.SetCursor(col)
+	}
+
+	// inclusive motion staying in place still covers the character
+	return linewise, inclusive, inclusive || position{m.row, m.col} != start
+}
+
+// vimWordForward moves cursor to the start of the next word. Empty line
+// counts as word. If stopAtLineEnd is set, cursor stops at line end instead.
+func (m *Model) vimWordForward(stopAtLineEnd bool) {
+	if line := m.currentLine(); m.col < len(line) && !unicode.IsSpace(line[m.col]) {
+		m.wordRight()
+	}
+
+	for {
+		line := m.currentLine()
+		if m.col < len(line) {
+			if !unicode.IsSpace(line[m.col]) {
+				return
+			}
+			m.SetCursor(m.col + 1)
+			continue
+		}
+
+		if stopAtLineEnd || m.row == m.value.Len()-1 {
+			return
+		}
+		m.row++
+		m.CursorStart()
+		if len(m.currentLine()) == 0 {
+			return
+		}
+	}
+}
+
+// hasWordBefore checks whether there is non-space character before cursor.
+func (m *Model) hasWordBefore() bool {
+	found := false
+	for row := m.row; row >= 0 && !found; row-- {
+		line := m.value.Line(row)
+		if row == m.row {
+			line = line[:min(m.col, len(line))]
+		}
+		found = slices.ContainsFunc(line, func(r rune) bool { return !unicode.IsSpace(r) })
+	}
+	return found
+}
+
+// vimTextObject returns range of text object around cursor, e.g. "iw" for
+// word or "a(" for parentheses along with text in them.
+func (m *Model) vimTextObject(object string) (start, end position, ok bool) {
+	around := object[0] == 'a'
+	switch kind := object[1:]; kind {
+	case "w", "W":
+		return m.wordObject(around)
+	case `"`, "'", "`":
+		return m.quoteObject(rune(kind[0]), around)
+	case "(", ")", "b":
+		return m.bracketObject('(', ')', around)
+	case "[", "]":
+		return m.bracketObject('[', ']', around)
+	case "{", "}", "B":
+		return m.bracketObject('{', '}', around)
+	case "<", ">":
+		return m.bracketObject('<', '>', around)
+	default:
+		return position{}, position{}, false
+	}
+}
+
+// wordObject returns range of word or spaces under cursor. Around word
+// spaces after it are included, or ones before it if there are none after.
+func (m *Model) wordObject(around bool) (start, end position, ok bool) {
+	line := m.currentLine()
+	if len(line) == 0 {
+		return position{}, position{}, false
+	}
+
+	col := min(m.col, len(line)-1)
+	space := unicode.IsSpace(line[col])
+	from, to := col, col+1
+	for from > 0 && unicode.IsSpace(line[from-1]) == space {
+		from--
+	}
+	for to < len(line) && unicode.IsSpace(line[to]) == space {
+		to++
+	}
+
+	if around {
+		switch {
+		case space:
+			for to < len(line) && !unicode.IsSpace(line[to]) {
+				to++
+			}
+		case to < len(line):
+			for to < len(line) && unicode.IsSpace(line[to]) {
+				to++
+			}
+		default:
+			for from > 0 && unicode.IsSpace(line[from-1]) {
+				from--
+			}
+		}
+	}
+	return position{m.row, from}, position{m.row, to}, true
+}
+
+// quoteObject returns range of quoted string under or after cursor on the
+// current line.
+func (m *Model) quoteObject(quote rune, around bool) (start, end position, ok bool) {
+	line := m.currentLine()
+	var quotes []int
+	for i, r := range line {
+		if r == quote {
+			quotes = append(quotes, i)
+		}
+	}
+
+	for i := 0; i+1 < len(quotes); i += 2 {
+		from, to := quotes[i], quotes[i+1]
+		if to < m.col {
+			continue
+		}
+
+		if around {
+			return position{m.row, from}, position{m.row, to + 1}, true
+		}
+		return position{m.row, from + 1}, position{m.row, to}, true
+	}
+	return position{}, position{}, false
+}
+
+// bracketObject returns range of text in brackets around cursor, possibly
+// spanning several lines.
+func (m *Model) bracketObject(open, close rune, around bool) (start, end position, ok bool) {
+	// find unmatched opening bracket before cursor
+	from := position{m.row, m.col}
+	if m.runeAt(from) != open {
+		depth := 0
+		for {
+			if from, ok = m.vimBefore(from); !ok {
+				return position{}, position{}, false
+			}
+
+			r := m.runeAt(from)
+			if r == close {
+				depth++
+			} else if r == open {
+				if depth == 0 {
+					break
+				}
+				depth--
+			}
+		}
+	}
+
+	// find matching closing bracket
+	to, depth := from, 0
+	for {
+		next := m.vimAfter(to)
+		if next == to {
+			return position{}, position{}, false
+		}
+		to = next
+
+		r := m.runeAt(to)
+		if r == open {
+			depth++
+		} else if r == close {
+			if depth == 0 {
+				break
+			}
+			depth--
+		}
+	}
+
+	if around {
+		return from, position{to.row, to.col + 1}, true
+	}
+	return m.vimAfter(from), to, true
+}
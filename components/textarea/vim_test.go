@@ -0,0 +1,143 @@
+package textarea
+
+import (
+	"testing"
+
+	"github.com/rprtr258/assert"
+
+	"github.com/rprtr258/tea"
+)
+
+func TestVim(t *testing.T) {
+	f := func(...tea.Cmd) {}
+	esc := tea.MsgKey{Type: tea.KeyEsc}
+	typeKeys := func(m *Model, keys ...any) {
+		for _, k := range keys {
+			switch k := k.(type) {
+			case string:
+				m.Update(tea.MsgKey{Type: tea.KeyRunes, Runes: []rune(k)}, f)
+			case tea.MsgKey:
+				m.Update(k, f)
+			}
+		}
+	}
+	newModel := func(value string) Model {
+		m := New()
+		m.Focus()
+		m.SetValue(value)
+		m.row, m.col = 0, 0
+		m.SetVim(true)
+		return m
+	}
+
+	t.Run("motions", func(t *testing.T) {
+		m := newModel("one two three\n  four\nfive\nsix")
+		typeKeys(&m, "w")
+		assert.Equal(t, [2]int{0, 4}, [2]int{m.row, m.col})
+		typeKeys(&m, "e")
+		assert.Equal(t, [2]int{0, 6}, [2]int{m.row, m.col})
+		typeKeys(&m, "$")
+		assert.Equal(t, [2]int{0, 12}, [2]int{m.row, m.col})
+		typeKeys(&m, "3j")
+		assert.Equal(t, [2]int{3, 2}, [2]int{m.row, m.col})
+		typeKeys(&m, "gg")
+		assert.Equal(t, [2]int{0, 0}, [2]int{m.row, m.col})
+		typeKeys(&m, "G")
+		assert.Equal(t, 3, m.row)
+		typeKeys(&m, "2G")
+		assert.Equal(t, [2]int{1, 2}, [2]int{m.row, m.col})
+		typeKeys(&m, "b")
+		assert.Equal(t, [2]int{0, 8}, [2]int{m.row, m.col})
+		typeKeys(&m, "Fo")
+		assert.Equal(t, [2]int{0, 6}, [2]int{m.row, m.col})
+		assert.Equal(t, ModeNormal, m.Mode())
+	})
+
+	t.Run("operators", func(t *testing.T) {
+		m := newModel("one two three\nfoo(\"a b\", [x])\nlast")
+		typeKeys(&m, "dw")
+		assert.Equal(t, "two three\nfoo(\"a b\", [x])\nlast", m.Value())
+		typeKeys(&m, "w", "D")
+		assert.Equal(t, "two \nfoo(\"a b\", [x])\nlast", m.Value())
+
+		typeKeys(&m, "j", "fa", `ci"`, "z", esc)
+		assert.Equal(t, "two \nfoo(\"z\", [x])\nlast", m.Value())
+		assert.Equal(t, ModeNormal, m.Mode())
+		typeKeys(&m, "di(")
+		assert.Equal(t, "two \nfoo()\nlast", m.Value())
+
+		typeKeys(&m, "2dd")
+		assert.Equal(t, "two ", m.Value())
+		typeKeys(&m, "u")
+		assert.Equal(t, "two \nfoo()\nlast", m.Value())
+	})
+
+	t.Run("registers", func(t *testing.T) {
+		m := newModel("alpha\nbeta")
+		typeKeys(&m, `"ayy`, "j", `"Ayy`, "yiw")
+		text, _ := m.Register('a')
+		assert.Equal(t, "alpha\nbeta", text)
+		text, _ = m.Register('0')
+		assert.Equal(t, "beta", text)
+
+		typeKeys(&m, `"ap`)
+		assert.Equal(t, "alpha\nbeta\nalpha\nbeta", m.Value())
+		assert.Equal(t, 2, m.row)
+
+		typeKeys(&m, "gg", "x", "$", "p")
+		assert.Equal(t, "lphaa\nbeta\nalpha\nbeta", m.Value())
+	})
+
+	t.Run("repeat", func(t *testing.T) {
+		m := newModel("a b c d")
+		typeKeys(&m, "cw", "x", esc, "w", ".", "w", "2.")
+		assert.Equal(t, "x x x", m.Value())
+
+		m = newModel("1\n2\n3\n4")
+		typeKeys(&m, "A", ";", esc, "j", ".", "j", ".")
+		assert.Equal(t, "1;\n2;\n3;\n4", m.Value())
+	})
+
+	t.Run("visual", func(t *testing.T) {
+		m := newModel("hello world\nfoo\nbar")
+		typeKeys(&m, "v", "e")
+		assert.Equal(t, ModeVisual, m.Mode())
+		assert.Equal(t, "hello", m.Selection()+string(m.currentLine()[m.col]))
+		typeKeys(&m, "d")
+		assert.Equal(t, " world\nfoo\nbar", m.Value())
+		assert.Equal(t, ModeNormal, m.Mode())
+
+		typeKeys(&m, "j", "Vj", "y")
+		text, _ := m.Register('"')
+		assert.Equal(t, "foo\nbar", text)
+		typeKeys(&m, "P")
+		assert.Equal(t, " world\nfoo\nbar\nfoo\nbar", m.Value())
+	})
+
+	t.Run("insert", func(t *testing.T) {
+		// selection left by mouse click is dropped on entering insert mode
+		m := newModel("foo bar\nbazqux")
+		m.Update(tea.MsgMouseZone{ID: m.id, X: 10, Y: 1, Event: tea.MouseEvent{Type: tea.MouseLeft}}, f)
+		m.Update(tea.MsgMouseZone{ID: m.id, X: 10, Y: 1, Event: tea.MouseEvent{Type: tea.MouseRelease}}, f)
+		assert.Equal(t, [2]int{1, 5}, [2]int{m.row, m.col})
+		typeKeys(&m, "S", "x")
+		assert.Equal(t, "foo bar\nx", m.Value())
+
+		m = newModel("foo bar\nbaz")
+		typeKeys(&m, "v", "l", "c", "x")
+		assert.Equal(t, "xo bar\nbaz", m.Value())
+
+		m = newModel("foo bar\nbaz")
+		typeKeys(&m, "i", tea.MsgKey{Type: tea.KeyShiftRight}, esc, "S", "x")
+		assert.Equal(t, "x\nbaz", m.Value())
+
+		// change and typed text are undone at once
+		m = newModel("foo bar")
+		typeKeys(&m, "i", "a", esc, "w", "cw", "qux", esc)
+		assert.Equal(t, "afoo qux", m.Value())
+		typeKeys(&m, "u")
+		assert.Equal(t, "afoo bar", m.Value())
+		typeKeys(&m, "u")
+		assert.Equal(t, "foo bar", m.Value())
+	})
+}
@@ -0,0 +1,156 @@
+package textinput
+
+import (
+	"errors"
+	"unicode"
+)
+
+// Predefined masks for common structured inputs.
+var (
+	MaskCreditCard = NewMask("9999 9999 9999 9999")
+	MaskDate       = NewMask("9999-99-99")
+	MaskTime       = NewMask("99:99")
+	MaskExpiry     = NewMask("99/99")
+	MaskIPv4       = NewMask("9##.9##.9##.9##")
+)
+
+// maskSlot is a single position of mask.
+type maskSlot struct {
+	// literal is character inserted automatically, zero for input positions.
+	literal rune
+	// accept checks whether character can be typed at the position.
+	accept func(rune) bool
+	// optional positions can be skipped by typing the literal after them.
+	optional bool
+}
+
+// Mask restricts characters allowed at each position of input and inserts
+// literal characters, such as separators, automatically.
+type Mask struct {
+	slots []maskSlot
+}
+
+// NewMask creates mask from pattern. Pattern characters are:
+//
+//	9 - digit
+//	a - letter
+//	* - letter or digit
+//	# - optional digit, skipped by typing the literal following it
+//	\ - escapes the next character, so it is literal
+//
+// Any other character is literal.
+func NewMask(pattern string) *Mask {
+	isAlnum := func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }
+
+	mask := &Mask{}
+	runes := []rune(pattern)
+	for i := 0; i < len(runes); i++ {
+		var slot maskSlot
+		switch r := runes[i]; r {
+		case '9':
+			slot.accept = unicode.IsDigit
+		case 'a':
+			slot.accept = unicode.IsLetter
+		case '*':
+			slot.accept = isAlnum
+		case '#':
+			slot.accept, slot.optional = unicode.IsDigit, true
+		case '\\':
+			if i+1 < len(runes) {
+				i++
+			}
+			slot.literal = runes[i]
+		default:
+			slot.literal = r
+		}
+		mask.slots = append(mask.slots, slot)
+	}
+	return mask
+}
+
+// match finds slot from given one at which r can be placed, skipping
+// literals and optional slots.
+func (mask *Mask) match(slot int, r rune) (int, bool) {
+	for j := slot; j < len(mask.slots); j++ {
+		switch s := mask.slots[j]; {
+		case s.literal != 0:
+			if s.literal == r {
+				return j, true
+			}
+		case s.accept(r):
+			return j, true
+		case !s.optional:
+			return 0, false
+		}
+	}
+	return 0, false
+}
+
+// apply fits runes into mask, dropping characters not allowed and inserting
+// literals before accepted ones. Cursor position pos is moved accordingly.
+// Returns also index of the first slot not filled.
+func (mask *Mask) apply(runes []rune, pos int) ([]rune, int, int) {
+	res := make([]rune, 0, len(mask.slots))
+	newPos, slot := -1, 0
+	for i, r := range runes {
+		if i == pos {
+			newPos = len(res)
+		}
+
+		j, ok := mask.match(slot, r)
+		if !ok {
+			continue
+		}
+
+		for ; slot < j; slot++ {
+			if literal := mask.slots[slot].literal; literal != 0 {
+				res = append(res, literal)
+			}
+		}
+		res = append(res, r)
+		slot++
+	}
+	if newPos == -1 {
+		newPos = len(res)
+	}
+	return res, newPos, slot
+}
+
+// Format returns s fitted into mask.
+func (mask *Mask) Format(s string) string {
+	res, _, _ := mask.apply([]rune(s), 0)
+	return string(res)
+}
+
+// Unmask returns characters of s typed by user, without literals.
+func (mask *Mask) Unmask(s string) string {
+	res := []rune{}
+	slot := 0
+	for _, r := range s {
+		j, ok := mask.match(slot, r)
+		if !ok {
+			continue
+		}
+
+		if mask.slots[j].literal == 0 {
+			res = append(res, r)
+		}
+		slot = j + 1
+	}
+	return string(res)
+}
+
+// ErrIncomplete is returned by Mask.Validate if not all required positions
+// are filled.
+var ErrIncomplete = errors.New("input is incomplete")
+
+// Validate checks that all required positions of mask are filled in s.
+func (mask *Mask) Validate(s string) error {
+	_, _, slot := mask.apply([]rune(s), 0)
+	for _, s := range mask.slots[slot:] {
+		if s.literal == 0 && !s.optional {
+			return ErrIncomplete
+		}
+	}
+	return nil
+}
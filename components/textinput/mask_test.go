@@ -0,0 +1,73 @@
+package textinput
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rprtr258/assert"
+
+	"github.com/rprtr258/tea"
+)
+
+func TestMask(t *testing.T) {
+	f := func(...tea.Cmd) {}
+	runes := func(s string) tea.MsgKey {
+		return tea.MsgKey{Type: tea.KeyRunes, Runes: []rune(s)}
+	}
+
+	m := New()
+	m.Focus()
+	m.Mask = MaskCreditCard
+	m.Check = MaskCreditCard.Validate
+
+	// literals are inserted, other characters are dropped
+	m.Update(runes("1234x5"), f)
+	assert.Equal(t, "1234 5", m.Value())
+	assert.Equal(t, 6, m.Position())
+	assert.Equal(t, ErrIncomplete, m.Err)
+
+	// deleting in the middle shifts characters after cursor
+	m.SetCursor(2)
+	m.Update(tea.MsgKey{Type: tea.KeyBackspace}, f)
+	assert.Equal(t, "1345", m.Value())
+	assert.Equal(t, 1, m.Position())
+
+	m.CursorEnd()
+	m.Update(runes("678901234567"), f)
+	assert.Equal(t, "1345 6789 0123 4567", m.Value())
+	assert.NoError(t, m.Err)
+	assert.Equal(t, "1345678901234567", MaskCreditCard.Unmask(m.Value()))
+
+	// optional positions are skipped by typing the literal after them
+	assert.Equal(t, "10.0.255.1", MaskIPv4.Format("10.0.2551"))
+	assert.NoError(t, MaskIPv4.Validate("10.0.255.1"))
+	assert.Equal(t, ErrIncomplete, MaskIPv4.Validate("10.0"))
+}
+
+func TestParsers(t *testing.T) {
+	percent := Int().Where(InRange(0, 100))
+	v, err := percent("42")
+	assert.NoError(t, err)
+	assert.Equal(t, 42, v)
+	assert.Equal(t, "must be between 0 and 100", percent.Validate("142").Error())
+	assert.Equal(t, ErrRequired, percent.Validate(" "))
+	assert.Equal(t, "not an integer", percent.Validate("4x").Error())
+
+	d, err := Duration()("1h30m")
+	assert.NoError(t, err)
+	assert.Equal(t, 90*time.Minute, d)
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+	date := Date(time.DateOnly).Where(DateInRange(time.DateOnly, from, to))
+	assert.NoError(t, date.Validate("2024-02-29"))
+	assert.Equal(t, "must be between 2024-01-01 and 2024-12-31", date.Validate("2025-01-01").Error())
+
+	// checked input keeps invalid value, unlike validated one
+	m := New()
+	m.Focus()
+	m.Check = percent.Validate
+	m.Update(tea.MsgKey{Type: tea.KeyRunes, Runes: []rune("1000")}, func(...tea.Cmd) {})
+	assert.Equal(t, "1000", m.Value())
+	assert.True(t, m.Err != nil)
+}
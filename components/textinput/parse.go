@@ -0,0 +1,97 @@
+package textinput
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrRequired is returned by parsers for empty input.
+var ErrRequired = errors.New("value is required")
+
+// Parser parses input value into typed one, returning error if value is
+// invalid. Its Validate method can be used as Model.Check.
+type Parser[T any] func(string) (T, error)
+
+// Validate checks that s parses, discarding parsed value.
+func (p Parser[T]) Validate(s string) error {
+	_, err := p(s)
+	return err
+}
+
+// Where returns parser additionally checking parsed value.
+func (p Parser[T]) Where(check func(T) error) Parser[T] {
+	return func(s string) (T, error) {
+		v, err := p(s)
+		if err != nil {
+			return v, err
+		}
+		return v, check(v)
+	}
+}
+
+// parser makes Parser from parse function, trimming spaces and reporting
+// empty input and parse errors uniformly.
+func parser[T any](parse func(string) (T, error), invalid string) Parser[T] {
+	return func(s string) (T, error) {
+		var zero T
+		s = strings.TrimSpace(s)
+		if s == "" {
+			return zero, ErrRequired
+		}
+
+		v, err := parse(s)
+		if err != nil {
+			return zero, errors.New(invalid)
+		}
+		return v, nil
+	}
+}
+
+// Int parses integer.
+func Int() Parser[int] {
+	return parser(strconv.Atoi, "not an integer")
+}
+
+// Float parses floating point number.
+func Float() Parser[float64] {
+	return parser(func(s string) (float64, error) {
+		return strconv.ParseFloat(s, 64)
+	}, "not a number")
+}
+
+// Duration parses duration, such as "1h30m".
+func Duration() Parser[time.Duration] {
+	return parser(time.ParseDuration, "not a duration, e.g. 1h30m")
+}
+
+// Date parses date in given layout, such as time.DateOnly.
+func Date(layout string) Parser[time.Time] {
+	return parser(func(s string) (time.Time, error) {
+		return time.Parse(layout, s)
+	}, "not a date, e.g. "+layout)
+}
+
+// InRange checks that value is between lo and hi inclusive.
+func InRange[T cmp.Ordered](lo, hi T) func(T) error {
+	return func(v T) error {
+		if v < lo || v > hi {
+			return fmt.Errorf("must be between %v and %v", lo, hi)
+		}
+		return nil
+	}
+}
+
+// DateInRange checks that date is between from and to inclusive, formatting
+// them in layout for error message.
+func DateInRange(layout string, from, to time.Time) func(time.Time) error {
+	return func(t time.Time) error {
+		if t.Before(from) || t.After(to) {
+			return fmt.Errorf("must be between %s and %s", from.Format(layout), to.Format(layout))
+		}
+		return nil
+	}
+}
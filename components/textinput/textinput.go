@@ -11,6 +11,7 @@ import (
 	"github.com/rprtr258/fun"
 	"github.com/rprtr258/tea"
 	"github.com/rprtr258/tea/components/cursor"
+	"github.com/rprtr258/tea/components/headless/history"
 	"github.com/rprtr258/tea/components/key"
 	"github.com/rprtr258/tea/components/runeutil"
 	"github.com/rprtr258/tea/styles"
@@ -58,6 +59,8 @@ type KeyMap struct {
 	AcceptSuggestion        key.Binding
 	NextSuggestion          key.Binding
 	PrevSuggestion          key.Binding
+	Undo                    key.Binding
+	Redo                    key.Binding
 }
 
 // DefaultKeyMap is the default set of key bindings for navigating and acting
@@ -79,6 +82,8 @@ var DefaultKeyMap = KeyMap{
 	AcceptSuggestion:        key.Binding{Keys: []string{"tab"}},
 	NextSuggestion:          key.Binding{Keys: []string{"down", "ctrl+n"}},
 	PrevSuggestion:          key.Binding{Keys: []string{"up", "ctrl+p"}},
+	Undo:                    key.Binding{Keys: []string{"ctrl+z"}},
+	Redo:                    key.Binding{Keys: []string{"ctrl+y"}},
 }
 
 // Model is the Tea model for this text input element.
@@ -97,6 +102,7 @@ type Model struct {
 	TextStyle        styles.Style
 	PlaceholderStyle styles.Style
 	CompletionStyle  styles.Style
+	ErrorStyle       styles.Style
 
 	// CharLimit is the maximum amount of characters this input element will accept.
 	// If 0 or less, there's no limit.
@@ -130,6 +136,19 @@ type Model struct {
 	// If the function is not defined, all input is considered valid.
 	Validate ValidateFunc
 
+	// Check is a function that checks whether the text is complete and
+	// correct, e.g. parses as a number in range. Unlike Validate, it does not
+	// reject input, its error is kept in the `Err` field while user types.
+	Check ValidateFunc
+
+	// Mask restricts characters at each position and inserts literals, such
+	// as separators, while user types. If nil, input is not masked.
+	Mask *Mask
+
+	// ShowError renders `Err` on the line below the input, if viewbox has
+	// room for it.
+	ShowError bool
+
 	// rune sanitizer for input
 	rsan runeutil.Sanitizer
 
@@ -140,6 +159,15 @@ type Model struct {
 	suggestions            [][]rune
 	matchedSuggestions     [][]rune
 	currentSuggestionIndex int
+
+	// history of edits for undo and redo
+	history *history.Undo[snapshot]
+}
+
+// snapshot is the text input state saved in undo history.
+type snapshot struct {
+	value string
+	pos   int
 }
 
 // New creates a new model with default settings.
@@ -151,6 +179,7 @@ func New() Model {
 		PlaceholderStyle: styles.Style{}.Foreground(styles.FgColor("240")),
 		ShowSuggestions:  false,
 		CompletionStyle:  styles.Style{}.Foreground(styles.FgColor("240")),
+		ErrorStyle:       styles.Style{}.Foreground(styles.FgColor("9")),
 		Cursor:           cursor.New(),
 		KeyMap:           DefaultKeyMap,
 
@@ -158,6 +187,7 @@ func New() Model {
 		value:       nil,
 		focus:       false,
 		pos:         0,
+		history:     history.NewUndo(snapshot{}),
 
 		// Textinput has all its input on a single line so collapse
 		// newlines/tabs to single spaces.
@@ -176,11 +206,17 @@ func (m *Model) SetValue(s string) {
 	m.setValueInternal(runes)
 }
 
-func (m *Model) setValueInternal(runes []rune) {
+// setValueInternal sets value unless Validate rejects it, returns whether
+// value is set.
+func (m *Model) setValueInternal(runes []rune) bool {
+	if m.Mask != nil {
+		runes, m.pos, _ = m.Mask.apply(runes, m.pos)
+	}
+
 	if m.Validate != nil {
 		if err := m.Validate(string(runes)); err != nil {
 			m.Err = err
-			return
+			return false
 		}
 	}
 
@@ -195,7 +231,24 @@ func (m *Model) setValueInternal(runes []rune) {
 	if m.pos == 0 && empty || m.pos > len(m.value) {
 		m.SetCursor(len(m.value))
 	}
+	m.check()
 	m.handleOverflow()
+	return true
+}
+
+// format fits value into mask after it is edited and checks it.
+func (m *Model) format() {
+	if m.Mask != nil {
+		m.value, m.pos, _ = m.Mask.apply(m.value, m.pos)
+	}
+	m.check()
+}
+
+// check sets `Err` to the result of Check, if it is set.
+func (m *Model) check() {
+	if m.Check != nil {
+		m.Err = m.Check(string(m.value))
+	}
 }
 
 // Value returns the value of the text input.
@@ -248,6 +301,46 @@ func (m *Model) Blur() {
 func (m *Model) Reset() {
 	m.value = nil
 	m.SetCursor(0)
+	m.history.Reset(m.snapshot())
+}
+
+func (m *Model) snapshot() snapshot {
+	return snapshot{
+		value: string(m.value),
+		pos:   m.pos,
+	}
+}
+
+func (m *Model) restore(s snapshot, ok bool) {
+	if !ok {
+		return
+	}
+
+	m.value = []rune(s.value)
+	m.SetCursor(s.pos)
+}
+
+// recordEdit saves edit made since before state to undo history, if the
+// value has changed.
+func (m *Model) recordEdit(kind history.EditKind, before snapshot) {
+	after := m.snapshot()
+	if after.value == before.value {
+		m.history.Break()
+		return
+	}
+
+	m.history.Edit(kind, before, after)
+}
+
+// Undo reverts the last edit, restoring the cursor position before it.
+// Consecutive typing or deleting of characters is reverted at once.
+func (m *Model) Undo() {
+	m.restore(m.history.Undo())
+}
+
+// Redo reapplies the last undone edit.
+func (m *Model) Redo() {
+	m.restore(m.history.Redo())
 }
 
 // SetSuggestions sets the suggestions for the input.
@@ -301,9 +394,7 @@ func (m *Model) insertRunesFromUserInput(v []rune) {
 	}
 
 	// Put it all back together
-	m.setValueInternal(append(head, tail...))
-
-	if m.Err != nil {
+	if !m.setValueInternal(append(head, tail...)) {
 		m.pos = oldPos
 	}
 }
@@ -538,8 +629,10 @@ func (m *Model) Update(msg tea.Msg, f func(...tea.Cmd)) {
 	keyMsg, ok := msg.(tea.MsgKey)
 	if ok && key.Matches(keyMsg, m.KeyMap.AcceptSuggestion) {
 		if m.canAcceptSuggestion() {
+			before := m.snapshot()
 			m.value = append(m.value, m.matchedSuggestions[m.currentSuggestionIndex][len(m.value):]...)
 			m.CursorEnd()
+			m.recordEdit(history.EditOther, before)
 		}
 	}
 
@@ -549,11 +642,17 @@ func (m *Model) Update(msg tea.Msg, f func(...tea.Cmd)) {
 
 	switch msg := msg.(type) {
 	case tea.MsgKey:
+		before, kind := m.snapshot(), history.EditOther
 		switch {
+		case key.Matches(msg, m.KeyMap.Undo):
+			m.Undo()
+		case key.Matches(msg, m.KeyMap.Redo):
+			m.Redo()
 		case key.Matches(msg, m.KeyMap.DeleteWordBackward):
 			m.Err = nil
 			m.deleteWordBackward()
 		case key.Matches(msg, m.KeyMap.DeleteCharacterBackward):
+			kind = history.EditDelete
 			m.Err = nil
 			if len(m.value) > 0 {
 				m.value = append(m.value[:max(0, m.pos-1)], m.value[m.pos:]...)
@@ -576,6 +675,7 @@ func (m *Model) Update(msg tea.Msg, f func(...tea.Cmd)) {
 		case key.Matches(msg, m.KeyMap.LineStart):
 			m.CursorStart()
 		case key.Matches(msg, m.KeyMap.DeleteCharacterForward):
+			kind = history.EditDelete
 			if len(m.value) > 0 && m.pos < len(m.value) {
 				m.value = append(m.value[:m.pos], m.value[m.pos+1:]...)
 			}
@@ -596,15 +696,23 @@ func (m *Model) Update(msg tea.Msg, f func(...tea.Cmd)) {
 			m.previousSuggestion()
 		default:
 			// Input one or more regular characters.
+			kind = history.EditInsert
 			m.insertRunesFromUserInput(msg.Runes)
 		}
+		m.format()
+
+		if !key.Matches(msg, m.KeyMap.Undo, m.KeyMap.Redo) {
+			m.recordEdit(kind, before)
+		}
 
 		// Check again if can be completed
 		// because value might be something that does not match the completion prefix
 		m.updateSuggestions()
 
 	case pasteMsg:
+		before := m.snapshot()
 		m.insertRunesFromUserInput([]rune(msg))
+		m.recordEdit(history.EditOther, before)
 
 	case pasteErrMsg:
 		m.Err = msg
@@ -622,6 +730,10 @@ func (m *Model) Update(msg tea.Msg, f func(...tea.Cmd)) {
 
 // View renders the textinput in its current state.
 func (m Model) View(vb tea.Viewbox) {
+	if m.ShowError && m.Err != nil && vb.Height > 1 {
+		vb.PaddingTop(1).Styled(m.ErrorStyle).WriteLine(m.Err.Error())
+	}
+
 	// Placeholder text
 	if len(m.value) == 0 && m.Placeholder != "" {
 		m.viewPlaceholder(vb)
@@ -760,12 +872,20 @@ func (m *Model) updateSuggestions() {
 	m.matchedSuggestions = matches
 }
 
-// nextSuggestion selects the next suggestion.
+// nextSuggestion selects the next suggestion, if there are any.
 func (m *Model) nextSuggestion() {
+	if len(m.matchedSuggestions) == 0 {
+		return
+	}
+
 	m.currentSuggestionIndex = (m.currentSuggestionIndex + 1) % len(m.matchedSuggestions)
 }
 
-// previousSuggestion selects the previous suggestion.
+// previousSuggestion selects the previous suggestion, if there are any.
 func (m *Model) previousSuggestion() {
-	m.currentSuggestionIndex = (m.currentSuggestionIndex - 1) % len(m.matchedSuggestions)
+	if len(m.matchedSuggestions) == 0 {
+		return
+	}
+
+	m.currentSuggestionIndex = (m.currentSuggestionIndex - 1 + len(m.matchedSuggestions)) % len(m.matchedSuggestions)
 }
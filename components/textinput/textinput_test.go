@@ -0,0 +1,32 @@
+package textinput
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/rprtr258/assert"
+
+	"github.com/rprtr258/tea"
+)
+
+func TestViewError(t *testing.T) {
+	m := New()
+	m.Prompt = "> "
+	m.SetValue("abc")
+	m.ShowError = true
+	m.Err = errors.New("invalid")
+
+	for name, test := range map[string]struct {
+		height   int
+		expected []string
+	}{
+		"below input": {height: 2, expected: []string{"> abc", "invalid"}},
+		"no room":     {height: 1, expected: []string{"> abc"}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			vb := tea.NewViewbox(test.height, 12)
+			m.View(vb)
+			assert.Equal(t, test.expected, vb.Lines())
+		})
+	}
+}
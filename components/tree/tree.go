@@ -0,0 +1,468 @@
+// Package tree provides tree view component over hierachy.Hierachy, with
+// optional extra columns, making it a tree table.
+package tree
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/mattn/go-runewidth"
+
+	"github.com/rprtr258/tea"
+	"github.com/rprtr258/tea/components/headless/hierachy"
+	"github.com/rprtr258/tea/components/key"
+	"github.com/rprtr258/tea/components/textinput"
+	"github.com/rprtr258/tea/components/viewport"
+	"github.com/rprtr258/tea/styles"
+)
+
+// _gap is number of cells between columns.
+const _gap = 2
+
+// Column is extra column shown to the right of the tree.
+type Column[T any] struct {
+	Title string
+	Get   func(T) string
+	// Width of column, zero means width fitting its content.
+	Width int
+}
+
+// MsgLoaded is sent when children of lazy node are loaded.
+type MsgLoaded[T any] struct {
+	// tree is id of tree the node belongs to
+	tree string
+	// Node is id of node in hierachy.
+	Node     int
+	Children []hierachy.Node[T]
+	Err      error
+}
+
+// _lastID is used to generate unique ids of trees.
+var _lastID atomic.Int64
+
+// KeyMap defines keybindings.
+type KeyMap struct {
+	Up, Down         key.Binding
+	PageUp, PageDown key.Binding
+	GotoTop, GotoEnd key.Binding
+	Collapse, Expand key.Binding
+	Toggle           key.Binding
+	Filter           key.Binding
+	AcceptFilter     key.Binding
+	CancelFilter     key.Binding
+}
+
+// DefaultKeyMap is a default set of keybindings.
+var DefaultKeyMap = KeyMap{
+	Up: key.Binding{
+		Keys: []string{"up", "k"},
+		Help: key.Help{"↑/k", "up"},
+	},
+	Down: key.Binding{
+		Keys: []string{"down", "j"},
+		Help: key.Help{"↓/j", "down"},
+	},
+	PageUp: key.Binding{
+		Keys: []string{"pgup", "b"},
+		Help: key.Help{"b/pgup", "page up"},
+	},
+	PageDown: key.Binding{
+		Keys: []string{"pgdown", "f"},
+		Help: key.Help{"f/pgdn", "page down"},
+	},
+	GotoTop: key.Binding{
+		Keys: []string{"home", "g"},
+		Help: key.Help{"g/home", "go to start"},
+	},
+	GotoEnd: key.Binding{
+		Keys: []string{"end", "G"},
+		Help: key.Help{"G/end", "go to end"},
+	},
+	Collapse: key.Binding{
+		Keys: []string{"left", "h"},
+		Help: key.Help{"←/h", "collapse"},
+	},
+	Expand: key.Binding{
+		Keys: []string{"right", "l"},
+		Help: key.Help{"→/l", "expand"},
+	},
+	Toggle: key.Binding{
+		Keys: []string{"enter", " "},
+		Help: key.Help{"enter", "toggle"},
+	},
+	Filter: key.Binding{
+		Keys: []string{"/"},
+		Help: key.Help{"/", "filter"},
+	},
+	AcceptFilter: key.Binding{
+		Keys: []string{"enter"},
+		Help: key.Help{"enter", "apply filter"},
+	},
+	CancelFilter: key.Binding{
+		Keys: []string{"esc"},
+		Help: key.Help{"esc", "cancel filter"},
+	},
+}
+
+// Styles contains style definitions for tree.
+type Styles struct {
+	Header styles.Style
+	// Guide is style of indentation guides.
+	Guide    styles.Style
+	Marker   styles.Style
+	Selected styles.Style
+	// Match is style of nodes matching filter, Ancestor is style of nodes
+	// shown only because their descendants match.
+	Match    styles.Style
+	Ancestor styles.Style
+	Error    styles.Style
+}
+
+// DefaultStyles is a default set of style definitions.
+var DefaultStyles = Styles{
+	Header:   styles.Style{}.Bold(true),
+	Guide:    styles.Style{}.Foreground(styles.FgColor("240")),
+	Marker:   styles.Style{}.Foreground(styles.FgColor("169")),
+	Selected: styles.Style{}.Foreground(styles.FgColor("170")).Bold(true),
+	Match:    styles.Style{}.Foreground(styles.FgColor("212")),
+	Ancestor: styles.Style{}.Faint(),
+	Error:    styles.Style{}.Foreground(styles.FgColor("9")),
+}
+
+// Model is tree view state.
+type Model[T any] struct {
+	Tree *hierachy.Hierachy[T]
+	// Title returns text shown for node, it is matched by filter too.
+	Title func(T) string
+	// Load loads children of lazy node when it is expanded first time.
+	// Nodes without Load are expanded with no children.
+	Load func(T) ([]hierachy.Node[T], error)
+	// Header is title of tree column, shown with Columns.
+	Header  string
+	Columns []Column[T]
+
+	KeyMap KeyMap
+	Styles Styles
+
+	// id prefixes mouse zone ids of rows and markers
+	id string
+	// loading are ids of nodes which children are being loaded
+	loading map[int]bool
+	// errs are errors of loading children by node id
+	errs      map[int]error
+	filter    textinput.Model
+	filtering bool
+	viewport  viewport.Model
+}
+
+// New creates tree view of root node and its descendants.
+func New[T any](root hierachy.Node[T], title func(T) string) Model[T] {
+	filter := textinput.New()
+	filter.Prompt = "/ "
+
+	return Model[T]{
+		Tree:     hierachy.New(root),
+		Title:    title,
+		KeyMap:   DefaultKeyMap,
+		Styles:   DefaultStyles,
+		id:       fmt.Sprintf("tree-%d", _lastID.Add(1)),
+		loading:  map[int]bool{},
+		errs:     map[int]error{},
+		filter:   filter,
+		viewport: viewport.New(0, 0),
+	}
+}
+
+// Update is the Tea update loop.
+func (m *Model[T]) Update(msg tea.Msg, f func(...tea.Cmd)) {
+	switch msg := msg.(type) {
+	case MsgLoaded[T]:
+		m.loaded(msg)
+	case tea.MsgMouseZone:
+		m.updateMouse(msg, f)
+	case tea.MsgKey:
+		if m.filtering {
+			m.updateFilter(msg, f)
+			return
+		}
+
+		pos := m.Tree.SelectedPos()
+		switch {
+		case key.Matches(msg, m.KeyMap.Up):
+			m.Tree.GoPrevOrUp()
+		case key.Matches(msg, m.KeyMap.Down):
+			m.Tree.GoNextOrUp()
+		case key.Matches(msg, m.KeyMap.PageUp):
+			m.Tree.SelectPos(pos - m.viewport.Height)
+		case key.Matches(msg, m.KeyMap.PageDown):
+			m.Tree.SelectPos(pos + m.viewport.Height)
+		case key.Matches(msg, m.KeyMap.GotoTop):
+			m.Tree.SelectPos(0)
+		case key.Matches(msg, m.KeyMap.GotoEnd):
+			m.Tree.SelectPos(m.Tree.Len() - 1)
+		case key.Matches(msg, m.KeyMap.Collapse):
+			if m.Tree.IsCollapsed() || m.Tree.IsFiltered() {
+				m.Tree.GoUp()
+			} else {
+				m.Tree.ToggleCollapsed()
+			}
+		case key.Matches(msg, m.KeyMap.Expand):
+			if m.Tree.IsCollapsed() {
+				m.Expand(f)
+			} else {
+				m.Tree.GoDown()
+			}
+		case key.Matches(msg, m.KeyMap.Toggle):
+			m.Toggle(f)
+		case key.Matches(msg, m.KeyMap.Filter):
+			m.filtering = true
+			f(m.filter.Focus()...)
+		}
+	}
+}
+
+// updateMouse selects clicked node, click on expand marker toggles it.
+func (m *Model[T]) updateMouse(msg tea.MsgMouseZone, f func(...tea.Cmd)) {
+	id, ok := strings.CutPrefix(msg.ID, m.id+"-")
+	if !ok || msg.Event.Type != tea.MouseLeft {
+		return
+	}
+
+	id, marker := strings.CutPrefix(id, "marker-")
+	node, err := strconv.Atoi(id)
+	if err != nil || !m.Tree.Select(node) {
+		return
+	}
+
+	if marker {
+		m.Toggle(f)
+	}
+}
+
+// updateFilter handles keys while filter is being typed.
+func (m *Model[T]) updateFilter(msg tea.MsgKey, f func(...tea.Cmd)) {
+	switch {
+	case key.Matches(msg, m.KeyMap.AcceptFilter):
+		m.filtering = false
+		m.filter.Blur()
+	case key.Matches(msg, m.KeyMap.CancelFilter):
+		m.filtering = false
+		m.filter.Blur()
+		m.SetFilter("")
+	default:
+		query := m.filter.Value()
+		m.filter.Update(msg, f)
+		if m.filter.Value() != query {
+			m.SetFilter(m.filter.Value())
+		}
+	}
+}
+
+// SetFilter shows only nodes which title contains query, case insensitive,
+// and their ancestors. Empty query shows all nodes.
+func (m *Model[T]) SetFilter(query string) {
+	m.filter.SetValue(query)
+	if query == "" {
+		m.Tree.SetFilter(nil)
+		return
+	}
+
+	query = strings.ToLower(query)
+	m.Tree.SetFilter(func(v T) bool {
+		return strings.Contains(strings.ToLower(m.Title(v)), query)
+	})
+}
+
+// FilterValue returns current filter query.
+func (m *Model[T]) FilterValue() string {
+	return m.filter.Value()
+}
+
+// Filtering returns whether filter query is being typed.
+func (m *Model[T]) Filtering() bool {
+	return m.filtering
+}
+
+// Expand expands selected node. Children of lazy node are loaded first.
+func (m *Model[T]) Expand(f func(...tea.Cmd)) {
+	if !m.Tree.IsLazy() || m.Load == nil {
+		if m.Tree.IsCollapsed() {
+			m.Tree.ToggleCollapsed()
+		}
+		return
+	}
+
+	id := m.Tree.SelectedID()
+	if m.loading[id] {
+		return
+	}
+
+	m.loading[id] = true
+	delete(m.errs, id)
+	load, value, tree := m.Load, m.Tree.Selected(), m.id
+	f(func() tea.Msg {
+		children, err := load(value)
+		return MsgLoaded[T]{tree: tree, Node: id, Children: children, Err: err}
+	})
+}
+
+// Toggle expands or collapses selected node.
+func (m *Model[T]) Toggle(f func(...tea.Cmd)) {
+	if m.Tree.IsCollapsed() {
+		m.Expand(f)
+	} else {
+		m.Tree.ToggleCollapsed()
+	}
+}
+
+// Loading returns whether children of any node are being loaded.
+func (m *Model[T]) Loading() bool {
+	return len(m.loading) > 0
+}
+
+// loaded sets children of lazy node, error is shown next to it and expanding
+// it again retries loading.
+func (m *Model[T]) loaded(msg MsgLoaded[T]) {
+	if msg.tree != m.id {
+		return
+	}
+
+	delete(m.loading, msg.Node)
+	if msg.Err != nil {
+		m.errs[msg.Node] = msg.Err
+		return
+	}
+
+	m.Tree.SetChildren(msg.Node, msg.Children)
+}
+
+// row is shown node with its indentation guides.
+type row[T any] struct {
+	hierachy.IterItem[T]
+	guides string
+}
+
+// rows returns shown nodes.
+func (m *Model[T]) rows() []row[T] {
+	var rows []row[T]
+	// last[depth] is whether the last visited node at depth is the last
+	// child, its descendants have no guide at that depth then
+	var last []bool
+	m.Tree.Iter(func(item hierachy.IterItem[T]) bool {
+		var guides strings.Builder
+		for depth := 1; depth < item.Depth; depth++ {
+			if last[depth] {
+				guides.WriteString("   ")
+			} else {
+				guides.WriteString("│  ")
+			}
+		}
+		if item.Depth > 0 {
+			if item.IsLast {
+				guides.WriteString("└─ ")
+			} else {
+				guides.WriteString("├─ ")
+			}
+		}
+
+		last = append(last[:min(item.Depth, len(last))], item.IsLast)
+		rows = append(rows, row[T]{IterItem: item, guides: guides.String()})
+		return true
+	})
+	return rows
+}
+
+// marker returns expand marker of node.
+func (m *Model[T]) marker(r row[T]) string {
+	switch {
+	case m.loading[r.ID]:
+		return "…"
+	case !r.HasChildren:
+		return " "
+	case r.IsCollapsed:
+		return "▸"
+	default:
+		return "▾"
+	}
+}
+
+// View renders the tree.
+func (m *Model[T]) View(vb tea.Viewbox) {
+	if m.filtering || m.filter.Value() != "" {
+		m.filter.View(vb.Row(vb.Height - 1))
+		vb = vb.MaxHeight(vb.Height - 1)
+	}
+
+	rows := m.rows()
+
+	// extra columns take their widths, tree takes the rest
+	widths := make([]int, len(m.Columns))
+	treeWidth := vb.Width
+	for i, col := range m.Columns {
+		widths[i] = col.Width
+		if widths[i] == 0 {
+			widths[i] = runewidth.StringWidth(col.Title)
+			for _, r := range rows {
+				widths[i] = max(widths[i], runewidth.StringWidth(col.Get(r.Value)))
+			}
+		}
+		treeWidth -= widths[i] + _gap
+	}
+
+	if len(m.Columns) > 0 {
+		vbh := vb.Row(0).Styled(m.Styles.Header)
+		vbh.WriteLine(m.Header)
+		x := treeWidth
+		for i, col := range m.Columns {
+			vbh.Sub(tea.Rectangle{Left: x + _gap, Width: widths[i]}).WriteLine(col.Title)
+			x += _gap + widths[i]
+		}
+		vb = vb.PaddingTop(1)
+	}
+
+	m.viewport.Height = vb.Height
+	m.viewport.ScrollIntoView(m.Tree.SelectedPos())
+	m.viewport.View(vb, func(vbRow tea.Viewbox, i int) {
+		if i >= len(rows) {
+			return
+		}
+
+		r := rows[i]
+		id := m.id + "-" + strconv.Itoa(r.ID)
+		vbRow = vbRow.Zone(id)
+		switch {
+		case r.IsSelected:
+			vbRow = vbRow.Styled(m.Styles.Selected)
+		case r.IsMatch:
+			vbRow = vbRow.Styled(m.Styles.Match)
+		case m.filter.Value() != "":
+			vbRow = vbRow.Styled(m.Styles.Ancestor)
+		}
+
+		vbTree := vbRow.MaxWidth(max(treeWidth, 0))
+		vbTree = vbTree.Styled(m.Styles.Guide).WriteLineX(r.guides)
+		vbMarker := vbTree.Sub(tea.Rectangle{Width: 1})
+		if r.HasChildren {
+			vbMarker = vbMarker.Zone(m.id + "-marker-" + strconv.Itoa(r.ID))
+		}
+		vbMarker.Styled(m.Styles.Marker).WriteLine(m.marker(r))
+
+		vbTitle := vbTree.PaddingLeft(2)
+		title := m.Title(r.Value)
+		if err, ok := m.errs[r.ID]; ok {
+			vbTitle = vbTitle.WriteLineX(title + " ")
+			title = err.Error()
+			vbTitle = vbTitle.Styled(m.Styles.Error)
+		}
+		vbTitle.WriteLine(runewidth.Truncate(title, vbTitle.Width, "…"))
+
+		x := treeWidth
+		for j, col := range m.Columns {
+			vbCell := vbRow.Sub(tea.Rectangle{Left: x + _gap, Width: widths[j]})
+			vbCell.WriteLine(runewidth.Truncate(col.Get(r.Value), widths[j], "…"))
+			x += _gap + widths[j]
+		}
+	})
+}
@@ -0,0 +1,161 @@
+package tree
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/rprtr258/assert"
+
+	"github.com/rprtr258/tea"
+	"github.com/rprtr258/tea/components/headless/hierachy"
+)
+
+type file struct {
+	Name string
+	Size string
+}
+
+func node(name string, children ...hierachy.Node[file]) hierachy.Node[file] {
+	return hierachy.Node[file]{Value: file{Name: name}, Children: children}
+}
+
+func render(m *Model[file], height, width int) []string {
+	vb := tea.NewViewbox(height, width)
+	m.View(vb)
+	return vb.Lines()
+}
+
+func TestView(t *testing.T) {
+	m := New(node("/",
+		node("cmd",
+			node("main.go"),
+		),
+		node("docs",
+			node("api",
+				node("index.md"),
+			),
+			node("README.md"),
+		),
+		node("go.mod"),
+	), func(f file) string { return f.Name })
+
+	assert.Equal(t, []string{
+		"▾ /",
+		"├─ ▾ cmd",
+		"│  └─   main.go",
+		"├─ ▾ docs",
+		"│  ├─ ▾ api",
+		"│  │  └─   index.md",
+		"│  └─   README.md",
+		"└─   go.mod",
+	}, render(&m, 8, 30))
+
+	// ancestors of matches are shown
+	m.SetFilter("READ")
+	assert.Equal(t, []string{
+		"▾ /",
+		"└─ ▾ docs",
+		"   └─   README.md",
+		"/ READ",
+	}, render(&m, 4, 30))
+
+	// collapse docs
+	m.SetFilter("")
+	for _, k := range []tea.KeyType{tea.KeyDown, tea.KeyDown, tea.KeyDown, tea.KeyLeft} {
+		m.Update(tea.MsgKey{Type: k}, func(...tea.Cmd) {})
+	}
+	assert.Equal(t, file{Name: "docs"}, m.Tree.Selected())
+	assert.Equal(t, []string{
+		"▾ /",
+		"├─ ▾ cmd",
+		"│  └─   main.go",
+		"├─ ▸ docs",
+		"└─   go.mod",
+	}, render(&m, 5, 30))
+}
+
+func TestFilteredNavigation(t *testing.T) {
+	m := New(node("Root",
+		node("a",
+			node("a1"),
+			node("xa2"),
+		),
+		node("b"),
+	), func(f file) string { return f.Name })
+	press := func(k tea.KeyType) {
+		m.Update(tea.MsgKey{Type: k}, func(...tea.Cmd) {})
+	}
+
+	m.SetFilter("x")
+	press(tea.KeyDown)
+	// hidden child is skipped
+	press(tea.KeyRight)
+	assert.Equal(t, file{Name: "xa2"}, m.Tree.Selected())
+	assert.Equal(t, 2, m.Tree.SelectedPos())
+
+	press(tea.KeyUp)
+	assert.Equal(t, file{Name: "a"}, m.Tree.Selected())
+	// shown children do not depend on collapsed nodes while filtering
+	press(tea.KeyLeft)
+	assert.Equal(t, file{Name: "Root"}, m.Tree.Selected())
+	assert.Equal(t, []string{
+		"▾ Root",
+		"└─ ▾ a",
+		"   └─   xa2",
+		"/ x",
+	}, render(&m, 4, 30))
+}
+
+func TestLazy(t *testing.T) {
+	var cmds []tea.Cmd
+	f := func(c ...tea.Cmd) { cmds = append(cmds, c...) }
+	run := func(m *Model[file]) {
+		for _, cmd := range cmds {
+			m.Update(cmd(), f)
+		}
+		cmds = nil
+	}
+
+	fail := true
+	m := New(hierachy.Node[file]{
+		Value:    file{Name: "/"},
+		Children: []hierachy.Node[file]{{Value: file{Name: "src"}, Lazy: true}, node("go.mod")},
+	}, func(f file) string { return f.Name })
+	m.Columns = []Column[file]{{Title: "Size", Get: func(f file) string { return f.Size }}}
+	m.Load = func(f file) ([]hierachy.Node[file], error) {
+		if fail {
+			return nil, errors.New("denied")
+		}
+		return []hierachy.Node[file]{
+			{Value: file{Name: "a.go", Size: "1K"}},
+			{Value: file{Name: "b.go", Size: "20K"}},
+		}, nil
+	}
+
+	m.Update(tea.MsgKey{Type: tea.KeyDown}, f)
+	m.Update(tea.MsgKey{Type: tea.KeyRight}, f)
+	assert.True(t, m.Loading())
+	assert.Equal(t, []string{
+		"                    Size",
+		"▾ /",
+		"├─ … src",
+		"└─   go.mod",
+	}, render(&m, 4, 24))
+
+	run(&m)
+	assert.False(t, m.Loading())
+	assert.Equal(t, "├─ ▸ src denied", render(&m, 4, 24)[2])
+
+	fail = false
+	m.Update(tea.MsgKey{Type: tea.KeyRight}, f)
+	run(&m)
+	assert.Equal(t, []string{
+		"                    Size",
+		"▾ /",
+		"├─ ▾ src",
+		"│  ├─   a.go        1K",
+		"│  └─   b.go        20K",
+		"└─   go.mod",
+	}, render(&m, 6, 24))
+	assert.Equal(t, file{Name: "src"}, m.Tree.Selected())
+}
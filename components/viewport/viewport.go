@@ -1,8 +1,11 @@
 package viewport
 
 import (
+	"iter"
+
 	"github.com/rprtr258/tea"
 	"github.com/rprtr258/tea/components/key"
+	"github.com/rprtr258/tea/components/search"
 )
 
 // KeyMap defines the keybindings for the viewport. Note that you don't
@@ -13,6 +16,9 @@ type KeyMap struct {
 	PageUp, PageDown         key.Binding
 	HalfPageUp, HalfPageDown key.Binding
 	Down, Up                 key.Binding
+	// Search opens search bar, SearchNext and SearchPrevious jump between
+	// matches after it is closed.
+	Search, SearchNext, SearchPrevious key.Binding
 }
 
 // DefaultKeyMap returns a set of pager-like default keybindings.
@@ -41,6 +47,18 @@ var DefaultKeyMap = KeyMap{
 		Keys: []string{"down", "j"},
 		Help: key.Help{"↓/j", "down"},
 	},
+	Search: key.Binding{
+		Keys: []string{"/"},
+		Help: key.Help{"/", "search"},
+	},
+	SearchNext: key.Binding{
+		Keys: []string{"n"},
+		Help: key.Help{"n", "next match"},
+	},
+	SearchPrevious: key.Binding{
+		Keys: []string{"N"},
+		Help: key.Help{"N", "previous match"},
+	},
 }
 
 // Model is Tea model for this viewport element.
@@ -59,6 +77,17 @@ type Model struct {
 
 	// YOffset is the vertical scroll position.
 	YOffset int
+
+	// SearchSource enumerates lines of content. Search is enabled only if
+	// it is set, it must be set again when content changes.
+	SearchSource iter.Seq2[int, string]
+	// SearchPrefixWidth returns width of prefix written before text of given
+	// line, e.g. of line number, so that matches are highlighted after it.
+	// Nil means lines have no prefix.
+	SearchPrefixWidth func(line int) int
+
+	// Search is search bar shown at the bottom of the viewport.
+	Search search.Model
 }
 
 // New create model with given width, height and default key mappings.
@@ -70,6 +99,7 @@ func New(width, height int) Model {
 		MouseWheelEnabled: true,
 		MouseWheelDelta:   3,
 		YOffset:           0,
+		Search:            search.New(),
 	}
 }
 
@@ -116,6 +146,42 @@ func (m *Model) GotoBottom(contentHeight int) {
 	m.SetYOffset(max(0, contentHeight-m.Height))
 }
 
+// visibleHeight returns number of content lines shown, excluding search
+// bar.
+func (m *Model) visibleHeight() int {
+	return max(0, m.Height-m.Search.Height())
+}
+
+// ScrollIntoView scrolls minimally so that given line is visible.
+func (m *Model) ScrollIntoView(line int) {
+	if line < m.YOffset {
+		m.SetYOffset(line)
+	} else if height := m.visibleHeight(); line >= m.YOffset+height {
+		m.SetYOffset(line - height + 1)
+	}
+}
+
+// gotoMatch scrolls to current search match.
+func (m *Model) gotoMatch() {
+	if match, ok := m.Search.Current(); ok {
+		m.ScrollIntoView(match.Line)
+	}
+}
+
+// updateSearch handles keys while search bar is open.
+func (m *Model) updateSearch(msg tea.Msg, f func(...tea.Cmd)) {
+	switch m.Search.Update(msg, f) {
+	case search.ActionFind:
+		m.Search.Find(m.SearchSource)
+		m.Search.Nearest(m.YOffset, 0)
+		m.gotoMatch()
+	case search.ActionMove:
+		m.gotoMatch()
+	default:
+		// text is not editable, so matches are not replaced
+	}
+}
+
 // Update handles standard message-based viewport updates.
 func (m *Model) Update(msg tea.Msg) {
 	switch msg := msg.(type) {
@@ -152,9 +218,53 @@ func (m *Model) Update(msg tea.Msg) {
 	}
 }
 
-// View renders the viewport into a string.
+// UpdateSearch handles messages as Update does, along with search keys:
+// typing in search bar while it is open, opening it and jumping between
+// matches. Commands of search bar are passed to f.
+func (m *Model) UpdateSearch(msg tea.Msg, f func(...tea.Cmd)) {
+	msgKey, ok := msg.(tea.MsgKey)
+	switch {
+	case !ok:
+	case m.Search.Active():
+		m.updateSearch(msgKey, f)
+		return
+	case key.Matches(msgKey, m.KeyMap.Search) && m.SearchSource != nil:
+		f(m.Search.Open(false)...)
+		m.Search.Find(m.SearchSource)
+		return
+	case key.Matches(msgKey, m.KeyMap.SearchNext):
+		m.Search.Next()
+		m.gotoMatch()
+		return
+	case key.Matches(msgKey, m.KeyMap.SearchPrevious):
+		m.Search.Previous()
+		m.gotoMatch()
+		return
+	}
+
+	m.Update(msg)
+}
+
+// View renders the viewport into a string. Search matches are highlighted
+// over lines rendered.
 func (m *Model) View(vb tea.Viewbox, lines func(tea.Viewbox, int)) {
-	for i := 0; i < min(m.Height, vb.Height); i++ {
-		lines(vb.Row(i), m.YOffset+i)
+	vb = vb.MaxHeight(m.Height)
+	if m.Search.Active() {
+		vbLines, vbSearch := vb.SplitY2(tea.Flex(1), tea.Fixed(m.Search.Height()))
+		m.Search.View(vbSearch)
+		vb = vbLines
+	}
+
+	for i := 0; i < vb.Height; i++ {
+		line := m.YOffset + i
+		lines(vb.Row(i), line)
+
+		prefix := 0
+		if m.SearchPrefixWidth != nil {
+			prefix = m.SearchPrefixWidth(line)
+		}
+		if prefix < vb.Width {
+			m.Search.Highlight(vb.Row(i).PaddingLeft(prefix), line)
+		}
 	}
 }
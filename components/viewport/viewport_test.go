@@ -0,0 +1,55 @@
+package viewport
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/rprtr258/assert"
+
+	"github.com/rprtr258/tea"
+)
+
+func TestSearchHighlight(t *testing.T) {
+	lines := []string{"foo bar", "bar"}
+
+	m := New(20, 3)
+	m.SearchSource = slices.All(lines)
+	m.SearchPrefixWidth = func(int) int { return 3 }
+	m.UpdateSearch(tea.MsgKey{Type: tea.KeyRunes, Runes: []rune("/")}, func(...tea.Cmd) {})
+	m.UpdateSearch(tea.MsgKey{Type: tea.KeyRunes, Runes: []rune("bar")}, func(...tea.Cmd) {})
+
+	vb := tea.NewViewbox(3, 20)
+	m.View(vb, func(vb tea.Viewbox, i int) {
+		if i < len(lines) {
+			vb.WriteLine(fmt.Sprintf("%2d %s", i+1, lines[i]))
+		}
+	})
+	// matches are highlighted after line numbers
+	rendered := string(vb.Render())
+	assert.True(t, strings.HasPrefix(rendered, " 1 foo \x1b[48;5;130mb"))
+	assert.True(t, strings.Contains(rendered, "\n 2 \x1b[48;5;58mb"))
+}
+
+func TestSearchNextAfterClose(t *testing.T) {
+	lines := []string{"bar", "", "", "", "", "bar", "", "", "bar", ""}
+	press := func(m *Model, k tea.MsgKey) {
+		m.UpdateSearch(k, func(...tea.Cmd) {})
+	}
+
+	m := New(20, 3)
+	m.SearchSource = slices.All(lines)
+	press(&m, tea.MsgKey{Type: tea.KeyRunes, Runes: []rune("/")})
+	press(&m, tea.MsgKey{Type: tea.KeyRunes, Runes: []rune("bar")})
+	press(&m, tea.MsgKey{Type: tea.KeyEsc})
+	assert.Equal(t, 0, m.YOffset)
+
+	// matches are kept after search bar is closed
+	press(&m, tea.MsgKey{Type: tea.KeyRunes, Runes: []rune("n")})
+	assert.Equal(t, 3, m.YOffset)
+	press(&m, tea.MsgKey{Type: tea.KeyRunes, Runes: []rune("n")})
+	assert.Equal(t, 6, m.YOffset)
+	press(&m, tea.MsgKey{Type: tea.KeyRunes, Runes: []rune("N")})
+	assert.Equal(t, 5, m.YOffset)
+}
@@ -3,8 +3,10 @@ package tea
 import (
 	"bytes"
 	"fmt"
+	"regexp"
+	"strings"
 
-	"github.com/mattn/go-runewidth"
+	"github.com/rivo/uniseg"
 	"github.com/rprtr258/fun"
 	"github.com/rprtr258/tea/styles"
 )
@@ -15,10 +17,16 @@ import (
 
 type framebuffer struct {
 	Height, Width int
-	B             []rune
+	// B holds grapheme cluster for each cell, cell following wide cluster
+	// holds empty string
+	B []string
 	// OPTIMIZE: store ranges of colors instead of color for every pixel
 	// backgrounds, foregrounds []string
 	styles []styles.Style
+	// zones tagged during the last render, see Viewbox.Zone
+	zones *[]zone
+	// overlays opened during the current render, see Viewbox.Overlay
+	layers *[]layer
 }
 
 // Viewbox is a view of the terminal to render to
@@ -28,13 +36,16 @@ type Viewbox struct {
 
 	fb    framebuffer
 	style styles.Style
+	// clip is absolute rectangle outside of which writes are discarded,
+	// nil means no clipping besides framebuffer borders
+	clip *Rectangle
 }
 
 // NewViewbox creates a new Framebuffer
 func NewViewbox(height, width int) Viewbox {
-	buf := make([]rune, height*width)
+	buf := make([]string, height*width)
 	for i := range buf {
-		buf[i] = ' '
+		buf[i] = " "
 	}
 
 	styless := make([]styles.Style, height*width)
@@ -50,6 +61,8 @@ func NewViewbox(height, width int) Viewbox {
 			// backgrounds: make([]string, height*width),
 			// foregrounds: make([]string, height*width),
 			styles: styless,
+			zones:  &[]zone{},
+			layers: &[]layer{},
 		},
 		Height: height,
 		Width:  width,
@@ -66,15 +79,49 @@ func (vb Viewbox) String() string {
 	)
 }
 
+// sgrRe matches style escape sequences, which can be written into cells
+// already rendered, e.g. cursor.
+var sgrRe = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+// Lines returns text of viewbox rows, e.g. to check rendered view in tests.
+// Styles, including SGR escape sequences written into cells, and trailing
+// spaces are stripped. Cells outside of framebuffer or clipped are spaces.
+func (vb Viewbox) Lines() []string {
+	res := make([]string, max(0, vb.Height))
+	for y := range res {
+		var sb strings.Builder
+		for x := 0; x < vb.Width; x++ {
+			i, ok := vb.index(y, x)
+			switch {
+			case !ok, x == 0 && vb.fb.B[i] == "":
+				// second half of wide cluster cut by left border is a space too
+				sb.WriteString(" ")
+			default:
+				sb.WriteString(vb.fb.B[i])
+			}
+		}
+		res[y] = strings.TrimRight(sgrRe.ReplaceAllString(sb.String(), ""), " ")
+	}
+	return res
+}
+
 func (vb *Viewbox) clear() {
 	for i := range vb.fb.B {
-		vb.fb.B[i] = ' '
+		vb.fb.B[i] = " "
 	}
 
 	for i := range vb.fb.styles {
 		vb.fb.styles[i] = styles.Style{}
 	}
 
+	if vb.fb.zones != nil {
+		*vb.fb.zones = (*vb.fb.zones)[:0]
+	}
+
+	if vb.fb.layers != nil {
+		*vb.fb.layers = (*vb.fb.layers)[:0]
+	}
+
 	vb.style = styles.Style{}
 }
 
@@ -98,8 +145,12 @@ func (vb Viewbox) Render() []byte {
 		// fullRow := vb.fb.B[y : y+vb.fb.Width]
 		for x := 0; x < vb.fb.Width; x++ {
 			i := y + x
+			if vb.fb.B[i] == "" {
+				// covered by previous wide cluster
+				continue
+			}
 
-			sb.WriteString(vb.fb.styles[i].Render(string([]rune{vb.fb.B[i]})))
+			sb.WriteString(vb.fb.styles[i].Render(vb.fb.B[i]))
 
 			// 	if vb.fb.backgrounds[y+x] != bg || vb.fb.foregrounds[y+x] != fg {
 			// 		bg = vb.fb.backgrounds[y+x]
@@ -121,6 +172,7 @@ func (vb Viewbox) Render() []byte {
 func (vb Viewbox) Row(y int) Viewbox {
 	return Viewbox{
 		fb:     vb.fb,
+		clip:   vb.clip,
 		Height: 1,
 		Width:  vb.Width,
 		Y:      y + vb.Y,
@@ -140,6 +192,7 @@ type PaddingOptions struct {
 func (vb Viewbox) Padding(opt PaddingOptions) Viewbox {
 	return Viewbox{
 		fb:     vb.fb,
+		clip:   vb.clip,
 		Height: vb.Height - opt.Top - opt.Bottom,
 		Width:  vb.Width - opt.Left - opt.Right,
 		Y:      vb.Y + opt.Top,
@@ -153,9 +206,15 @@ type Rectangle struct {
 	Height, Width int
 }
 
+func (r Rectangle) contains(y, x int) bool {
+	return y >= r.Top && y < r.Top+r.Height &&
+		x >= r.Left && x < r.Left+r.Width
+}
+
 func (vb Viewbox) Sub(rect Rectangle) Viewbox {
 	return Viewbox{
 		fb:     vb.fb,
+		clip:   vb.clip,
 		Height: fun.Clamp(fun.IF(rect.Height == 0, vb.Height, rect.Height), 0, vb.Height),
 		Width:  fun.Clamp(fun.IF(rect.Width == 0, vb.Width, rect.Width), 0, vb.Width),
 		Y:      vb.Y + fun.Clamp(rect.Top, 0, vb.Height-1),
@@ -206,6 +265,7 @@ func (vb Viewbox) MaxWidth(width int) Viewbox {
 func (vb Viewbox) Styled(style styles.Style) Viewbox {
 	vb = Viewbox{
 		fb:     vb.fb,
+		clip:   vb.clip,
 		Height: vb.Height,
 		Width:  vb.Width,
 		Y:      vb.Y,
@@ -215,23 +275,24 @@ func (vb Viewbox) Styled(style styles.Style) Viewbox {
 	if bg := style.GetBackground(); bg != nil {
 		for y := 0; y < vb.Height; y++ {
 			for x := 0; x < vb.Width; x++ {
-				i := (vb.Y+y)*vb.fb.Width + vb.X + x
-				vb.fb.styles[i] = vb.fb.styles[i].Background(bg)
+				if i, ok := vb.index(y, x); ok {
+					vb.fb.styles[i] = vb.fb.styles[i].Background(bg)
+				}
 			}
 		}
 	}
 	return vb
 }
 
-// WriteLine starting from x=offset without wrapping, returns end offset
+// WriteLine starting from x=offset without wrapping, returns end offset,
+// which is never greater than viewbox width, even if wide cluster is clipped
 func (vb Viewbox) WriteLine(line string) int {
 	x := 0
-	for _, c := range line {
-		if x >= vb.Width {
-			return x
-		}
-
-		x += vb.Set(0, x, c)
+	state := -1
+	for len(line) > 0 && x < vb.Width {
+		var cluster string
+		cluster, line, _, state = uniseg.FirstGraphemeClusterInString(line, state)
+		x = min(x+vb.SetCluster(0, x, cluster), vb.Width)
 	}
 	return x
 }
@@ -242,15 +303,23 @@ func (vb Viewbox) WriteLineX(line string) Viewbox {
 
 // WriteText starting from y, x with wrapping, returns end position
 func (vb Viewbox) WriteText(y, x int, text string) (int, int) {
-	for _, c := range text {
-		if c == '\n' { // TODO: remove
+	state := -1
+	for len(text) > 0 {
+		var cluster string
+		cluster, text, _, state = uniseg.FirstGraphemeClusterInString(text, state)
+		if cluster == "\n" || cluster == "\r\n" { // TODO: remove
 			x = 0
 			y++
 			continue
 		}
 
-		vb.Set(y, x, c)
-		x++
+		// wide cluster not fitting into the rest of line is moved to next one
+		if x+clusterWidth(cluster) > vb.Width && x > 0 {
+			x = 0
+			y++
+		}
+
+		x += vb.SetCluster(y, x, cluster)
 		if x >= vb.Width {
 			x = 0
 			y++
@@ -259,29 +328,122 @@ func (vb Viewbox) WriteText(y, x int, text string) (int, int) {
 	return y, x
 }
 
+// clusterWidth returns number of cells grapheme cluster takes, which is either
+// 1 or 2. Zero width clusters, like control characters, still take a cell.
+func clusterWidth(cluster string) int {
+	return max(1, min(2, uniseg.StringWidth(cluster)))
+}
+
 // Set writes a rune to the framebuffer in position relative to viewbox
 // 0 <= y < height, 0 <= x < width
 func (vb Viewbox) Set(y, x int, c rune) int {
+	return vb.SetCluster(y, x, string(c))
+}
+
+// SetCluster writes a grapheme cluster to the framebuffer in position relative
+// to viewbox, returns number of cells it takes.
+// Wide cluster not fitting into viewbox or clipped is replaced with space.
+// Wide clusters partially overwritten are replaced with spaces.
+// 0 <= y < height, 0 <= x < width
+func (vb Viewbox) SetCluster(y, x int, cluster string) int {
 	if y < 0 || y >= vb.Height || x < 0 || x >= vb.Width || len(vb.fb.B) == 0 {
 		return 0
 	}
 
-	if c == '\n' {
+	if cluster == "\n" || cluster == "\r\n" {
 		panic(fmt.Sprintf("unexpected newline: %d, %d", y, x))
 	}
 
-	i := (vb.Y+y)*vb.fb.Width + vb.X + x
-	vb.fb.B[i] = c
+	width := clusterWidth(cluster)
+
+	i, ok := vb.index(y, x)
+	if !ok {
+		return width
+	}
+
+	vb.fb.unwide(i)
+	vb.fb.B[i] = cluster
 	vb.fb.styles[i] = vb.style
 
-	if runewidth.RuneWidth(c) == 2 {
-		i++
-		vb.fb.B[i] = 0
-		vb.fb.styles[i] = styles.Style{}
-		return 2
+	if width == 2 {
+		j, ok := vb.index(y, x+1)
+		if !ok {
+			// second half is clipped, so cluster is not drawn at all
+			vb.fb.B[i] = " "
+			return width
+		}
+
+		vb.fb.unwide(j)
+		vb.fb.B[j] = ""
+		vb.fb.styles[j] = styles.Style{}
+	}
+
+	return width
+}
+
+// unwide replaces wide cluster covering cell i with spaces, so that cell can
+// be overwritten without leaving half of wide cluster
+func (fb framebuffer) unwide(i int) {
+	x := i % fb.Width
+	switch {
+	case fb.B[i] == "" && x > 0: // second half
+		fb.B[i-1] = " "
+		fb.B[i] = " "
+	case x+1 < fb.Width && fb.B[i+1] == "": // first half
+		fb.B[i+1] = " "
+	}
+}
+
+// index returns index of framebuffer cell at position relative to viewbox,
+// if it is inside viewbox and is not clipped
+func (vb Viewbox) index(y, x int) (int, bool) {
+	if y < 0 || y >= vb.Height || x < 0 || x >= vb.Width {
+		return 0, false
+	}
+
+	y, x = vb.Y+y, vb.X+x
+	if y < 0 || y >= vb.fb.Height || x < 0 || x >= vb.fb.Width {
+		return 0, false
+	}
+
+	if c := vb.clip; c != nil && !c.contains(y, x) {
+		return 0, false
+	}
+
+	return y*vb.fb.Width + x, true
+}
+
+// visibleRect returns absolute rectangle of viewbox part not clipped
+func (vb Viewbox) visibleRect() Rectangle {
+	top, left := max(vb.Y, 0), max(vb.X, 0)
+	bottom, right := min(vb.Y+vb.Height, vb.fb.Height), min(vb.X+vb.Width, vb.fb.Width)
+	if c := vb.clip; c != nil {
+		top, left = max(top, c.Top), max(left, c.Left)
+		bottom, right = min(bottom, c.Top+c.Height), min(right, c.Left+c.Width)
+	}
+	return Rectangle{
+		Top:    top,
+		Left:   left,
+		Height: max(0, bottom-top),
+		Width:  max(0, right-left),
 	}
+}
 
-	return 1
+// Virtual returns viewbox of given size, which might be bigger than current
+// one, scrolled by given offsets, so that its cell (yOffset, xOffset) is at
+// the top left corner of current viewbox. Writes outside of current viewbox
+// are discarded.
+func (vb Viewbox) Virtual(height, width, yOffset, xOffset int) Viewbox {
+	clip := vb.visibleRect()
+	return Viewbox{
+		fb:     vb.fb,
+		clip:   &clip,
+		Height: height,
+		Width:  width,
+		Y:      vb.Y - yOffset,
+		X:      vb.X - xOffset,
+		style:  vb.style,
+	}
 }
 
 func (vb Viewbox) Fill(c rune) {
@@ -307,113 +469,3 @@ func (vb Viewbox) Fill(c rune) {
 // 		vb.fb.foregrounds[(y+vb.Y)*vb.fb.Width+x] = foreground.Sequence(false)
 // 	}
 // }
-
-type Layout int
-
-func Auto() Layout { // TODO: implement
-	return Flex(1)
-}
-
-func Fixed(n int) Layout {
-	return Layout(-n)
-}
-
-func Flex(n int) Layout {
-	return Layout(n)
-}
-
-func EvalLayout(x int, ls ...Layout) []int {
-	res := make([]int, len(ls))
-	totalFlex := 0
-	flexes := 0
-	for i, l := range ls {
-		if l < 0 {
-			x += int(l)
-			res[i] = -int(l)
-		} else {
-			totalFlex += int(l)
-			flexes++
-		}
-	}
-	for i, l := range ls {
-		if l < 0 {
-			continue
-		}
-
-		if flexes == 1 {
-			res[i] = x
-			break
-		}
-
-		res[i] = int(float64(x*int(l)) / float64(totalFlex))
-		x -= res[i]
-		flexes--
-	}
-	return res
-}
-
-func (vb Viewbox) SplitY(ls ...Layout) []Viewbox {
-	heights := EvalLayout(vb.Height, ls...)
-	res := make([]Viewbox, len(heights))
-	y := vb.Y
-	for i, h := range heights {
-		res[i] = Viewbox{
-			fb:     vb.fb,
-			Height: h,
-			Width:  vb.Width,
-			Y:      y,
-			X:      vb.X,
-			style:  vb.style,
-		}
-		y += h
-	}
-	return res
-}
-
-func (vb Viewbox) SplitY2(l1, l2 Layout) (_, _ Viewbox) {
-	r := vb.SplitY(l1, l2)
-	return r[0], r[1]
-}
-
-func (vb Viewbox) SplitY3(l1, l2, l3 Layout) (_, _, _ Viewbox) {
-	r := vb.SplitY(l1, l2, l3)
-	return r[0], r[1], r[2]
-}
-
-func (vb Viewbox) SplitY4(l1, l2, l3, l4 Layout) (_, _, _, _ Viewbox) {
-	r := vb.SplitY(l1, l2, l3, l4)
-	return r[0], r[1], r[2], r[3]
-}
-
-func (vb Viewbox) SplitY5(l1, l2, l3, l4, l5 Layout) (_, _, _, _, _ Viewbox) {
-	r := vb.SplitY(l1, l2, l3, l4, l5)
-	return r[0], r[1], r[2], r[3], r[4]
-}
-
-func (vb Viewbox) SplitX(ls ...Layout) []Viewbox {
-	widths := EvalLayout(vb.Width, ls...)
-	res := make([]Viewbox, len(widths))
-	x := vb.X
-	for i, w := range widths {
-		res[i] = Viewbox{
-			fb:     vb.fb,
-			Height: vb.Height,
-			Width:  w,
-			Y:      vb.Y,
-			X:      x,
-			style:  vb.style,
-		}
-		x += w
-	}
-	return res
-}
-
-func (vb Viewbox) SplitX2(l1, l2 Layout) (_, _ Viewbox) {
-	r := vb.SplitX(l1, l2)
-	return r[0], r[1]
-}
-
-func (vb Viewbox) SplitX3(l1, l2, l3 Layout) (_, _, _ Viewbox) {
-	r := vb.SplitX(l1, l2, l3)
-	return r[0], r[1], r[2]
-}
@@ -0,0 +1,89 @@
+package tea
+
+import (
+	"testing"
+
+	"github.com/rprtr258/assert"
+
+	"github.com/rprtr258/tea/styles"
+)
+
+func TestVirtual(t *testing.T) {
+	vb := NewViewbox(4, 6)
+	vbVirtual := vb.Sub(Rectangle{Top: 1, Left: 1, Height: 2, Width: 4}).Virtual(5, 10, 1, 2)
+	for y := range vbVirtual.Height {
+		vbVirtual.Row(y).WriteLine("0123456789"[y:])
+	}
+	vbVirtual.Row(2).PaddingLeft(4).WriteLine("世界")
+
+	assert.Equal(t, []string{
+		"      ",
+		" 3456 ",
+		" 45世 ",
+		"      ",
+	}, rows(vb))
+}
+
+func TestWriteLineGraphemes(t *testing.T) {
+	for name, test := range map[string]struct {
+		line     string
+		expected string
+		width    int
+	}{
+		"ascii":    {line: "abc", expected: "abc   ", width: 3},
+		"combined": {line: "éa", expected: "éa    ", width: 2},
+		"zwj":      {line: "👩‍👩‍👧!", expected: "👩‍👩‍👧!   ", width: 3},
+		"flag":     {line: "🇯🇵🇫🇷", expected: "🇯🇵🇫🇷  ", width: 4},
+		"clipped":  {line: "abcde世", expected: "abcde ", width: 6},
+	} {
+		t.Run(name, func(t *testing.T) {
+			vb := NewViewbox(1, 6)
+			assert.Equal(t, test.width, vb.WriteLine(test.line))
+			assert.Equal(t, []string{test.expected}, rows(vb))
+		})
+	}
+}
+
+func TestSetOverwritesWide(t *testing.T) {
+	for name, test := range map[string]struct {
+		x        int
+		cluster  string
+		expected string
+	}{
+		"first half":    {x: 0, cluster: "a", expected: "a 界"},
+		"second half":   {x: 1, cluster: "a", expected: " a界"},
+		"wide shifted":  {x: 1, cluster: "日", expected: " 日 "},
+		"wide replaced": {x: 2, cluster: "日", expected: "世日"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			vb := NewViewbox(1, 4)
+			vb.WriteLine("世界")
+			vb.SetCluster(0, test.x, test.cluster)
+			assert.Equal(t, []string{test.expected}, rows(vb))
+		})
+	}
+}
+
+func TestWriteTextWrapsWide(t *testing.T) {
+	vb := NewViewbox(3, 3)
+	y, x := vb.WriteText(0, 0, "a世界\nb")
+	assert.Equal(t, 2, y)
+	assert.Equal(t, 1, x)
+	assert.Equal(t, []string{
+		"a世",
+		"界 ",
+		"b  ",
+	}, rows(vb))
+}
+
+func TestLines(t *testing.T) {
+	vb := NewViewbox(2, 6)
+	vb.Styled(styles.Style{}.Bold(true)).WriteLine("a世")
+	vb.Row(1).SetCluster(0, 2, styles.Style{}.Reverse(true).Render(" "))
+	assert.Equal(t, []string{"a世", ""}, vb.Lines())
+
+	// only text inside viewbox is returned
+	assert.Equal(t, []string{"世"}, vb.Sub(Rectangle{Height: 1, Left: 1, Width: 2}).Lines())
+	assert.Equal(t, []string{""}, vb.Sub(Rectangle{Height: 1, Left: 2, Width: 3}).Lines())
+	assert.Equal(t, []string{""}, vb.PaddingTop(1).Lines())
+}
@@ -0,0 +1,179 @@
+package tea
+
+import (
+	"github.com/rprtr258/tea/styles"
+)
+
+var _shadowColor = styles.BgColor("0")
+
+// Anchor computes absolute position of top left corner of overlay of given
+// size opened from parent rectangle.
+type Anchor func(screen, parent Rectangle, height, width int) (top, left int)
+
+// AnchorCenter places overlay at the center of parent viewbox.
+func AnchorCenter(_, parent Rectangle, height, width int) (int, int) {
+	return parent.Top + (parent.Height-height)/2, parent.Left + (parent.Width-width)/2
+}
+
+// AnchorBelow places overlay right under parent viewbox aligned to its left
+// border, or right above it if there is no space left below, e.g. dropdowns.
+func AnchorBelow(screen, parent Rectangle, height, _ int) (int, int) {
+	top := parent.Top + parent.Height
+	if top+height > screen.Height && parent.Top-height >= 0 {
+		top = parent.Top - height
+	}
+	return top, parent.Left
+}
+
+// AnchorAt places overlay at given position relative to parent viewbox, e.g.
+// right under the cursor.
+func AnchorAt(y, x int) Anchor {
+	return func(_, parent Rectangle, _, _ int) (int, int) {
+		return parent.Top + y, parent.Left + x
+	}
+}
+
+// OverlayOptions is overlay options
+type OverlayOptions struct {
+	// Height and Width of overlay, zero means size of the screen
+	Height, Width int
+	// Anchor to position overlay with, AnchorCenter by default
+	Anchor Anchor
+	// Shadow enables drop shadow to the right and bottom of overlay
+	Shadow bool
+	// Dim makes everything beneath overlay faint and overlay modal, so that
+	// zones beneath it no longer get mouse events
+	Dim bool
+}
+
+// layer is overlay waiting to be composited
+type layer struct {
+	rect   Rectangle
+	opts   OverlayOptions
+	render func(Viewbox)
+}
+
+// Overlay opens overlay positioned relative to viewbox. Render is called
+// after the main View has finished, so overlay is drawn above everything
+// drawn to the base layer, and overlays opened later are drawn above those
+// opened earlier. Overlay is clipped to screen borders.
+func (vb Viewbox) Overlay(opts OverlayOptions, render func(Viewbox)) {
+	if vb.fb.layers == nil {
+		return
+	}
+
+	height := min(vb.fb.Height, opts.Height)
+	if opts.Height == 0 {
+		height = vb.fb.Height
+	}
+	width := min(vb.fb.Width, opts.Width)
+	if opts.Width == 0 {
+		width = vb.fb.Width
+	}
+
+	anchor := opts.Anchor
+	if anchor == nil {
+		anchor = AnchorCenter
+	}
+
+	screen := Rectangle{
+		Height: vb.fb.Height,
+		Width:  vb.fb.Width,
+	}
+	parent := Rectangle{
+		Top:    vb.Y,
+		Left:   vb.X,
+		Height: vb.Height,
+		Width:  vb.Width,
+	}
+	top, left := anchor(screen, parent, height, width)
+
+	*vb.fb.layers = append(*vb.fb.layers, layer{
+		rect: Rectangle{
+			Top:    max(0, min(top, vb.fb.Height-height)),
+			Left:   max(0, min(left, vb.fb.Width-width)),
+			Height: height,
+			Width:  width,
+		},
+		opts:   opts,
+		render: render,
+	})
+}
+
+// restyle updates style of each cell of absolute rectangle clipped to screen
+func (fb framebuffer) restyle(rect Rectangle, f func(styles.Style) styles.Style) {
+	for y := max(0, rect.Top); y < min(fb.Height, rect.Top+rect.Height); y++ {
+		for x := max(0, rect.Left); x < min(fb.Width, rect.Left+rect.Width); x++ {
+			i := y*fb.Width + x
+			fb.styles[i] = f(fb.styles[i])
+		}
+	}
+}
+
+// cut removes wide clusters crossing left and right borders of absolute
+// rectangle, so that they don't shift content when rectangle is overdrawn
+func (fb framebuffer) cut(rect Rectangle) {
+	if rect.Width <= 0 {
+		return
+	}
+
+	for y := max(0, rect.Top); y < min(fb.Height, rect.Top+rect.Height); y++ {
+		fb.unwide(y*fb.Width + rect.Left)
+		fb.unwide(y*fb.Width + rect.Left + rect.Width - 1)
+	}
+}
+
+// compose renders opened overlays above current content of the framebuffer
+func (vb Viewbox) compose() {
+	if vb.fb.layers == nil {
+		return
+	}
+
+	screen := Rectangle{
+		Height: vb.fb.Height,
+		Width:  vb.fb.Width,
+	}
+	// overlays might open more overlays, so length is checked on each iteration
+	for i := 0; i < len(*vb.fb.layers); i++ {
+		l := (*vb.fb.layers)[i]
+
+		if l.opts.Dim {
+			vb.fb.restyle(screen, func(s styles.Style) styles.Style {
+				return s.Faint()
+			})
+			if vb.fb.zones != nil {
+				*vb.fb.zones = (*vb.fb.zones)[:0]
+			}
+		}
+
+		if l.opts.Shadow {
+			shadow := func(s styles.Style) styles.Style {
+				return s.Background(_shadowColor).Faint()
+			}
+			vb.fb.restyle(Rectangle{
+				Top:    l.rect.Top + l.rect.Height,
+				Left:   l.rect.Left + 1,
+				Height: 1,
+				Width:  l.rect.Width,
+			}, shadow)
+			vb.fb.restyle(Rectangle{
+				Top:    l.rect.Top + 1,
+				Left:   l.rect.Left + l.rect.Width,
+				Height: l.rect.Height - 1,
+				Width:  1,
+			}, shadow)
+		}
+
+		vb.fb.cut(l.rect)
+		vbLayer := Viewbox{
+			fb:     vb.fb,
+			Height: l.rect.Height,
+			Width:  l.rect.Width,
+			Y:      l.rect.Top,
+			X:      l.rect.Left,
+			style:  styles.Style{},
+		}
+		vbLayer.Fill(' ')
+		l.render(vbLayer)
+	}
+}
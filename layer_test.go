@@ -0,0 +1,157 @@
+package tea
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rprtr258/assert"
+)
+
+func rows(vb Viewbox) []string {
+	res := make([]string, vb.fb.Height)
+	for y := range res {
+		res[y] = strings.Join(vb.fb.B[y*vb.fb.Width:(y+1)*vb.fb.Width], "")
+	}
+	return res
+}
+
+func TestOverlay(t *testing.T) {
+	for name, test := range map[string]struct {
+		view     func(Viewbox)
+		expected []string
+	}{
+		"center": {
+			view: func(vb Viewbox) {
+				vb.Fill('.')
+				vb.Overlay(OverlayOptions{Height: 1, Width: 4}, func(vb Viewbox) {
+					vb.WriteLine("ok")
+				})
+			},
+			expected: []string{
+				"........",
+				"..ok  ..",
+				"........",
+			},
+		},
+		"at clipped": {
+			view: func(vb Viewbox) {
+				vb.Fill('.')
+				vb.Overlay(OverlayOptions{Height: 2, Width: 3, Anchor: AnchorAt(2, 7)}, func(vb Viewbox) {
+					vb.Fill('#')
+				})
+			},
+			expected: []string{
+				"........",
+				".....###",
+				".....###",
+			},
+		},
+		"below flips above": {
+			view: func(vb Viewbox) {
+				vb.Fill('.')
+				vb.Row(2).MaxWidth(2).Overlay(OverlayOptions{Height: 2, Width: 2, Anchor: AnchorBelow}, func(vb Viewbox) {
+					vb.Fill('v')
+				})
+			},
+			expected: []string{
+				"vv......",
+				"vv......",
+				"........",
+			},
+		},
+		"nested overlays": {
+			view: func(vb Viewbox) {
+				vb.Overlay(OverlayOptions{Height: 3, Width: 4, Anchor: AnchorAt(0, 0)}, func(vb Viewbox) {
+					vb.Fill('1')
+					vb.Overlay(OverlayOptions{Height: 1, Width: 2, Anchor: AnchorAt(1, 3)}, func(vb Viewbox) {
+						vb.Fill('2')
+					})
+				})
+				vb.Fill('.')
+			},
+			expected: []string{
+				"1111....",
+				"11122...",
+				"1111....",
+			},
+		},
+		"cut wide characters": {
+			view: func(vb Viewbox) {
+				vb.Row(1).WriteLine("世界世界")
+				vb.Overlay(OverlayOptions{Height: 1, Width: 2, Anchor: AnchorAt(1, 3)}, func(vb Viewbox) {
+					vb.Fill('#')
+				})
+			},
+			expected: []string{
+				"        ",
+				"世 ## 界",
+				"        ",
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			vb := NewViewbox(3, 8)
+			test.view(vb)
+			vb.compose()
+			assert.Equal(t, test.expected, rows(vb))
+		})
+	}
+}
+
+func TestOverlayShadowAndDim(t *testing.T) {
+	vb := NewViewbox(4, 6)
+	vb.Overlay(OverlayOptions{Height: 2, Width: 3, Anchor: AnchorAt(0, 0), Shadow: true, Dim: true}, func(Viewbox) {})
+	vb.compose()
+
+	faint := make([]string, vb.fb.Height)
+	shadow := make([]string, vb.fb.Height)
+	for y := range vb.fb.Height {
+		for x := range vb.fb.Width {
+			s := vb.fb.styles[y*vb.fb.Width+x]
+			faint[y] += map[bool]string{true: "f", false: "."}[s.GetFaint()]
+			shadow[y] += map[bool]string{true: "s", false: "."}[s.GetBackground() != nil]
+		}
+	}
+	assert.Equal(t, []string{
+		"...fff",
+		"...fff",
+		"ffffff",
+		"ffffff",
+	}, faint)
+	assert.Equal(t, []string{
+		"......",
+		"...s..",
+		".sss..",
+		"......",
+	}, shadow)
+}
+
+func TestOverlayDimMasksZones(t *testing.T) {
+	vb := NewViewbox(4, 6)
+	vb.Zone("base")
+	vb.Overlay(OverlayOptions{Height: 2, Width: 3, Anchor: AnchorAt(0, 0)}, func(vb Viewbox) {
+		vb.Zone("popup")
+	})
+	vb.Overlay(OverlayOptions{Height: 1, Width: 2, Anchor: AnchorAt(2, 2), Dim: true}, func(vb Viewbox) {
+		vb.Zone("modal")
+	})
+	vb.compose()
+
+	_, ok := vb.fb.zoneAt(0, 0)
+	assert.False(t, ok)
+	_, ok = vb.fb.zoneAt(3, 5)
+	assert.False(t, ok)
+	z, ok := vb.fb.zoneAt(2, 3)
+	assert.True(t, ok)
+	assert.Equal(t, "modal", z.id)
+}
+
+func TestClearResetsOverlays(t *testing.T) {
+	vb := NewViewbox(1, 1)
+	vb.Overlay(OverlayOptions{}, func(vb Viewbox) {
+		vb.Fill('#')
+	})
+	vb.clear()
+	vb.compose()
+	assert.Equal(t, []string{" "}, rows(vb))
+}
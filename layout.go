@@ -0,0 +1,401 @@
+package tea
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/rprtr258/fun"
+)
+
+type layoutKind int
+
+const (
+	layoutFixed layoutKind = iota
+	layoutFlex
+	layoutPercent
+	layoutAuto
+	// layoutMeasured is auto item which content is measured already
+	layoutMeasured
+)
+
+// Layout is a size constraint of single item placed along an axis.
+//
+// NOTE: Layout used to be an int, negative for fixed and positive for flex
+// items. Construct it using Fixed, Flex, Percent and Auto instead of
+// converting integers.
+type Layout struct {
+	kind     layoutKind
+	value    int
+	min, max int // max == 0 means unbounded
+	// content is rendered to measure auto item
+	content func(Viewbox)
+}
+
+// Fixed item always takes exactly n cells, unless there is not enough space.
+func Fixed(n int) Layout {
+	return Layout{kind: layoutFixed, value: n}
+}
+
+// Flex item takes space left after other items, shared between flex items
+// proportionally to their weights.
+func Flex(n int) Layout {
+	return Layout{kind: layoutFlex, value: n}
+}
+
+// Percent item takes given percentage of available space.
+func Percent(p int) Layout {
+	return Layout{kind: layoutPercent, value: p}
+}
+
+// Auto item takes size of its content set by Content and shrinks first if
+// there is not enough space. Auto item without content takes space left, as
+// Flex(1) does.
+func Auto() Layout {
+	return Layout{kind: layoutAuto, value: 1}
+}
+
+// Content sets content of auto item, e.g. Auto().Content(m.View). Content is
+// rendered to a scratch viewbox of the size being split when viewbox is
+// split, and item takes extent of cells written along the split axis.
+func (l Layout) Content(view func(Viewbox)) Layout {
+	l.content = view
+	return l
+}
+
+// Min sets minimal size of item, it is not shrinked nor flexed below it.
+func (l Layout) Min(n int) Layout {
+	l.min = n
+	return l
+}
+
+// Max sets maximal size of item, it is not flexed above it.
+func (l Layout) Max(n int) Layout {
+	l.max = n
+	return l
+}
+
+func (l Layout) clamp(n int) int {
+	if l.max > 0 {
+		n = min(n, l.max)
+	}
+	return max(n, l.min, 0)
+}
+
+// Justify tells where to put space left after laying out all items, which
+// happens only when there are no flex items or they all reached max size.
+type Justify int
+
+const (
+	JustifyStart Justify = iota
+	JustifyEnd
+	JustifyCenter
+	JustifySpaceBetween
+)
+
+// Flow is options of laying out items along an axis
+type Flow struct {
+	// Gap is number of cells between adjacent items
+	Gap     int
+	Justify Justify
+}
+
+// distribute splits total between items proportionally to weights using
+// largest remainder method, so that sizes sum up exactly to total and
+// leftover cells go to items with largest fractions, earlier items first.
+func distribute(total int, weights []int) []int {
+	res := make([]int, len(weights))
+	sum := 0
+	for _, w := range weights {
+		sum += w
+	}
+	if sum == 0 || total <= 0 {
+		return res
+	}
+
+	left := total
+	remainders := make([]int, len(weights))
+	for i, w := range weights {
+		res[i] = total * w / sum
+		remainders[i] = total * w % sum
+		left -= res[i]
+	}
+
+	order := make([]int, len(weights))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(i, j int) int {
+		return cmp.Compare(remainders[j], remainders[i])
+	})
+	for _, i := range order[:left] {
+		res[i]++
+	}
+	return res
+}
+
+// flex shares pool between flex items, freezing items violating their min or
+// max constraints and sharing what is left between the rest.
+func flex(pool int, ls []Layout, idx []int, res []int) {
+	active := slices.Clone(idx)
+	for len(active) > 0 {
+		weights := make([]int, len(active))
+		for k, i := range active {
+			weights[k] = ls[i].value
+		}
+		shares := distribute(max(pool, 0), weights)
+
+		frozen := false
+		rest := active[:0:0]
+		for k, i := range active {
+			if size := ls[i].clamp(shares[k]); size != shares[k] {
+				res[i] = size
+				pool -= size
+				frozen = true
+			} else {
+				rest = append(rest, i)
+			}
+		}
+
+		if !frozen {
+			for k, i := range active {
+				res[i] = shares[k]
+			}
+			return
+		}
+		active = rest
+	}
+}
+
+// Eval computes offsets and sizes of items laid out on total cells.
+func (f Flow) Eval(total int, ls ...Layout) (offsets, sizes []int) {
+	sizes = make([]int, len(ls))
+	offsets = make([]int, len(ls))
+	if len(ls) == 0 {
+		return offsets, sizes
+	}
+
+	available := max(0, total-f.Gap*(len(ls)-1))
+
+	var flexes, shrinkables []int
+	used := 0
+	for i, l := range ls {
+		switch l.kind {
+		case layoutFixed:
+			sizes[i] = l.value
+		case layoutPercent:
+			sizes[i] = l.clamp(available * l.value / 100)
+			shrinkables = append(shrinkables, i)
+		case layoutMeasured:
+			sizes[i] = l.clamp(l.value)
+			shrinkables = append(shrinkables, i)
+		case layoutFlex, layoutAuto:
+			flexes = append(flexes, i)
+			continue
+		}
+		used += sizes[i]
+	}
+
+	// shrink auto and percent items proportionally to their sizes
+	if overflow := used - available; overflow > 0 && len(shrinkables) > 0 {
+		weights := make([]int, len(shrinkables))
+		for k, i := range shrinkables {
+			weights[k] = sizes[i] - ls[i].min
+		}
+		for k, cut := range distribute(overflow, weights) {
+			i := shrinkables[k]
+			cut = min(cut, weights[k])
+			sizes[i] -= cut
+			used -= cut
+		}
+	}
+
+	flex(available-used, ls, flexes, sizes)
+
+	// items not fitting are clipped from the end
+	left := available
+	for i := range sizes {
+		sizes[i] = min(sizes[i], left)
+		left -= sizes[i]
+	}
+
+	// extra cells added after each item
+	extra := make([]int, len(ls))
+	switch f.Justify {
+	case JustifyStart:
+	case JustifyEnd:
+		offsets[0] = left
+	case JustifyCenter:
+		offsets[0] = left / 2
+	case JustifySpaceBetween:
+		if len(ls) > 1 {
+			copy(extra, distribute(left, slices.Repeat([]int{1}, len(ls)-1)))
+		}
+	}
+
+	for i := 1; i < len(ls); i++ {
+		offsets[i] = offsets[i-1] + sizes[i-1] + f.Gap + extra[i-1]
+	}
+	return offsets, sizes
+}
+
+// EvalLayout computes sizes of items laid out on x cells without gaps.
+func EvalLayout(x int, ls ...Layout) []int {
+	_, sizes := Flow{}.Eval(x, ls...)
+	return sizes
+}
+
+// measure returns layouts with contents of auto items measured along given
+// axis, rendering them to scratch viewbox of given size.
+func measure(height, width int, vertical bool, ls []Layout) []Layout {
+	if !slices.ContainsFunc(ls, func(l Layout) bool { return l.kind == layoutAuto && l.content != nil }) {
+		return ls
+	}
+
+	// single scratch viewbox is reused for all items
+	vb := NewViewbox(height, width)
+	res := slices.Clone(ls)
+	for i, l := range res {
+		if l.kind != layoutAuto || l.content == nil {
+			continue
+		}
+
+		vb.clear()
+		l.content(vb)
+		extent := 0
+		for y := 0; y < height; y++ {
+			for x := 0; x < width; x++ {
+				if vb.fb.B[y*width+x] != " " {
+					extent = max(extent, fun.IF(vertical, y, x)+1)
+				}
+			}
+		}
+		res[i].kind, res[i].value = layoutMeasured, extent
+	}
+	return res
+}
+
+// FlowY splits viewbox into rows laid out according to flow options.
+func (vb Viewbox) FlowY(flow Flow, ls ...Layout) []Viewbox {
+	offsets, heights := flow.Eval(vb.Height, measure(vb.Height, vb.Width, true, ls)...)
+	res := make([]Viewbox, len(heights))
+	for i, h := range heights {
+		res[i] = Viewbox{
+			fb:     vb.fb,
+			clip:   vb.clip,
+			Height: h,
+			Width:  vb.Width,
+			Y:      vb.Y + offsets[i],
+			X:      vb.X,
+			style:  vb.style,
+		}
+	}
+	return res
+}
+
+// FlowX splits viewbox into columns laid out according to flow options.
+func (vb Viewbox) FlowX(flow Flow, ls ...Layout) []Viewbox {
+	offsets, widths := flow.Eval(vb.Width, measure(vb.Height, vb.Width, false, ls)...)
+	res := make([]Viewbox, len(widths))
+	for i, w := range widths {
+		res[i] = Viewbox{
+			fb:     vb.fb,
+			clip:   vb.clip,
+			Height: vb.Height,
+			Width:  w,
+			Y:      vb.Y,
+			X:      vb.X + offsets[i],
+			style:  vb.style,
+		}
+	}
+	return res
+}
+
+// Grid splits viewbox into rows, then each row into columns, so that cell
+// (y, x) is at res[y][x]. Cells might be split further to get nested grids.
+func (vb Viewbox) Grid(rowsFlow Flow, rows []Layout, colsFlow Flow, cols []Layout) [][]Viewbox {
+	res := make([][]Viewbox, len(rows))
+	for y, vbRow := range vb.FlowY(rowsFlow, rows...) {
+		res[y] = vbRow.FlowX(colsFlow, cols...)
+	}
+	return res
+}
+
+// WrapX lays out items in rows of given height, moving items to next row
+// when they don't fit into current one, like words in text. Row width is
+// decided using items minimal sizes, then remaining space of the row is laid
+// out according to flow. Items wider than viewbox take the whole row.
+func (vb Viewbox) WrapX(flow Flow, rowHeight, rowGap int, ls ...Layout) []Viewbox {
+	ls = measure(rowHeight, vb.Width, false, ls)
+	basis := func(l Layout) int {
+		switch l.kind {
+		case layoutFixed, layoutMeasured:
+			return l.clamp(l.value)
+		case layoutPercent:
+			return l.clamp(vb.Width * l.value / 100)
+		default:
+			return l.min
+		}
+	}
+
+	res := make([]Viewbox, 0, len(ls))
+	y := 0
+	for start := 0; start < len(ls); {
+		end, width := start+1, basis(ls[start])
+		for end < len(ls) && width+flow.Gap+basis(ls[end]) <= vb.Width {
+			width += flow.Gap + basis(ls[end])
+			end++
+		}
+
+		vbRow := Viewbox{
+			fb:     vb.fb,
+			clip:   vb.clip,
+			Height: max(0, min(rowHeight, vb.Height-y)),
+			Width:  vb.Width,
+			Y:      vb.Y + min(y, vb.Height),
+			X:      vb.X,
+			style:  vb.style,
+		}
+		res = append(res, vbRow.FlowX(flow, ls[start:end]...)...)
+		y += rowHeight + rowGap
+		start = end
+	}
+	return res
+}
+
+func (vb Viewbox) SplitY(ls ...Layout) []Viewbox {
+	return vb.FlowY(Flow{}, ls...)
+}
+
+func (vb Viewbox) SplitY2(l1, l2 Layout) (_, _ Viewbox) {
+	r := vb.SplitY(l1, l2)
+	return r[0], r[1]
+}
+
+func (vb Viewbox) SplitY3(l1, l2, l3 Layout) (_, _, _ Viewbox) {
+	r := vb.SplitY(l1, l2, l3)
+	return r[0], r[1], r[2]
+}
+
+func (vb Viewbox) SplitY4(l1, l2, l3, l4 Layout) (_, _, _, _ Viewbox) {
+	r := vb.SplitY(l1, l2, l3, l4)
+	return r[0], r[1], r[2], r[3]
+}
+
+func (vb Viewbox) SplitY5(l1, l2, l3, l4, l5 Layout) (_, _, _, _, _ Viewbox) {
+	r := vb.SplitY(l1, l2, l3, l4, l5)
+	return r[0], r[1], r[2], r[3], r[4]
+}
+
+func (vb Viewbox) SplitX(ls ...Layout) []Viewbox {
+	return vb.FlowX(Flow{}, ls...)
+}
+
+func (vb Viewbox) SplitX2(l1, l2 Layout) (_, _ Viewbox) {
+	r := vb.SplitX(l1, l2)
+	return r[0], r[1]
+}
+
+func (vb Viewbox) SplitX3(l1, l2, l3 Layout) (_, _, _ Viewbox) {
+	r := vb.SplitX(l1, l2, l3)
+	return r[0], r[1], r[2]
+}
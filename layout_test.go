@@ -0,0 +1,156 @@
+package tea
+
+import (
+	"testing"
+
+	"github.com/rprtr258/assert"
+)
+
+func TestEvalLayout(t *testing.T) {
+	for name, test := range map[string]struct {
+		total    int
+		layouts  []Layout
+		expected []int
+	}{
+		"fixed and flex": {
+			total:    10,
+			layouts:  []Layout{Fixed(3), Flex(1)},
+			expected: []int{3, 7},
+		},
+		"fair rounding": {
+			total:    10,
+			layouts:  []Layout{Flex(1), Flex(1), Flex(1)},
+			expected: []int{4, 3, 3},
+		},
+		"largest remainder": {
+			total:    10,
+			layouts:  []Layout{Flex(1), Flex(2), Flex(4)},
+			expected: []int{1, 3, 6},
+		},
+		"percent": {
+			total:    20,
+			layouts:  []Layout{Percent(25), Flex(1), Percent(50)},
+			expected: []int{5, 5, 10},
+		},
+		"flex max": {
+			total:    20,
+			layouts:  []Layout{Flex(1).Max(4), Flex(1), Flex(1)},
+			expected: []int{4, 8, 8},
+		},
+		"flex min": {
+			total:    10,
+			layouts:  []Layout{Flex(1).Min(6), Flex(1), Flex(1)},
+			expected: []int{6, 2, 2},
+		},
+		"auto without content": {
+			total:    10,
+			layouts:  []Layout{Auto(), Flex(1)},
+			expected: []int{5, 5},
+		},
+		"clipped from the end": {
+			total:    10,
+			layouts:  []Layout{Fixed(6), Fixed(6), Flex(1)},
+			expected: []int{6, 4, 0},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, EvalLayout(test.total, test.layouts...))
+		})
+	}
+}
+
+func TestAuto(t *testing.T) {
+	text := func(lines ...string) func(Viewbox) {
+		return func(vb Viewbox) {
+			for i, line := range lines {
+				vb.Row(i).WriteLine(line)
+			}
+		}
+	}
+	widths := func(vbs []Viewbox) []int {
+		res := make([]int, len(vbs))
+		for i, vb := range vbs {
+			res[i] = vb.Width
+		}
+		return res
+	}
+
+	vb := NewViewbox(2, 10)
+	assert.Equal(t, []int{3, 7}, widths(vb.SplitX(Auto().Content(text("ab", "abc")), Flex(1))))
+	assert.Equal(t, []int{2, 3}, widths(vb.SplitX(Fixed(2), Auto().Content(text("abc")))))
+	// each auto item is measured on its own
+	assert.Equal(t, []int{5, 2, 3}, widths(vb.SplitX(Auto().Content(text("abcde")), Auto().Content(text("ab")), Flex(1))))
+	// auto items shrink first
+	assert.Equal(t, []int{4, 3, 3}, widths(vb.SplitX(Fixed(4), Auto().Content(text("abcdef")), Auto().Content(text("abc")).Min(2))))
+
+	top, bottom := NewViewbox(5, 4).SplitY2(Auto().Content(text("a", "b")), Flex(1))
+	assert.Equal(t, [2]int{2, 3}, [2]int{top.Height, bottom.Height})
+}
+
+func TestFlowEval(t *testing.T) {
+	for name, test := range map[string]struct {
+		flow    Flow
+		offsets []int
+		sizes   []int
+	}{
+		"gap": {
+			flow:    Flow{Gap: 1},
+			offsets: []int{0, 3, 6},
+			sizes:   []int{2, 2, 2},
+		},
+		"end": {
+			flow:    Flow{Justify: JustifyEnd},
+			offsets: []int{4, 6, 8},
+			sizes:   []int{2, 2, 2},
+		},
+		"center": {
+			flow:    Flow{Justify: JustifyCenter},
+			offsets: []int{2, 4, 6},
+			sizes:   []int{2, 2, 2},
+		},
+		"space between": {
+			flow:    Flow{Gap: 1, Justify: JustifySpaceBetween},
+			offsets: []int{0, 4, 8},
+			sizes:   []int{2, 2, 2},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			offsets, sizes := test.flow.Eval(10, Fixed(2), Fixed(2), Fixed(2))
+			assert.Equal(t, test.offsets, offsets)
+			assert.Equal(t, test.sizes, sizes)
+		})
+	}
+}
+
+func TestGrid(t *testing.T) {
+	vb := NewViewbox(5, 9)
+	cells := vb.Grid(
+		Flow{Gap: 1}, []Layout{Fixed(1), Flex(1)},
+		Flow{Gap: 1}, []Layout{Flex(1), Flex(1), Flex(1)},
+	)
+	for y, row := range cells {
+		for x, cell := range row {
+			cell.Fill([]rune("abc")[x] + rune(y)*3)
+		}
+	}
+	assert.Equal(t, []string{
+		"aaa bb cc",
+		"         ",
+		"ddd ee ff",
+		"ddd ee ff",
+		"ddd ee ff",
+	}, rows(vb))
+}
+
+func TestWrapX(t *testing.T) {
+	vb := NewViewbox(4, 7)
+	for i, vbItem := range vb.WrapX(Flow{Gap: 1}, 1, 1, Fixed(3), Fixed(2), Fixed(4), Flex(1).Min(1), Fixed(9)) {
+		vbItem.Fill(rune('1' + i))
+	}
+	assert.Equal(t, []string{
+		"111 22 ",
+		"       ",
+		"3333 44",
+		"       ",
+	}, rows(vb))
+}
@@ -46,6 +46,10 @@ const (
 	MouseWheelUp
 	MouseWheelDown
 	MouseMotion
+	// MouseZoneEnter and MouseZoneLeave are only reported in MsgMouseZone,
+	// when mouse pointer enters or leaves a zone.
+	MouseZoneEnter
+	MouseZoneLeave
 )
 
 var mouseEventTypes = map[MouseEventType]string{
@@ -57,6 +61,8 @@ var mouseEventTypes = map[MouseEventType]string{
 	MouseWheelUp:   "wheel up",
 	MouseWheelDown: "wheel down",
 	MouseMotion:    "motion",
+	MouseZoneEnter: "zone enter",
+	MouseZoneLeave: "zone leave",
 }
 
 // Parse X10-encoded mouse events; the simplest kind. The last release of X10
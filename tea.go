@@ -166,6 +166,7 @@ type Program[M Model] struct {
 	restoreOutput func() error
 	renderer      *Renderer
 	vb            Viewbox
+	zones         zoneTracker
 
 	// where to read inputs from, this will usually be os.Stdin.
 	input        io.Reader
@@ -391,6 +392,10 @@ func (p *Program[M]) eventLoop(model M, cmds chan []Cmd) (M, error) {
 				continue
 			}
 
+			// Mouse events over zones from the last render are also
+			// delivered as zone messages.
+			var zoneMsgs []Msg
+
 			// Handle special internal messages.
 			switch msg := msg.(type) {
 			case MsgQuit:
@@ -428,6 +433,9 @@ func (p *Program[M]) eventLoop(model M, cmds chan []Cmd) (M, error) {
 				// TODO: move to renderer
 			case MsgWindowSize:
 				p.vb = NewViewbox(msg.Height, msg.Width)
+
+			case MsgMouse:
+				zoneMsgs = p.zones.translate(p.vb.fb, MouseEvent(msg))
 			}
 
 			p.renderer.handleMessages(msg)
@@ -435,14 +443,26 @@ func (p *Program[M]) eventLoop(model M, cmds chan []Cmd) (M, error) {
 			model.Update(msg, func(c ...Cmd) {
 				cmds <- c
 			}) // run update, process command (if any)
-			p.renderer.reset()
-			p.vb.clear()
-			model.View(p.vb)
-			p.renderer.Write(p.vb.Render())
+			for _, zoneMsg := range zoneMsgs {
+				model.Update(zoneMsg, func(c ...Cmd) {
+					cmds <- c
+				})
+			}
+			p.render(model)
 		}
 	}
 }
 
+// render draws model view and overlays opened by it, then passes the frame
+// to the renderer.
+func (p *Program[M]) render(model M) {
+	p.renderer.reset()
+	p.vb.clear()
+	model.View(p.vb)
+	p.vb.compose()
+	p.renderer.Write(p.vb.Render())
+}
+
 // Run initializes the program and runs its event loops, blocking until it gets
 // terminated by either [Program.Quit], [Program.Kill], or its signal handler.
 // Returns the final model.
@@ -523,10 +543,7 @@ func (p *Program[M]) Run() (M, error) {
 	p.renderer.start()
 
 	// Render the initial view.
-	p.renderer.reset()
-	p.vb.clear()
-	p.model.View(p.vb)
-	p.renderer.Write(p.vb.Render())
+	p.render(p.model)
 
 	// Subscribe to user input.
 	if p.input != nil {
@@ -548,10 +565,7 @@ func (p *Program[M]) Run() (M, error) {
 		err = ErrProgramKilled
 	} else {
 		// Ensure we rendered the final state of the model.
-		p.renderer.reset()
-		p.vb.clear()
-		p.model.View(p.vb)
-		p.renderer.Write(p.vb.Render())
+		p.render(p.model)
 	}
 
 	// Tear down.
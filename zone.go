@@ -0,0 +1,100 @@
+package tea
+
+// MsgMouseZone is sent to Update in addition to MsgMouse when a mouse event
+// lands inside a zone registered with Viewbox.Zone during the last View. X and
+// Y are relative to the top left corner of the zone. Event is the original
+// mouse event with absolute coordinates, or MouseZoneEnter/MouseZoneLeave
+// when the pointer crosses the zone border.
+type MsgMouseZone struct {
+	ID    string
+	X, Y  int
+	Event MouseEvent
+}
+
+// zone is an absolutely positioned rectangle of the framebuffer tagged by ID.
+type zone struct {
+	id   string
+	rect Rectangle
+	// absolute position of viewbox origin, differs from rect corner if viewbox
+	// is partially clipped
+	y, x int
+}
+
+func (z zone) msg(e MouseEvent) MsgMouseZone {
+	return MsgMouseZone{
+		ID:    z.id,
+		X:     e.X - z.x,
+		Y:     e.Y - z.y,
+		Event: e,
+	}
+}
+
+// Zone tags area covered by viewbox with given id, so mouse events over it
+// are delivered as MsgMouseZone. Zones are reset on every render, so tag them
+// in View. If zones overlap, the one tagged last wins, so child zones should
+// be tagged after their parents.
+func (vb Viewbox) Zone(id string) Viewbox {
+	if vb.fb.zones == nil {
+		return vb
+	}
+
+	rect := vb.visibleRect()
+	if rect.Height == 0 || rect.Width == 0 {
+		return vb
+	}
+
+	*vb.fb.zones = append(*vb.fb.zones, zone{
+		id:   id,
+		rect: rect,
+		y:    vb.Y,
+		x:    vb.X,
+	})
+	return vb
+}
+
+// zoneAt returns topmost zone containing given absolute position
+func (fb framebuffer) zoneAt(y, x int) (zone, bool) {
+	if fb.zones == nil {
+		return zone{}, false
+	}
+
+	zones := *fb.zones
+	for i := len(zones) - 1; i >= 0; i-- {
+		if zones[i].rect.contains(y, x) {
+			return zones[i], true
+		}
+	}
+	return zone{}, false
+}
+
+// zoneTracker remembers zone under the mouse pointer to report hover changes.
+type zoneTracker struct {
+	hovered *zone
+}
+
+// translate mouse event into zone messages: leave and enter events if the
+// pointer moved to another zone, then event itself for the zone under pointer.
+func (t *zoneTracker) translate(fb framebuffer, e MouseEvent) []Msg {
+	var msgs []Msg
+
+	z, ok := fb.zoneAt(e.Y, e.X)
+	if t.hovered != nil && (!ok || t.hovered.id != z.id) {
+		leave := e
+		leave.Type = MouseZoneLeave
+		msgs = append(msgs, t.hovered.msg(leave))
+		t.hovered = nil
+	}
+
+	if !ok {
+		return msgs
+	}
+
+	if t.hovered == nil {
+		enter := e
+		enter.Type = MouseZoneEnter
+		msgs = append(msgs, z.msg(enter))
+	}
+	t.hovered = &z
+
+	return append(msgs, z.msg(e))
+}
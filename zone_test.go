@@ -0,0 +1,71 @@
+package tea
+
+import (
+	"testing"
+
+	"github.com/rprtr258/assert"
+)
+
+func TestViewboxZone(t *testing.T) {
+	vb := NewViewbox(10, 20)
+	top, bottom := vb.SplitY2(Fixed(3), Flex(1))
+	top.Zone("top")
+	bottom.Zone("bottom")
+	bottom.Sub(Rectangle{Top: 2, Left: 5, Height: 2, Width: 4}).Zone("button")
+
+	for name, test := range map[string]struct {
+		y, x int
+		id   string
+		ok   bool
+	}{
+		"top":           {y: 0, x: 0, id: "top", ok: true},
+		"bottom":        {y: 3, x: 19, id: "bottom", ok: true},
+		"nested":        {y: 5, x: 5, id: "button", ok: true},
+		"nested edge":   {y: 6, x: 8, id: "button", ok: true},
+		"nested border": {y: 7, x: 5, id: "bottom", ok: true},
+		"outside":       {y: 10, x: 0, ok: false},
+	} {
+		t.Run(name, func(t *testing.T) {
+			z, ok := vb.fb.zoneAt(test.y, test.x)
+			assert.Equal(t, test.ok, ok)
+			assert.Equal(t, test.id, z.id)
+		})
+	}
+
+	vb.clear()
+	_, ok := vb.fb.zoneAt(0, 0)
+	assert.False(t, ok)
+}
+
+func TestZoneTracker(t *testing.T) {
+	vb := NewViewbox(5, 10)
+	left, right := vb.SplitX2(Fixed(5), Flex(1))
+	left.Zone("left")
+	right.PaddingTop(1).Zone("right")
+
+	var tracker zoneTracker
+	event := func(y, x int, typ MouseEventType) MouseEvent {
+		return MouseEvent{X: x, Y: y, Type: typ}
+	}
+
+	assert.Equal(t, []Msg{
+		MsgMouseZone{ID: "left", X: 1, Y: 2, Event: event(2, 1, MouseZoneEnter)},
+		MsgMouseZone{ID: "left", X: 1, Y: 2, Event: event(2, 1, MouseMotion)},
+	}, tracker.translate(vb.fb, event(2, 1, MouseMotion)))
+
+	assert.Equal(t, []Msg{
+		MsgMouseZone{ID: "left", X: 4, Y: 2, Event: event(2, 4, MouseLeft)},
+	}, tracker.translate(vb.fb, event(2, 4, MouseLeft)))
+
+	assert.Equal(t, []Msg{
+		MsgMouseZone{ID: "left", X: 5, Y: 3, Event: event(3, 5, MouseZoneLeave)},
+		MsgMouseZone{ID: "right", X: 0, Y: 2, Event: event(3, 5, MouseZoneEnter)},
+		MsgMouseZone{ID: "right", X: 0, Y: 2, Event: event(3, 5, MouseMotion)},
+	}, tracker.translate(vb.fb, event(3, 5, MouseMotion)))
+
+	assert.Equal(t, []Msg{
+		MsgMouseZone{ID: "right", X: 1, Y: -1, Event: event(0, 6, MouseZoneLeave)},
+	}, tracker.translate(vb.fb, event(0, 6, MouseMotion)))
+
+	assert.Equal(t, nil, tracker.translate(vb.fb, event(0, 7, MouseMotion)))
+}